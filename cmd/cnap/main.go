@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 
 	"github.com/cnap-tech/cli/internal/cmd"
+	"github.com/cnap-tech/cli/internal/cmdutil"
 )
 
 func main() {
@@ -17,8 +20,23 @@ func run() int {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
-	if err := cmd.Execute(ctx); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+	return exitCode(ctx, cmd.Execute(ctx), os.Stderr)
+}
+
+// exitCode maps the error from running a command in ctx to the process
+// exit status, printing it to stderr unless it has its own status or came
+// from an interrupt.
+func exitCode(ctx context.Context, err error, stderr io.Writer) int {
+	if err != nil {
+		var exitErr *cmdutil.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.Code
+		}
+		// Like a shell after SIGINT, exit with status 130 without an error.
+		if cmdutil.Interrupted(ctx, err) {
+			return 130
+		}
+		fmt.Fprintf(stderr, "Error: %s\n", err)
 		return 1
 	}
 	return 0
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/cmd"
+	"github.com/cnap-tech/cli/internal/cmdtest"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/prompt"
+)
+
+func TestExitCodeInterruptedCommand(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The request is in flight when Ctrl-C arrives.
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+	}))
+
+	args := os.Args
+	t.Cleanup(func() { os.Args = args })
+	os.Args = []string{"cnap", "workspaces", "list"}
+
+	var stderr bytes.Buffer
+	if code := exitCode(ctx, cmd.Execute(ctx), &stderr); code != 130 {
+		t.Errorf("exit code = %d, want 130", code)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("stderr = %q, want nothing", stderr.String())
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		err        error
+		want       int
+		wantStderr string
+	}{
+		{"success", context.Background(), nil, 0, ""},
+		{"aborted prompt", context.Background(), fmt.Errorf("select install: %w", prompt.ErrAborted), 130, ""},
+		{"interrupted prompt", cancelled, fmt.Errorf("select install: %w", prompt.ErrInterrupted), 130, ""},
+		{"cancelled request", cancelled, fmt.Errorf("list installs: %w", context.Canceled), 130, ""},
+		// A cancellation that didn't come from our context isn't an interrupt.
+		{"stray cancel", context.Background(), context.Canceled, 1, "Error: context canceled\n"},
+		// Running out of --timeout is an error, not an interrupt.
+		{"timeout", expired, fmt.Errorf("timed out after 1s (--timeout): %w", context.DeadlineExceeded), 1, "Error: timed out after 1s (--timeout): context deadline exceeded\n"},
+		{"exit error", context.Background(), &cmdutil.ExitError{Code: 3}, 3, ""},
+		{"error", context.Background(), errors.New("boom"), 1, "Error: boom\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			if got := exitCode(tt.ctx, tt.err, &stderr); got != tt.want {
+				t.Errorf("exit code = %d, want %d", got, tt.want)
+			}
+			if stderr.String() != tt.wantStderr {
+				t.Errorf("stderr = %q, want %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
@@ -22,6 +22,25 @@ const (
 	BearerAuthScopes = "BearerAuth.Scopes"
 )
 
+// Defines values for ContainerStatusState.
+const (
+	Running    ContainerStatusState = "running"
+	Terminated ContainerStatusState = "terminated"
+	Waiting    ContainerStatusState = "waiting"
+)
+
+// Defines values for EventSource.
+const (
+	Kubernetes EventSource = "kubernetes"
+	Workflow   EventSource = "workflow"
+)
+
+// Defines values for EventType.
+const (
+	Normal  EventType = "Normal"
+	Warning EventType = "Warning"
+)
+
 // Defines values for KaasInfoStatus.
 const (
 	DEGRADED     KaasInfoStatus = "DEGRADED"
@@ -116,6 +135,20 @@ type ClusterList struct {
 	Pagination Pagination `json:"pagination"`
 }
 
+// ContainerStatus defines model for ContainerStatus.
+type ContainerStatus struct {
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+
+	// Reason Reason for a waiting or terminated state
+	Reason       *string              `json:"reason"`
+	RestartCount int                  `json:"restart_count"`
+	State        ContainerStatusState `json:"state"`
+}
+
+// ContainerStatusState defines model for ContainerStatus.State.
+type ContainerStatusState string
+
 // CreatedToken defines model for CreatedToken.
 type CreatedToken struct {
 	Id   string `json:"id"`
@@ -125,6 +158,22 @@ type CreatedToken struct {
 	Token string `json:"token"`
 }
 
+// CurrentUser defines model for CurrentUser.
+type CurrentUser struct {
+	Email string `json:"email"`
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+
+	// Token The PAT used for this request, null for session tokens and JWTs
+	Token *struct {
+		// ExpiresAt Unix timestamp (seconds), null if never
+		ExpiresAt *float32 `json:"expires_at"`
+		Id        string   `json:"id"`
+		Name      string   `json:"name"`
+		Scopes    []string `json:"scopes"`
+	} `json:"token"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	Error struct {
@@ -138,6 +187,30 @@ type Error struct {
 	} `json:"error"`
 }
 
+// Event defines model for Event.
+type Event struct {
+	// Count Number of occurrences
+	Count   int    `json:"count"`
+	Message string `json:"message"`
+
+	// Object Kind and name of the involved object
+	Object string `json:"object"`
+	Reason string `json:"reason"`
+
+	// Source Whether the event came from the cluster or the CNAP workflow
+	Source EventSource `json:"source"`
+
+	// Timestamp Unix timestamp (seconds) of the last occurrence
+	Timestamp float64   `json:"timestamp"`
+	Type      EventType `json:"type"`
+}
+
+// EventSource Whether the event came from the cluster or the CNAP workflow
+type EventSource string
+
+// EventType defines model for Event.Type.
+type EventType string
+
 // HelmSource defines model for HelmSource.
 type HelmSource struct {
 	Chart    HelmSourceChart          `json:"chart"`
@@ -154,6 +227,18 @@ type HelmSourceChart struct {
 	TargetRevision string  `json:"target_revision"`
 }
 
+// HelmSourceChartFiles defines model for HelmSourceChartFiles.
+type HelmSourceChartFiles struct {
+	Chart HelmSourceChart `json:"chart"`
+
+	// DefaultValues The chart's default values.yaml
+	DefaultValues        string `json:"default_values"`
+	TemplateHelmSourceId string `json:"template_helm_source_id"`
+
+	// ValuesSchema The chart's values.schema.json, or null if it ships none
+	ValuesSchema *map[string]*interface{} `json:"values_schema"`
+}
+
 // Install defines model for Install.
 type Install struct {
 	ClusterId string `json:"cluster_id"`
@@ -163,6 +248,18 @@ type Install struct {
 	Id        string  `json:"id"`
 	Name      *string `json:"name"`
 
+	// Namespace Kubernetes namespace the install is deployed to (null until first deployed)
+	Namespace *string `json:"namespace"`
+
+	// Overrides Per-install value overrides by helm source, as set with PATCH /v1/installs/{id}/overrides
+	Overrides *[]struct {
+		// TemplateHelmSourceId Helm source ID
+		TemplateHelmSourceId string `json:"template_helm_source_id"`
+
+		// Values Override values
+		Values map[string]*interface{} `json:"values"`
+	} `json:"overrides"`
+
 	// ProductId Set for product-based installs
 	ProductId *string `json:"product_id"`
 
@@ -177,6 +274,18 @@ type InstallList struct {
 	Pagination Pagination `json:"pagination"`
 }
 
+// InstallStatus defines model for InstallStatus.
+type InstallStatus struct {
+	// Health Application health as reported by ArgoCD
+	Health string `json:"health"`
+
+	// Message Details when the install is failing or progressing
+	Message *string `json:"message"`
+
+	// Phase Phase of the latest install workflow
+	Phase string `json:"phase"`
+}
+
 // KaasInfo Present if cluster is KaaS-managed
 type KaasInfo struct {
 	Status        KaasInfoStatus `json:"status"`
@@ -196,8 +305,22 @@ type Pagination struct {
 
 // Pod defines model for Pod.
 type Pod struct {
-	Containers []string `json:"containers"`
-	Name       string   `json:"name"`
+	// ContainerStatuses Per-container status, in the same order as containers
+	ContainerStatuses *[]ContainerStatus `json:"container_statuses,omitempty"`
+	Containers        []string           `json:"containers"`
+
+	// CreatedAt Unix timestamp (seconds)
+	CreatedAt *float32 `json:"created_at,omitempty"`
+	Name      string   `json:"name"`
+
+	// NodeName Node the pod is scheduled on (null while pending)
+	NodeName *string `json:"node_name"`
+
+	// Phase Pod phase (Pending, Running, Succeeded, Failed, Unknown)
+	Phase *string `json:"phase,omitempty"`
+
+	// Ready Whether all containers are ready
+	Ready *bool `json:"ready,omitempty"`
 }
 
 // Product defines model for Product.
@@ -331,6 +454,15 @@ type PatchV1ClustersIdJSONBody struct {
 	RegionId *string `json:"region_id,omitempty"`
 }
 
+// GetV1ClustersIdEventsParams defines parameters for GetV1ClustersIdEvents.
+type GetV1ClustersIdEventsParams struct {
+	// Since Only return events newer than this Unix timestamp (seconds)
+	Since *float64 `form:"since,omitempty" json:"since,omitempty"`
+
+	// Limit Maximum number of events (most recent first)
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
 // GetV1InstallsParams defines parameters for GetV1Installs.
 type GetV1InstallsParams struct {
 	// Cursor Pagination cursor from previous response
@@ -338,10 +470,22 @@ type GetV1InstallsParams struct {
 
 	// Limit Items per page (1-100)
 	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// ProductId Only installs of this product
+	ProductId *string `form:"product_id,omitempty" json:"product_id,omitempty"`
+
+	// ClusterId Only installs on this cluster
+	ClusterId *string `form:"cluster_id,omitempty" json:"cluster_id,omitempty"`
+
+	// RegionId Only installs on clusters in this region
+	RegionId *string `form:"region_id,omitempty" json:"region_id,omitempty"`
 }
 
 // PostV1InstallsJSONBody defines parameters for PostV1Installs.
 type PostV1InstallsJSONBody struct {
+	// Name Install name (unnamed if omitted)
+	Name *string `json:"name,omitempty"`
+
 	// Overrides Initial value overrides per helm source
 	Overrides *[]struct {
 		// TemplateHelmSourceId Helm source ID to override
@@ -387,6 +531,20 @@ type PostV1InstallsStandaloneParams struct {
 	IdempotencyKey *string `json:"idempotency-key,omitempty"`
 }
 
+// PatchV1InstallsIdJSONBody defines parameters for PatchV1InstallsId.
+type PatchV1InstallsIdJSONBody struct {
+	Name string `json:"name"`
+}
+
+// GetV1InstallsIdEventsParams defines parameters for GetV1InstallsIdEvents.
+type GetV1InstallsIdEventsParams struct {
+	// Since Only return events newer than this Unix timestamp (seconds)
+	Since *float64 `form:"since,omitempty" json:"since,omitempty"`
+
+	// Limit Maximum number of events (most recent first)
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
 // GetV1InstallsIdLogsParams defines parameters for GetV1InstallsIdLogs.
 type GetV1InstallsIdLogsParams struct {
 	// Pod Pod name (all pods if omitted)
@@ -395,6 +553,9 @@ type GetV1InstallsIdLogsParams struct {
 	// Container Container name
 	Container *string `form:"container,omitempty" json:"container,omitempty"`
 
+	// Selector Label selector for the pods to stream (e.g. app=web,tier!=cache). Ignored when pod is set
+	Selector *string `form:"selector,omitempty" json:"selector,omitempty"`
+
 	// Follow Follow log output
 	Follow *bool `form:"follow,omitempty" json:"follow,omitempty"`
 
@@ -656,6 +817,9 @@ type PostV1InstallsJSONRequestBody PostV1InstallsJSONBody
 // PostV1InstallsStandaloneJSONRequestBody defines body for PostV1InstallsStandalone for application/json ContentType.
 type PostV1InstallsStandaloneJSONRequestBody PostV1InstallsStandaloneJSONBody
 
+// PatchV1InstallsIdJSONRequestBody defines body for PatchV1InstallsId for application/json ContentType.
+type PatchV1InstallsIdJSONRequestBody PatchV1InstallsIdJSONBody
+
 // PatchV1InstallsIdOverridesJSONRequestBody defines body for PatchV1InstallsIdOverrides for application/json ContentType.
 type PatchV1InstallsIdOverridesJSONRequestBody PatchV1InstallsIdOverridesJSONBody
 
@@ -770,6 +934,9 @@ type ClientInterface interface {
 
 	PatchV1ClustersId(ctx context.Context, id string, body PatchV1ClustersIdJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// GetV1ClustersIdEvents request
+	GetV1ClustersIdEvents(ctx context.Context, id string, params *GetV1ClustersIdEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// GetV1ClustersIdKubeconfig request
 	GetV1ClustersIdKubeconfig(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -792,6 +959,14 @@ type ClientInterface interface {
 	// GetV1InstallsId request
 	GetV1InstallsId(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// PatchV1InstallsIdWithBody request with any body
+	PatchV1InstallsIdWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PatchV1InstallsId(ctx context.Context, id string, body PatchV1InstallsIdJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetV1InstallsIdEvents request
+	GetV1InstallsIdEvents(ctx context.Context, id string, params *GetV1InstallsIdEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// GetV1InstallsIdLogs request
 	GetV1InstallsIdLogs(ctx context.Context, id string, params *GetV1InstallsIdLogsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -803,6 +978,9 @@ type ClientInterface interface {
 	// GetV1InstallsIdPods request
 	GetV1InstallsIdPods(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// GetV1InstallsIdStatus request
+	GetV1InstallsIdStatus(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// PatchV1InstallsIdValuesWithBody request with any body
 	PatchV1InstallsIdValuesWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -865,6 +1043,12 @@ type ClientInterface interface {
 
 	PatchV1TemplatesId(ctx context.Context, id string, body PatchV1TemplatesIdJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 
+	// GetV1TemplatesIdHelmSourcesSourceIdChart request
+	GetV1TemplatesIdHelmSourcesSourceIdChart(ctx context.Context, id string, sourceId string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetV1User request
+	GetV1User(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// GetV1UserTokens request
 	GetV1UserTokens(ctx context.Context, params *GetV1UserTokensParams, reqEditors ...RequestEditorFn) (*http.Response, error)
 
@@ -943,6 +1127,18 @@ func (c *Client) PatchV1ClustersId(ctx context.Context, id string, body PatchV1C
 	return c.Client.Do(req)
 }
 
+func (c *Client) GetV1ClustersIdEvents(ctx context.Context, id string, params *GetV1ClustersIdEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetV1ClustersIdEventsRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) GetV1ClustersIdKubeconfig(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewGetV1ClustersIdKubeconfigRequest(c.Server, id)
 	if err != nil {
@@ -1039,6 +1235,42 @@ func (c *Client) GetV1InstallsId(ctx context.Context, id string, reqEditors ...R
 	return c.Client.Do(req)
 }
 
+func (c *Client) PatchV1InstallsIdWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPatchV1InstallsIdRequestWithBody(c.Server, id, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PatchV1InstallsId(ctx context.Context, id string, body PatchV1InstallsIdJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPatchV1InstallsIdRequest(c.Server, id, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetV1InstallsIdEvents(ctx context.Context, id string, params *GetV1InstallsIdEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetV1InstallsIdEventsRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) GetV1InstallsIdLogs(ctx context.Context, id string, params *GetV1InstallsIdLogsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewGetV1InstallsIdLogsRequest(c.Server, id, params)
 	if err != nil {
@@ -1087,6 +1319,18 @@ func (c *Client) GetV1InstallsIdPods(ctx context.Context, id string, reqEditors
 	return c.Client.Do(req)
 }
 
+func (c *Client) GetV1InstallsIdStatus(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetV1InstallsIdStatusRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) PatchV1InstallsIdValuesWithBody(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewPatchV1InstallsIdValuesRequestWithBody(c.Server, id, contentType, body)
 	if err != nil {
@@ -1363,6 +1607,30 @@ func (c *Client) PatchV1TemplatesId(ctx context.Context, id string, body PatchV1
 	return c.Client.Do(req)
 }
 
+func (c *Client) GetV1TemplatesIdHelmSourcesSourceIdChart(ctx context.Context, id string, sourceId string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetV1TemplatesIdHelmSourcesSourceIdChartRequest(c.Server, id, sourceId)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetV1User(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetV1UserRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 func (c *Client) GetV1UserTokens(ctx context.Context, params *GetV1UserTokensParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
 	req, err := NewGetV1UserTokensRequest(c.Server, params)
 	if err != nil {
@@ -1615,6 +1883,78 @@ func NewPatchV1ClustersIdRequestWithBody(server string, id string, contentType s
 	return req, nil
 }
 
+// NewGetV1ClustersIdEventsRequest generates requests for GetV1ClustersIdEvents
+func NewGetV1ClustersIdEventsRequest(server string, id string, params *GetV1ClustersIdEventsParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/clusters/%s/events", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Since != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "since", runtime.ParamLocationQuery, *params.Since); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewGetV1ClustersIdKubeconfigRequest generates requests for GetV1ClustersIdKubeconfig
 func NewGetV1ClustersIdKubeconfigRequest(server string, id string) (*http.Request, error) {
 	var err error
@@ -1703,6 +2043,54 @@ func NewGetV1InstallsRequest(server string, params *GetV1InstallsParams) (*http.
 
 		}
 
+		if params.ProductId != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "product_id", runtime.ParamLocationQuery, *params.ProductId); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.ClusterId != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "cluster_id", runtime.ParamLocationQuery, *params.ClusterId); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.RegionId != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "region_id", runtime.ParamLocationQuery, *params.RegionId); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
 		queryURL.RawQuery = queryValues.Encode()
 	}
 
@@ -1892,13 +2280,132 @@ func NewGetV1InstallsIdRequest(server string, id string) (*http.Request, error)
 	return req, nil
 }
 
-// NewGetV1InstallsIdLogsRequest generates requests for GetV1InstallsIdLogs
-func NewGetV1InstallsIdLogsRequest(server string, id string, params *GetV1InstallsIdLogsParams) (*http.Request, error) {
-	var err error
-
-	var pathParam0 string
-
-	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+// NewPatchV1InstallsIdRequest calls the generic PatchV1InstallsId builder with application/json body
+func NewPatchV1InstallsIdRequest(server string, id string, body PatchV1InstallsIdJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPatchV1InstallsIdRequestWithBody(server, id, "application/json", bodyReader)
+}
+
+// NewPatchV1InstallsIdRequestWithBody generates requests for PatchV1InstallsId with any type of body
+func NewPatchV1InstallsIdRequestWithBody(server string, id string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/installs/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewGetV1InstallsIdEventsRequest generates requests for GetV1InstallsIdEvents
+func NewGetV1InstallsIdEventsRequest(server string, id string, params *GetV1InstallsIdEventsParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/installs/%s/events", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Since != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "since", runtime.ParamLocationQuery, *params.Since); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetV1InstallsIdLogsRequest generates requests for GetV1InstallsIdLogs
+func NewGetV1InstallsIdLogsRequest(server string, id string, params *GetV1InstallsIdLogsParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
 	if err != nil {
 		return nil, err
 	}
@@ -1953,6 +2460,22 @@ func NewGetV1InstallsIdLogsRequest(server string, id string, params *GetV1Instal
 
 		}
 
+		if params.Selector != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "selector", runtime.ParamLocationQuery, *params.Selector); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
 		if params.Follow != nil {
 
 			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "follow", runtime.ParamLocationQuery, *params.Follow); err != nil {
@@ -2093,6 +2616,40 @@ func NewGetV1InstallsIdPodsRequest(server string, id string) (*http.Request, err
 	return req, nil
 }
 
+// NewGetV1InstallsIdStatusRequest generates requests for GetV1InstallsIdStatus
+func NewGetV1InstallsIdStatusRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/installs/%s/status", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewPatchV1InstallsIdValuesRequest calls the generic PatchV1InstallsIdValues builder with application/json body
 func NewPatchV1InstallsIdValuesRequest(server string, id string, body PatchV1InstallsIdValuesJSONRequestBody) (*http.Request, error) {
 	var bodyReader io.Reader
@@ -2824,6 +3381,74 @@ func NewPatchV1TemplatesIdRequestWithBody(server string, id string, contentType
 	return req, nil
 }
 
+// NewGetV1TemplatesIdHelmSourcesSourceIdChartRequest generates requests for GetV1TemplatesIdHelmSourcesSourceIdChart
+func NewGetV1TemplatesIdHelmSourcesSourceIdChartRequest(server string, id string, sourceId string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "source_id", runtime.ParamLocationPath, sourceId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/templates/%s/helm-sources/%s/chart", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetV1UserRequest generates requests for GetV1User
+func NewGetV1UserRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/user")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewGetV1UserTokensRequest generates requests for GetV1UserTokens
 func NewGetV1UserTokensRequest(server string, params *GetV1UserTokensParams) (*http.Request, error) {
 	var err error
@@ -3119,6 +3744,9 @@ type ClientWithResponsesInterface interface {
 
 	PatchV1ClustersIdWithResponse(ctx context.Context, id string, body PatchV1ClustersIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PatchV1ClustersIdResponse, error)
 
+	// GetV1ClustersIdEventsWithResponse request
+	GetV1ClustersIdEventsWithResponse(ctx context.Context, id string, params *GetV1ClustersIdEventsParams, reqEditors ...RequestEditorFn) (*GetV1ClustersIdEventsResponse, error)
+
 	// GetV1ClustersIdKubeconfigWithResponse request
 	GetV1ClustersIdKubeconfigWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetV1ClustersIdKubeconfigResponse, error)
 
@@ -3141,6 +3769,14 @@ type ClientWithResponsesInterface interface {
 	// GetV1InstallsIdWithResponse request
 	GetV1InstallsIdWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetV1InstallsIdResponse, error)
 
+	// PatchV1InstallsIdWithBodyWithResponse request with any body
+	PatchV1InstallsIdWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PatchV1InstallsIdResponse, error)
+
+	PatchV1InstallsIdWithResponse(ctx context.Context, id string, body PatchV1InstallsIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PatchV1InstallsIdResponse, error)
+
+	// GetV1InstallsIdEventsWithResponse request
+	GetV1InstallsIdEventsWithResponse(ctx context.Context, id string, params *GetV1InstallsIdEventsParams, reqEditors ...RequestEditorFn) (*GetV1InstallsIdEventsResponse, error)
+
 	// GetV1InstallsIdLogsWithResponse request
 	GetV1InstallsIdLogsWithResponse(ctx context.Context, id string, params *GetV1InstallsIdLogsParams, reqEditors ...RequestEditorFn) (*GetV1InstallsIdLogsResponse, error)
 
@@ -3152,6 +3788,9 @@ type ClientWithResponsesInterface interface {
 	// GetV1InstallsIdPodsWithResponse request
 	GetV1InstallsIdPodsWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetV1InstallsIdPodsResponse, error)
 
+	// GetV1InstallsIdStatusWithResponse request
+	GetV1InstallsIdStatusWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetV1InstallsIdStatusResponse, error)
+
 	// PatchV1InstallsIdValuesWithBodyWithResponse request with any body
 	PatchV1InstallsIdValuesWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PatchV1InstallsIdValuesResponse, error)
 
@@ -3214,6 +3853,12 @@ type ClientWithResponsesInterface interface {
 
 	PatchV1TemplatesIdWithResponse(ctx context.Context, id string, body PatchV1TemplatesIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PatchV1TemplatesIdResponse, error)
 
+	// GetV1TemplatesIdHelmSourcesSourceIdChartWithResponse request
+	GetV1TemplatesIdHelmSourcesSourceIdChartWithResponse(ctx context.Context, id string, sourceId string, reqEditors ...RequestEditorFn) (*GetV1TemplatesIdHelmSourcesSourceIdChartResponse, error)
+
+	// GetV1UserWithResponse request
+	GetV1UserWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetV1UserResponse, error)
+
 	// GetV1UserTokensWithResponse request
 	GetV1UserTokensWithResponse(ctx context.Context, params *GetV1UserTokensParams, reqEditors ...RequestEditorFn) (*GetV1UserTokensResponse, error)
 
@@ -3332,6 +3977,33 @@ func (r PatchV1ClustersIdResponse) StatusCode() int {
 	return 0
 }
 
+type GetV1ClustersIdEventsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data []Event `json:"data"`
+	}
+	JSON401 *Error
+	JSON404 *Error
+	JSON422 *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetV1ClustersIdEventsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetV1ClustersIdEventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type GetV1ClustersIdKubeconfigResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -3365,6 +4037,7 @@ type GetV1InstallsResponse struct {
 	JSON200      *InstallList
 	JSON401      *Error
 	JSON403      *Error
+	JSON422      *Error
 }
 
 // Status returns HTTPResponse.Status
@@ -3478,9 +4151,62 @@ func (r GetV1InstallsIdResponse) StatusCode() int {
 	return 0
 }
 
-type GetV1InstallsIdLogsResponse struct {
+type PatchV1InstallsIdResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
+	JSON200      *Install
+	JSON401      *Error
+	JSON403      *Error
+	JSON404      *Error
+	JSON422      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PatchV1InstallsIdResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PatchV1InstallsIdResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetV1InstallsIdEventsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data []Event `json:"data"`
+	}
+	JSON401 *Error
+	JSON404 *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetV1InstallsIdEventsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetV1InstallsIdEventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetV1InstallsIdLogsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
 	JSON401      *Error
 	JSON404      *Error
 }
@@ -3551,6 +4277,30 @@ func (r GetV1InstallsIdPodsResponse) StatusCode() int {
 	return 0
 }
 
+type GetV1InstallsIdStatusResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *InstallStatus
+	JSON401      *Error
+	JSON404      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetV1InstallsIdStatusResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetV1InstallsIdStatusResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type PatchV1InstallsIdValuesResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -3949,6 +4699,54 @@ func (r PatchV1TemplatesIdResponse) StatusCode() int {
 	return 0
 }
 
+type GetV1TemplatesIdHelmSourcesSourceIdChartResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *HelmSourceChartFiles
+	JSON401      *Error
+	JSON404      *Error
+	JSON502      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetV1TemplatesIdHelmSourcesSourceIdChartResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetV1TemplatesIdHelmSourcesSourceIdChartResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetV1UserResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *CurrentUser
+	JSON401      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetV1UserResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetV1UserResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
 type GetV1UserTokensResponse struct {
 	Body         []byte
 	HTTPResponse *http.Response
@@ -4110,6 +4908,15 @@ func (c *ClientWithResponses) PatchV1ClustersIdWithResponse(ctx context.Context,
 	return ParsePatchV1ClustersIdResponse(rsp)
 }
 
+// GetV1ClustersIdEventsWithResponse request returning *GetV1ClustersIdEventsResponse
+func (c *ClientWithResponses) GetV1ClustersIdEventsWithResponse(ctx context.Context, id string, params *GetV1ClustersIdEventsParams, reqEditors ...RequestEditorFn) (*GetV1ClustersIdEventsResponse, error) {
+	rsp, err := c.GetV1ClustersIdEvents(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetV1ClustersIdEventsResponse(rsp)
+}
+
 // GetV1ClustersIdKubeconfigWithResponse request returning *GetV1ClustersIdKubeconfigResponse
 func (c *ClientWithResponses) GetV1ClustersIdKubeconfigWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetV1ClustersIdKubeconfigResponse, error) {
 	rsp, err := c.GetV1ClustersIdKubeconfig(ctx, id, reqEditors...)
@@ -4180,6 +4987,32 @@ func (c *ClientWithResponses) GetV1InstallsIdWithResponse(ctx context.Context, i
 	return ParseGetV1InstallsIdResponse(rsp)
 }
 
+// PatchV1InstallsIdWithBodyWithResponse request with arbitrary body returning *PatchV1InstallsIdResponse
+func (c *ClientWithResponses) PatchV1InstallsIdWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PatchV1InstallsIdResponse, error) {
+	rsp, err := c.PatchV1InstallsIdWithBody(ctx, id, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePatchV1InstallsIdResponse(rsp)
+}
+
+func (c *ClientWithResponses) PatchV1InstallsIdWithResponse(ctx context.Context, id string, body PatchV1InstallsIdJSONRequestBody, reqEditors ...RequestEditorFn) (*PatchV1InstallsIdResponse, error) {
+	rsp, err := c.PatchV1InstallsId(ctx, id, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePatchV1InstallsIdResponse(rsp)
+}
+
+// GetV1InstallsIdEventsWithResponse request returning *GetV1InstallsIdEventsResponse
+func (c *ClientWithResponses) GetV1InstallsIdEventsWithResponse(ctx context.Context, id string, params *GetV1InstallsIdEventsParams, reqEditors ...RequestEditorFn) (*GetV1InstallsIdEventsResponse, error) {
+	rsp, err := c.GetV1InstallsIdEvents(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetV1InstallsIdEventsResponse(rsp)
+}
+
 // GetV1InstallsIdLogsWithResponse request returning *GetV1InstallsIdLogsResponse
 func (c *ClientWithResponses) GetV1InstallsIdLogsWithResponse(ctx context.Context, id string, params *GetV1InstallsIdLogsParams, reqEditors ...RequestEditorFn) (*GetV1InstallsIdLogsResponse, error) {
 	rsp, err := c.GetV1InstallsIdLogs(ctx, id, params, reqEditors...)
@@ -4215,6 +5048,15 @@ func (c *ClientWithResponses) GetV1InstallsIdPodsWithResponse(ctx context.Contex
 	return ParseGetV1InstallsIdPodsResponse(rsp)
 }
 
+// GetV1InstallsIdStatusWithResponse request returning *GetV1InstallsIdStatusResponse
+func (c *ClientWithResponses) GetV1InstallsIdStatusWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*GetV1InstallsIdStatusResponse, error) {
+	rsp, err := c.GetV1InstallsIdStatus(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetV1InstallsIdStatusResponse(rsp)
+}
+
 // PatchV1InstallsIdValuesWithBodyWithResponse request with arbitrary body returning *PatchV1InstallsIdValuesResponse
 func (c *ClientWithResponses) PatchV1InstallsIdValuesWithBodyWithResponse(ctx context.Context, id string, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PatchV1InstallsIdValuesResponse, error) {
 	rsp, err := c.PatchV1InstallsIdValuesWithBody(ctx, id, contentType, body, reqEditors...)
@@ -4415,6 +5257,24 @@ func (c *ClientWithResponses) PatchV1TemplatesIdWithResponse(ctx context.Context
 	return ParsePatchV1TemplatesIdResponse(rsp)
 }
 
+// GetV1TemplatesIdHelmSourcesSourceIdChartWithResponse request returning *GetV1TemplatesIdHelmSourcesSourceIdChartResponse
+func (c *ClientWithResponses) GetV1TemplatesIdHelmSourcesSourceIdChartWithResponse(ctx context.Context, id string, sourceId string, reqEditors ...RequestEditorFn) (*GetV1TemplatesIdHelmSourcesSourceIdChartResponse, error) {
+	rsp, err := c.GetV1TemplatesIdHelmSourcesSourceIdChart(ctx, id, sourceId, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetV1TemplatesIdHelmSourcesSourceIdChartResponse(rsp)
+}
+
+// GetV1UserWithResponse request returning *GetV1UserResponse
+func (c *ClientWithResponses) GetV1UserWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*GetV1UserResponse, error) {
+	rsp, err := c.GetV1User(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetV1UserResponse(rsp)
+}
+
 // GetV1UserTokensWithResponse request returning *GetV1UserTokensResponse
 func (c *ClientWithResponses) GetV1UserTokensWithResponse(ctx context.Context, params *GetV1UserTokensParams, reqEditors ...RequestEditorFn) (*GetV1UserTokensResponse, error) {
 	rsp, err := c.GetV1UserTokens(ctx, params, reqEditors...)
@@ -4656,6 +5516,55 @@ func ParsePatchV1ClustersIdResponse(rsp *http.Response) (*PatchV1ClustersIdRespo
 	return response, nil
 }
 
+// ParseGetV1ClustersIdEventsResponse parses an HTTP response from a GetV1ClustersIdEventsWithResponse call
+func ParseGetV1ClustersIdEventsResponse(rsp *http.Response) (*GetV1ClustersIdEventsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetV1ClustersIdEventsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data []Event `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 422:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON422 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseGetV1ClustersIdKubeconfigResponse parses an HTTP response from a GetV1ClustersIdKubeconfigWithResponse call
 func ParseGetV1ClustersIdKubeconfigResponse(rsp *http.Response) (*GetV1ClustersIdKubeconfigResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -4752,6 +5661,13 @@ func ParseGetV1InstallsResponse(rsp *http.Response) (*GetV1InstallsResponse, err
 		}
 		response.JSON403 = &dest
 
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 422:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON422 = &dest
+
 	}
 
 	return response, nil
@@ -4910,6 +5826,102 @@ func ParseGetV1InstallsIdResponse(rsp *http.Response) (*GetV1InstallsIdResponse,
 	return response, nil
 }
 
+// ParsePatchV1InstallsIdResponse parses an HTTP response from a PatchV1InstallsIdWithResponse call
+func ParsePatchV1InstallsIdResponse(rsp *http.Response) (*PatchV1InstallsIdResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PatchV1InstallsIdResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Install
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 422:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON422 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetV1InstallsIdEventsResponse parses an HTTP response from a GetV1InstallsIdEventsWithResponse call
+func ParseGetV1InstallsIdEventsResponse(rsp *http.Response) (*GetV1InstallsIdEventsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetV1InstallsIdEventsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data []Event `json:"data"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseGetV1InstallsIdLogsResponse parses an HTTP response from a GetV1InstallsIdLogsWithResponse call
 func ParseGetV1InstallsIdLogsResponse(rsp *http.Response) (*GetV1InstallsIdLogsResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -4924,6 +5936,13 @@ func ParseGetV1InstallsIdLogsResponse(rsp *http.Response) (*GetV1InstallsIdLogsR
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
 		var dest Error
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -5025,6 +6044,46 @@ func ParseGetV1InstallsIdPodsResponse(rsp *http.Response) (*GetV1InstallsIdPodsR
 	return response, nil
 }
 
+// ParseGetV1InstallsIdStatusResponse parses an HTTP response from a GetV1InstallsIdStatusWithResponse call
+func ParseGetV1InstallsIdStatusResponse(rsp *http.Response) (*GetV1InstallsIdStatusResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetV1InstallsIdStatusResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest InstallStatus
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParsePatchV1InstallsIdValuesResponse parses an HTTP response from a PatchV1InstallsIdValuesWithResponse call
 func ParsePatchV1InstallsIdValuesResponse(rsp *http.Response) (*PatchV1InstallsIdValuesResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -5703,6 +6762,86 @@ func ParsePatchV1TemplatesIdResponse(rsp *http.Response) (*PatchV1TemplatesIdRes
 	return response, nil
 }
 
+// ParseGetV1TemplatesIdHelmSourcesSourceIdChartResponse parses an HTTP response from a GetV1TemplatesIdHelmSourcesSourceIdChartWithResponse call
+func ParseGetV1TemplatesIdHelmSourcesSourceIdChartResponse(rsp *http.Response) (*GetV1TemplatesIdHelmSourcesSourceIdChartResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetV1TemplatesIdHelmSourcesSourceIdChartResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest HelmSourceChartFiles
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 502:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON502 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetV1UserResponse parses an HTTP response from a GetV1UserWithResponse call
+func ParseGetV1UserResponse(rsp *http.Response) (*GetV1UserResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetV1UserResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest CurrentUser
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseGetV1UserTokensResponse parses an HTTP response from a GetV1UserTokensWithResponse call
 func ParseGetV1UserTokensResponse(rsp *http.Response) (*GetV1UserTokensResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
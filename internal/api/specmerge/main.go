@@ -0,0 +1,117 @@
+// Command specmerge merges openapi.pending.json over the server's
+// openapi.json for code generation (see task generate).
+//
+// The server spec is replaced wholesale by the weekly update, so the
+// operations and schemas the CLI needs before the server publishes them
+// live in the pending file. Each operation (path and method) and schema
+// there replaces the server's; ones the server already publishes are
+// reported, so the pending entry can be removed.
+//
+// Usage: specmerge <openapi.json> <openapi.pending.json> <output>
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+)
+
+func main() {
+	if len(os.Args) != 4 {
+		fmt.Fprintln(os.Stderr, "usage: specmerge <openapi.json> <openapi.pending.json> <output>")
+		os.Exit(2)
+	}
+	if err := run(os.Args[1], os.Args[2], os.Args[3], os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "specmerge: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(specPath, pendingPath, outPath string, report io.Writer) error {
+	var spec, pending map[string]any
+	if err := readJSON(specPath, &spec); err != nil {
+		return err
+	}
+	if err := readJSON(pendingPath, &pending); err != nil {
+		return err
+	}
+
+	for _, note := range merge(spec, pending) {
+		_, _ = fmt.Fprintf(report, "%s: %s\n", pendingPath, note)
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outPath, data, 0o644)
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return nil
+}
+
+// merge copies pending's operations and schemas into spec, replacing
+// spec's. It returns a note for each one spec already had.
+func merge(spec, pending map[string]any) []string {
+	var notes []string
+	note := func(what string, server, ours any) {
+		if reflect.DeepEqual(server, ours) {
+			notes = append(notes, what+" is published by the server unchanged; remove it")
+		} else {
+			notes = append(notes, what+" overrides the server's version")
+		}
+	}
+
+	paths := object(spec, "paths")
+	pendingPaths := object(pending, "paths")
+	for _, p := range sortedKeys(pendingPaths) {
+		ops, _ := pendingPaths[p].(map[string]any)
+		item := object(paths, p)
+		for _, method := range sortedKeys(ops) {
+			if server, ok := item[method]; ok {
+				note(fmt.Sprintf("%s %s", method, p), server, ops[method])
+			}
+			item[method] = ops[method]
+		}
+	}
+
+	schemas := object(object(spec, "components"), "schemas")
+	pendingSchemas := object(object(pending, "components"), "schemas")
+	for _, name := range sortedKeys(pendingSchemas) {
+		if server, ok := schemas[name]; ok {
+			note("schema "+name, server, pendingSchemas[name])
+		}
+		schemas[name] = pendingSchemas[name]
+	}
+	return notes
+}
+
+// object returns m[key] as an object, creating it if it's missing.
+func object(m map[string]any, key string) map[string]any {
+	o, ok := m[key].(map[string]any)
+	if !ok {
+		o = map[string]any{}
+		m[key] = o
+	}
+	return o
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	var spec, pending map[string]any
+	if err := json.Unmarshal([]byte(`{
+		"paths": {"/v1/installs": {"get": {"operationId": "list"}}},
+		"components": {"schemas": {"Install": {"type": "object"}, "Pod": {"type": "object"}}}
+	}`), &spec); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{
+		"paths": {
+			"/v1/installs": {"post": {"operationId": "create"}},
+			"/v1/user": {"get": {"operationId": "user"}}
+		},
+		"components": {"schemas": {"Install": {"type": "object", "required": ["id"]}, "Pod": {"type": "object"}}}
+	}`), &pending); err != nil {
+		t.Fatal(err)
+	}
+
+	notes := merge(spec, pending)
+
+	var want map[string]any
+	_ = json.Unmarshal([]byte(`{
+		"paths": {
+			"/v1/installs": {"get": {"operationId": "list"}, "post": {"operationId": "create"}},
+			"/v1/user": {"get": {"operationId": "user"}}
+		},
+		"components": {"schemas": {"Install": {"type": "object", "required": ["id"]}, "Pod": {"type": "object"}}}
+	}`), &want)
+	if !reflect.DeepEqual(spec, want) {
+		t.Errorf("merged = %v\nwant %v", spec, want)
+	}
+	wantNotes := []string{
+		"schema Install overrides the server's version",
+		"schema Pod is published by the server unchanged; remove it",
+	}
+	if !reflect.DeepEqual(notes, wantNotes) {
+		t.Errorf("notes = %q, want %q", notes, wantNotes)
+	}
+}
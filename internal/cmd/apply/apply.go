@@ -0,0 +1,617 @@
+package apply
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdApply() *cobra.Command {
+	var file string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "apply -f <file>",
+		Short: "Create or update resources from a manifest",
+		Long: `Reads a declarative environment manifest and creates the resources it
+describes, in dependency order: regions, templates, products, installs.
+
+Resources are matched to existing ones by name, so applying the same
+manifest twice is safe:
+  - regions and products that already exist are left unchanged
+  - templates whose sources differ are updated
+  - installs (one per product and region) that already exist are updated
+    only when their overrides differ from the manifest's
+
+Use --dry-run to see the plan without changing anything.
+
+Example manifest:
+
+  workspace: ws_123          # optional; must match the active workspace
+  regions:
+    - name: eu-west
+  templates:
+    - name: monitoring
+      sources:
+        - chart: {repo_url: https://prometheus-community.github.io/helm-charts, chart: prometheus, target_revision: 25.0.0}
+  products:
+    - name: web
+      clusters: [cls_abc]
+      sources:
+        - chart: {repo_url: https://charts.bitnami.com/bitnami, chart: nginx, target_revision: 15.0.0}
+          values: {replicaCount: 2}
+  installs:
+    - product: web
+      region: eu-west
+      overrides:
+        nginx: {replicaCount: 3}   # keyed by chart name or helm source ID`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := readManifest(file)
+			if err != nil {
+				return err
+			}
+
+			client, cfg, err := cmdutil.NewClient()
+			if err != nil {
+				return err
+			}
+			if err := cmdutil.RequireWorkspace(cfg); err != nil {
+				return err
+			}
+			if m.Workspace != "" && m.Workspace != cfg.ActiveWorkspace {
+				return fmt.Errorf("manifest is for workspace %s but the active workspace is %s", m.Workspace, cfg.ActiveWorkspace)
+			}
+
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+
+			a := &applier{client: client, dryRun: dryRun, now: time.Now}
+			applyErr := a.apply(cmd.Context(), m)
+
+			if format.IsJSON() {
+				if err := output.PrintData(format, a.changes); err != nil {
+					return err
+				}
+				return applyErr
+			}
+			printChanges(a.changes, dryRun)
+			return applyErr
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "filename", "f", "", "Manifest file (- for stdin)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the plan without changing anything")
+	_ = cmd.MarkFlagRequired("filename")
+
+	return cmd
+}
+
+// Change actions. In a dry run they describe what would happen.
+const (
+	actionCreate    = "create"
+	actionUpdate    = "update"
+	actionUnchanged = "unchanged"
+)
+
+// change is one resource's outcome, reported in the summary.
+type change struct {
+	Action string `json:"action"`
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	ID     string `json:"id,omitempty"` // empty for resources not created yet (dry run, async installs)
+}
+
+type productRef struct {
+	ID         string
+	TemplateID string
+}
+
+// applier applies a manifest, recording each change. Name → ID maps include
+// resources created earlier in the run; in a dry run those map to "".
+type applier struct {
+	client  *api.ClientWithResponses
+	dryRun  bool
+	now     func() time.Time
+	changes []change
+
+	regions  map[string]string
+	products map[string]productRef
+}
+
+func (a *applier) record(action, kind, name, id string) {
+	a.changes = append(a.changes, change{Action: action, Kind: kind, Name: name, ID: id})
+}
+
+func (a *applier) apply(ctx context.Context, m *Manifest) error {
+	regions, err := cmdutil.ListAll(func(cursor *string) ([]api.Region, *api.Pagination, error) {
+		resp, err := a.client.GetV1RegionsWithResponse(ctx, &api.GetV1RegionsParams{Limit: &pageSize, Cursor: cursor})
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching regions: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
+		}
+		return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+	})
+	if err != nil {
+		return err
+	}
+	a.regions = make(map[string]string, len(regions))
+	for _, r := range regions {
+		a.regions[r.Name] = r.Id
+	}
+
+	products, err := cmdutil.ListAll(func(cursor *string) ([]api.Product, *api.Pagination, error) {
+		resp, err := a.client.GetV1ProductsWithResponse(ctx, &api.GetV1ProductsParams{Limit: &pageSize, Cursor: cursor})
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching products: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
+		}
+		return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+	})
+	if err != nil {
+		return err
+	}
+	a.products = make(map[string]productRef, len(products))
+	for _, p := range products {
+		a.products[p.Name] = productRef{ID: p.Id, TemplateID: p.TemplateId}
+	}
+
+	// Check install references up front so a typo fails before anything
+	// is created.
+	for _, i := range m.Installs {
+		if _, ok := a.products[i.Product]; !ok && !hasProduct(m, i.Product) {
+			return fmt.Errorf("install %s@%s: product %q not found in the manifest or workspace", i.Product, i.Region, i.Product)
+		}
+		if _, ok := a.regions[i.Region]; !ok && !hasRegion(m, i.Region) {
+			return fmt.Errorf("install %s@%s: region %q not found in the manifest or workspace", i.Product, i.Region, i.Region)
+		}
+	}
+
+	for _, r := range m.Regions {
+		if err := a.applyRegion(ctx, r); err != nil {
+			return err
+		}
+	}
+	if len(m.Templates) > 0 {
+		if err := a.applyTemplates(ctx, m.Templates); err != nil {
+			return err
+		}
+	}
+	for _, p := range m.Products {
+		if err := a.applyProduct(ctx, p); err != nil {
+			return err
+		}
+	}
+	if len(m.Installs) > 0 {
+		if err := a.applyInstalls(ctx, m.Installs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyRegion(ctx context.Context, r RegionSpec) error {
+	if id, ok := a.regions[r.Name]; ok {
+		a.record(actionUnchanged, "region", r.Name, id)
+		return nil
+	}
+	if a.dryRun {
+		a.regions[r.Name] = ""
+		a.record(actionCreate, "region", r.Name, "")
+		return nil
+	}
+
+	body := api.PostV1RegionsJSONRequestBody{Name: r.Name}
+	if r.Icon != "" {
+		body.Icon = &r.Icon
+	}
+	resp, err := a.client.PostV1RegionsWithResponse(ctx, body)
+	if err != nil {
+		return fmt.Errorf("creating region %s: %w", r.Name, err)
+	}
+	if resp.JSON201 == nil {
+		return fmt.Errorf("creating region %s: %w", r.Name, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403, resp.JSON422))
+	}
+	a.regions[r.Name] = resp.JSON201.Id
+	a.record(actionCreate, "region", r.Name, resp.JSON201.Id)
+	return nil
+}
+
+func (a *applier) applyTemplates(ctx context.Context, specs []TemplateSpec) error {
+	existing, err := cmdutil.ListAll(func(cursor *string) ([]api.Template, *api.Pagination, error) {
+		resp, err := a.client.GetV1TemplatesWithResponse(ctx, &api.GetV1TemplatesParams{Limit: &pageSize, Cursor: cursor})
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching templates: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
+		}
+		return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+	})
+	if err != nil {
+		return err
+	}
+	ids := make(map[string]string, len(existing))
+	for _, t := range existing {
+		ids[t.Name] = t.Id
+	}
+
+	for _, t := range specs {
+		id, ok := ids[t.Name]
+		if !ok {
+			if err := a.createTemplate(ctx, t); err != nil {
+				return err
+			}
+			continue
+		}
+
+		detail, err := a.fetchTemplate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if templateMatches(t, detail) {
+			a.record(actionUnchanged, "template", t.Name, id)
+			continue
+		}
+		if !a.dryRun {
+			var body api.PatchV1TemplatesIdJSONRequestBody
+			if err := convert(t.Sources, &body.Sources); err != nil {
+				return fmt.Errorf("template %s: %w", t.Name, err)
+			}
+			if t.RegistryProxyMode != "" {
+				mode := api.PatchV1TemplatesIdJSONBodyRegistryProxyMode(t.RegistryProxyMode)
+				body.RegistryProxyMode = &mode
+			}
+			resp, err := a.client.PatchV1TemplatesIdWithResponse(ctx, id, body)
+			if err != nil {
+				return fmt.Errorf("updating template %s: %w", t.Name, err)
+			}
+			if resp.JSON200 == nil {
+				return fmt.Errorf("updating template %s: %w", t.Name, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404, resp.JSON422))
+			}
+		}
+		a.record(actionUpdate, "template", t.Name, id)
+	}
+	return nil
+}
+
+func (a *applier) createTemplate(ctx context.Context, t TemplateSpec) error {
+	if a.dryRun {
+		a.record(actionCreate, "template", t.Name, "")
+		return nil
+	}
+
+	body := api.PostV1TemplatesJSONRequestBody{Name: t.Name}
+	if err := convert(t.Sources, &body.Sources); err != nil {
+		return fmt.Errorf("template %s: %w", t.Name, err)
+	}
+	if t.RegistryProxyMode != "" {
+		mode := api.PostV1TemplatesJSONBodyRegistryProxyMode(t.RegistryProxyMode)
+		body.RegistryProxyMode = &mode
+	}
+	resp, err := a.client.PostV1TemplatesWithResponse(ctx, body)
+	if err != nil {
+		return fmt.Errorf("creating template %s: %w", t.Name, err)
+	}
+	if resp.JSON201 == nil {
+		return fmt.Errorf("creating template %s: %w", t.Name, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403, resp.JSON422))
+	}
+	a.record(actionCreate, "template", t.Name, resp.JSON201.TemplateId)
+	return nil
+}
+
+// templateMatches reports whether an existing template already has the
+// spec's sources (charts and values) and proxy mode. Source metadata is
+// not compared.
+func templateMatches(spec TemplateSpec, t *api.TemplateDetail) bool {
+	if spec.RegistryProxyMode != "" &&
+		(t.RegistryProxyMode == nil || string(*t.RegistryProxyMode) != spec.RegistryProxyMode) {
+		return false
+	}
+
+	var have, want []SourceSpec
+	if convert(t.HelmSources, &have) != nil || convert(spec.Sources, &want) != nil {
+		return false
+	}
+	return reflect.DeepEqual(have, want)
+}
+
+func (a *applier) applyProduct(ctx context.Context, p ProductSpec) error {
+	if ref, ok := a.products[p.Name]; ok {
+		a.record(actionUnchanged, "product", p.Name, ref.ID)
+		return nil
+	}
+	if a.dryRun {
+		a.products[p.Name] = productRef{}
+		a.record(actionCreate, "product", p.Name, "")
+		return nil
+	}
+
+	body := api.PostV1ProductsJSONRequestBody{Name: p.Name, ClusterIds: p.Clusters}
+	if err := convert(p.Sources, &body.Sources); err != nil {
+		return fmt.Errorf("product %s: %w", p.Name, err)
+	}
+	resp, err := a.client.PostV1ProductsWithResponse(ctx, body)
+	if err != nil {
+		return fmt.Errorf("creating product %s: %w", p.Name, err)
+	}
+	if resp.JSON201 == nil {
+		return fmt.Errorf("creating product %s: %w", p.Name, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403, resp.JSON422))
+	}
+	a.products[p.Name] = productRef{ID: resp.JSON201.ProductId, TemplateID: resp.JSON201.TemplateId}
+	a.record(actionCreate, "product", p.Name, resp.JSON201.ProductId)
+	return nil
+}
+
+func (a *applier) applyInstalls(ctx context.Context, specs []InstallSpec) error {
+	clusters, err := cmdutil.ListAll(func(cursor *string) ([]api.Cluster, *api.Pagination, error) {
+		resp, err := a.client.GetV1ClustersWithResponse(ctx, &api.GetV1ClustersParams{Limit: &pageSize, Cursor: cursor})
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching clusters: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
+		}
+		return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+	})
+	if err != nil {
+		return err
+	}
+	clusterRegion := make(map[string]string, len(clusters))
+	for _, c := range clusters {
+		clusterRegion[c.Id] = c.RegionId
+	}
+
+	installs, err := cmdutil.ListAll(func(cursor *string) ([]api.Install, *api.Pagination, error) {
+		resp, err := a.client.GetV1InstallsWithResponse(ctx, &api.GetV1InstallsParams{Limit: &pageSize, Cursor: cursor})
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching installs: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
+		}
+		return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+	})
+	if err != nil {
+		return err
+	}
+	// Installs are identified by product and region: "<product-id>/<region-id>".
+	existing := make(map[string]api.Install, len(installs))
+	for _, i := range installs {
+		if i.ProductId != nil {
+			existing[*i.ProductId+"/"+clusterRegion[i.ClusterId]] = i
+		}
+	}
+
+	for _, spec := range specs {
+		if err := a.applyInstall(ctx, spec, existing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *applier) applyInstall(ctx context.Context, spec InstallSpec, existing map[string]api.Install) error {
+	name := spec.Product + "@" + spec.Region
+	product := a.products[spec.Product]
+	regionID := a.regions[spec.Region]
+
+	// Product or region is only planned (dry run): the install is too.
+	if product.ID == "" || regionID == "" {
+		a.record(actionCreate, "install", name, "")
+		return nil
+	}
+
+	var overrides []sourceValues
+	if len(spec.Overrides) > 0 {
+		detail, err := a.fetchTemplate(ctx, product.TemplateID)
+		if err != nil {
+			return err
+		}
+		overrides, err = resolveOverrides(spec.Overrides, detail.HelmSources)
+		if err != nil {
+			return fmt.Errorf("install %s: %w", name, err)
+		}
+	}
+
+	if inst, ok := existing[product.ID+"/"+regionID]; ok {
+		id := inst.Id
+		if len(overrides) == 0 || overridesMatch(inst.Overrides, overrides) {
+			a.record(actionUnchanged, "install", name, id)
+			return nil
+		}
+		if !a.dryRun {
+			resp, err := a.client.PatchV1InstallsIdOverridesWithResponse(ctx, id, api.PatchV1InstallsIdOverridesJSONRequestBody{Updates: overrides})
+			if err != nil {
+				return fmt.Errorf("updating install %s: %w", name, err)
+			}
+			if _, ok := cmdutil.Succeeded(resp.StatusCode(), resp.Body, cmdutil.AsyncStatuses...); !ok {
+				return fmt.Errorf("updating install %s: %w", name, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404, resp.JSON422))
+			}
+		}
+		a.record(actionUpdate, "install", name, id)
+		return nil
+	}
+
+	if !a.dryRun {
+		body := api.PostV1InstallsJSONRequestBody{ProductId: product.ID, RegionId: regionID}
+		if len(overrides) > 0 {
+			body.Overrides = &overrides
+		}
+		// Installs are created asynchronously and may not be listed yet on
+		// a quick re-run; the key makes the retry return the same workflow.
+		key := installKey(product.ID, regionID, overrides, a.now())
+		resp, err := a.client.PostV1InstallsWithResponse(ctx, &api.PostV1InstallsParams{IdempotencyKey: &key}, body)
+		if err != nil {
+			return fmt.Errorf("creating install %s: %w", name, err)
+		}
+		if _, ok := cmdutil.Succeeded(resp.StatusCode(), resp.Body, cmdutil.AsyncStatuses...); !ok {
+			return fmt.Errorf("creating install %s: %w", name, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403, resp.JSON422))
+		}
+	}
+	a.record(actionCreate, "install", name, "")
+	return nil
+}
+
+// sourceValues is the per-source element of the install override bodies.
+type sourceValues = struct {
+	TemplateHelmSourceId string                  `json:"template_helm_source_id"`
+	Values               map[string]*interface{} `json:"values"`
+}
+
+// overridesMatch reports whether an install already has the desired
+// values for every overridden source. Installs listed without overrides
+// (older API versions) never match, so the overrides are re-sent.
+func overridesMatch(current *[]sourceValues, desired []sourceValues) bool {
+	if current == nil {
+		return false
+	}
+	have := make(map[string]map[string]*interface{}, len(*current))
+	for _, s := range *current {
+		have[s.TemplateHelmSourceId] = s.Values
+	}
+	for _, s := range desired {
+		values, ok := have[s.TemplateHelmSourceId]
+		if !ok {
+			return false
+		}
+		var got, want map[string]any
+		if convert(values, &got) != nil || convert(s.Values, &want) != nil {
+			return false
+		}
+		if !reflect.DeepEqual(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// idempotencyWindow is how long a re-run with the same spec reuses the
+// install create's idempotency key.
+const idempotencyWindow = 10 * time.Minute
+
+// installKey derives the idempotency key for creating an install from the
+// desired spec and the current idempotencyWindow. A quick retry reuses the
+// in-flight create; an install deleted and re-applied later, or applied
+// with different overrides, gets a new key.
+func installKey(productID, regionID string, overrides []sourceValues, now time.Time) string {
+	spec, _ := json.Marshal(struct {
+		Product   string         `json:"product"`
+		Region    string         `json:"region"`
+		Overrides []sourceValues `json:"overrides"`
+		Window    int64          `json:"window"`
+	}{productID, regionID, overrides, now.Truncate(idempotencyWindow).Unix()})
+	sum := sha256.Sum256(spec)
+	return "apply-" + hex.EncodeToString(sum[:16])
+}
+
+// resolveOverrides maps overrides keyed by helm source ID or chart name to
+// the template's source IDs.
+func resolveOverrides(overrides map[string]map[string]any, sources []api.HelmSource) ([]sourceValues, error) {
+	var out []sourceValues
+	for _, key := range slices.Sorted(maps.Keys(overrides)) {
+		values := overrides[key]
+		id := ""
+		for _, s := range sources {
+			if s.Id == key || ptrValue(s.Chart.Chart) == key || ptrValue(s.Chart.Path) == key {
+				id = s.Id
+				break
+			}
+		}
+		if id == "" {
+			return nil, fmt.Errorf("no helm source matches override %q", key)
+		}
+
+		vals := make(map[string]*interface{}, len(values))
+		for k, v := range values {
+			val := v
+			vals[k] = &val
+		}
+		out = append(out, sourceValues{TemplateHelmSourceId: id, Values: vals})
+	}
+	return out, nil
+}
+
+func (a *applier) fetchTemplate(ctx context.Context, id string) (*api.TemplateDetail, error) {
+	resp, err := a.client.GetV1TemplatesIdWithResponse(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("fetching template: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+	}
+	return resp.JSON200, nil
+}
+
+// pageSize is the list page size; a variable because params take a pointer.
+var pageSize = 100
+
+func printChanges(changes []change, dryRun bool) {
+	if len(changes) == 0 {
+		fmt.Println("Nothing to apply.")
+		return
+	}
+
+	counts := map[string]int{}
+	var rows [][]string
+	for _, c := range changes {
+		counts[c.Action]++
+		id := c.ID
+		if id == "" {
+			id = "-"
+		}
+		rows = append(rows, []string{c.Action, c.Kind, c.Name, id})
+	}
+	output.PrintTable([]string{"ACTION", "KIND", "NAME", "ID"}, rows)
+
+	fmt.Println()
+	if dryRun {
+		fmt.Printf("Plan: %d to create, %d to update, %d unchanged.\n",
+			counts[actionCreate], counts[actionUpdate], counts[actionUnchanged])
+		return
+	}
+	fmt.Printf("%d created, %d updated, %d unchanged.\n",
+		counts[actionCreate], counts[actionUpdate], counts[actionUnchanged])
+}
+
+func hasProduct(m *Manifest, name string) bool {
+	for _, p := range m.Products {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRegion(m *Manifest, name string) bool {
+	for _, r := range m.Regions {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func ptrValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,263 @@
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdtest"
+	"github.com/cnap-tech/cli/internal/config"
+)
+
+const testManifest = `
+regions:
+  - name: eu-west
+templates:
+  - name: monitoring
+    sources:
+      - chart: {repo_url: https://charts.example.com, chart: prometheus, target_revision: 1.0.0}
+products:
+  - name: web
+    clusters: [cls_1]
+    sources:
+      - chart: {repo_url: https://charts.example.com, chart: nginx, target_revision: 1.0.0}
+        values: {replicas: 2}
+installs:
+  - product: web
+    region: eu-west
+`
+
+// fakeAPI is an in-memory workspace supporting the endpoints apply uses.
+type fakeAPI struct {
+	mu        sync.Mutex
+	regions   []api.Region
+	templates []api.TemplateDetail
+	products  []api.Product
+	installs  []api.Install
+	writes    []string // "METHOD path" of every mutating request
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Method != http.MethodGet {
+		f.writes = append(f.writes, r.Method+" "+r.URL.Path)
+	}
+	reply := func(status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	list := func(data any) map[string]any {
+		return map[string]any{"data": data, "pagination": map[string]any{"has_more": false}}
+	}
+
+	switch r.Method + " " + r.URL.Path {
+	case "GET /v1/regions":
+		reply(200, list(f.regions))
+	case "POST /v1/regions":
+		var body api.PostV1RegionsJSONRequestBody
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		region := api.Region{Id: "reg_1", Name: body.Name}
+		f.regions = append(f.regions, region)
+		reply(201, region)
+	case "GET /v1/templates":
+		var out []api.Template
+		for _, t := range f.templates {
+			out = append(out, api.Template{Id: t.Id, Name: t.Name})
+		}
+		reply(200, list(out))
+	case "POST /v1/templates":
+		var body struct {
+			Name    string           `json:"name"`
+			Sources []api.HelmSource `json:"sources"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.templates = append(f.templates, api.TemplateDetail{Id: "tpl_1", Name: body.Name, HelmSources: body.Sources})
+		reply(201, map[string]string{"template_id": "tpl_1"})
+	case "GET /v1/templates/tpl_1":
+		reply(200, f.templates[0])
+	case "GET /v1/templates/tpl_2":
+		chart := "nginx"
+		reply(200, api.TemplateDetail{Id: "tpl_2", HelmSources: []api.HelmSource{{Id: "src_1", Chart: api.HelmSourceChart{Chart: &chart}}}})
+	case "GET /v1/products":
+		reply(200, list(f.products))
+	case "POST /v1/products":
+		var body api.PostV1ProductsJSONRequestBody
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.products = append(f.products, api.Product{Id: "prod_1", Name: body.Name, TemplateId: "tpl_2"})
+		reply(201, map[string]string{"product_id": "prod_1", "template_id": "tpl_2"})
+	case "GET /v1/clusters":
+		reply(200, list([]api.Cluster{{Id: "cls_1", Name: "main", RegionId: "reg_1"}}))
+	case "GET /v1/installs":
+		reply(200, list(f.installs))
+	case "POST /v1/installs":
+		if r.Header.Get("Idempotency-Key") == "" {
+			reply(422, map[string]any{"error": map[string]any{"code": "missing_key", "message": "missing idempotency key"}})
+			return
+		}
+		var body api.PostV1InstallsJSONRequestBody
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.installs = append(f.installs, api.Install{Id: "inst_1", ClusterId: "cls_1", ProductId: &body.ProductId, Overrides: body.Overrides})
+		reply(202, map[string]any{})
+	case "PATCH /v1/installs/inst_1/overrides":
+		var body api.PatchV1InstallsIdOverridesJSONRequestBody
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.installs[0].Overrides = &body.Updates
+		reply(202, map[string]any{})
+	default:
+		cmdtest.WriteError(w, http.StatusNotFound, "not found: "+r.Method+" "+r.URL.Path)
+	}
+}
+
+func setup(t *testing.T) (*fakeAPI, string) {
+	t.Helper()
+	f := &fakeAPI{}
+	cmdtest.NewServer(t, f)
+
+	cfg := config.DefaultConfig()
+	cfg.ActiveWorkspace = "ws_1"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "env.yaml")
+	if err := os.WriteFile(path, []byte(testManifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return f, path
+}
+
+func TestApplyIsIdempotent(t *testing.T) {
+	f, path := setup(t)
+
+	if err := cmdtest.Run(NewCmdApply(), "-f", path); err != nil {
+		t.Fatalf("first apply: %v", err)
+	}
+	want := []string{"POST /v1/regions", "POST /v1/templates", "POST /v1/products", "POST /v1/installs"}
+	if !reflect.DeepEqual(f.writes, want) {
+		t.Errorf("first apply writes = %v, want %v", f.writes, want)
+	}
+
+	f.writes = nil
+	if err := cmdtest.Run(NewCmdApply(), "-f", path); err != nil {
+		t.Fatalf("second apply: %v", err)
+	}
+	if len(f.writes) != 0 {
+		t.Errorf("second apply writes = %v, want none", f.writes)
+	}
+}
+
+func TestApplyOverridesAreIdempotent(t *testing.T) {
+	f, path := setup(t)
+	write := func(replicas int) {
+		t.Helper()
+		manifest := testManifest + fmt.Sprintf("    overrides:\n      nginx: {replicas: %d}\n", replicas)
+		if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(3)
+	if err := cmdtest.Run(NewCmdApply(), "-f", path); err != nil {
+		t.Fatalf("first apply: %v", err)
+	}
+
+	f.writes = nil
+	if err := cmdtest.Run(NewCmdApply(), "-f", path); err != nil {
+		t.Fatalf("second apply: %v", err)
+	}
+	if len(f.writes) != 0 {
+		t.Errorf("unchanged overrides writes = %v, want none", f.writes)
+	}
+
+	write(4)
+	if err := cmdtest.Run(NewCmdApply(), "-f", path); err != nil {
+		t.Fatalf("third apply: %v", err)
+	}
+	want := []string{"PATCH /v1/installs/inst_1/overrides"}
+	if !reflect.DeepEqual(f.writes, want) {
+		t.Errorf("changed overrides writes = %v, want %v", f.writes, want)
+	}
+}
+
+func TestInstallKey(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	var three interface{} = float64(3)
+	overrides := []sourceValues{{TemplateHelmSourceId: "src_1", Values: map[string]*interface{}{"replicas": &three}}}
+
+	key := installKey("prod_1", "reg_1", nil, now)
+	if got := installKey("prod_1", "reg_1", nil, now.Add(time.Minute)); got != key {
+		t.Errorf("retry in the same window got key %s, want %s", got, key)
+	}
+	if got := installKey("prod_1", "reg_1", nil, now.Add(idempotencyWindow)); got == key {
+		t.Error("re-apply after the window reused the key")
+	}
+	if got := installKey("prod_1", "reg_1", overrides, now); got == key {
+		t.Error("different overrides reused the key")
+	}
+	if got := installKey("prod_1", "reg_2", nil, now); got == key {
+		t.Error("different region reused the key")
+	}
+}
+
+func TestApplyDryRun(t *testing.T) {
+	f, path := setup(t)
+
+	if err := cmdtest.Run(NewCmdApply(), "-f", path, "--dry-run"); err != nil {
+		t.Fatalf("apply --dry-run: %v", err)
+	}
+	if len(f.writes) != 0 {
+		t.Errorf("dry run writes = %v, want none", f.writes)
+	}
+}
+
+func TestApplyUnknownReference(t *testing.T) {
+	f, path := setup(t)
+	manifest := "installs:\n  - product: missing\n    region: eu-west\n"
+	if err := os.WriteFile(path, []byte(manifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := cmdtest.Run(NewCmdApply(), "-f", path)
+	if err == nil || !strings.Contains(err.Error(), `product "missing" not found`) {
+		t.Errorf("got error %v, want unknown product", err)
+	}
+	if len(f.writes) != 0 {
+		t.Errorf("writes = %v, want none", f.writes)
+	}
+}
+
+func TestTemplateMatches(t *testing.T) {
+	chart := "nginx"
+	values := map[string]*interface{}{}
+	var two interface{} = float64(2)
+	values["replicas"] = &two
+	detail := &api.TemplateDetail{HelmSources: []api.HelmSource{{
+		Id:     "src_1",
+		Chart:  api.HelmSourceChart{RepoUrl: "https://charts.example.com", Chart: &chart, TargetRevision: "1.0.0"},
+		Values: &values,
+	}}}
+
+	spec := TemplateSpec{Name: "web", Sources: []SourceSpec{{
+		Chart:  ChartSpec{RepoURL: "https://charts.example.com", Chart: "nginx", TargetRevision: "1.0.0"},
+		Values: map[string]any{"replicas": 2},
+	}}}
+	if !templateMatches(spec, detail) {
+		t.Error("templateMatches = false for identical sources")
+	}
+
+	spec.Sources[0].Chart.TargetRevision = "1.1.0"
+	if templateMatches(spec, detail) {
+		t.Error("templateMatches = true after a revision change")
+	}
+}
@@ -0,0 +1,156 @@
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest is the declarative environment spec read by `cnap apply`.
+// Resources are matched to existing ones by name; see the apply command's
+// help for what happens to existing resources.
+type Manifest struct {
+	// Workspace, if set, must match the active workspace.
+	Workspace string         `yaml:"workspace,omitempty"`
+	Regions   []RegionSpec   `yaml:"regions,omitempty"`
+	Templates []TemplateSpec `yaml:"templates,omitempty"`
+	Products  []ProductSpec  `yaml:"products,omitempty"`
+	Installs  []InstallSpec  `yaml:"installs,omitempty"`
+}
+
+type RegionSpec struct {
+	Name string `yaml:"name"`
+	Icon string `yaml:"icon,omitempty"`
+}
+
+type TemplateSpec struct {
+	Name              string       `yaml:"name"`
+	RegistryProxyMode string       `yaml:"registry_proxy_mode,omitempty"`
+	Sources           []SourceSpec `yaml:"sources"`
+}
+
+type ProductSpec struct {
+	Name string `yaml:"name"`
+	// Clusters are the IDs of the clusters the product can be deployed to.
+	Clusters []string     `yaml:"clusters"`
+	Sources  []SourceSpec `yaml:"sources"`
+}
+
+// InstallSpec deploys a product (by name) to a region (by name).
+// Overrides are keyed by helm source ID or chart name.
+type InstallSpec struct {
+	Product   string                    `yaml:"product"`
+	Region    string                    `yaml:"region"`
+	Overrides map[string]map[string]any `yaml:"overrides,omitempty"`
+}
+
+// SourceSpec is a helm source. Its JSON form matches the API's source
+// objects, so it can be converted to and compared with them.
+type SourceSpec struct {
+	Chart  ChartSpec      `yaml:"chart" json:"chart"`
+	Values map[string]any `yaml:"values,omitempty" json:"values,omitempty"`
+}
+
+type ChartSpec struct {
+	RepoURL        string `yaml:"repo_url" json:"repo_url"`
+	Chart          string `yaml:"chart,omitempty" json:"chart,omitempty"`
+	Path           string `yaml:"path,omitempty" json:"path,omitempty"`
+	TargetRevision string `yaml:"target_revision" json:"target_revision"`
+}
+
+// readManifest reads and validates a manifest file ("-" for stdin).
+func readManifest(path string) (*Manifest, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else if path, err = cmdutil.ExpandPath(path); err == nil {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (m *Manifest) validate() error {
+	seen := map[string]bool{}
+	unique := func(kind, name string) error {
+		if name == "" {
+			return fmt.Errorf("%s: name is required", kind)
+		}
+		if seen[kind+"/"+name] {
+			return fmt.Errorf("%s %q is defined more than once", kind, name)
+		}
+		seen[kind+"/"+name] = true
+		return nil
+	}
+
+	for _, r := range m.Regions {
+		if err := unique("region", r.Name); err != nil {
+			return err
+		}
+	}
+	for _, t := range m.Templates {
+		if err := unique("template", t.Name); err != nil {
+			return err
+		}
+		if err := validateSources("template "+t.Name, t.Sources); err != nil {
+			return err
+		}
+	}
+	for _, p := range m.Products {
+		if err := unique("product", p.Name); err != nil {
+			return err
+		}
+		if len(p.Clusters) == 0 {
+			return fmt.Errorf("product %s: at least one cluster is required", p.Name)
+		}
+		if err := validateSources("product "+p.Name, p.Sources); err != nil {
+			return err
+		}
+	}
+	for _, i := range m.Installs {
+		if i.Product == "" || i.Region == "" {
+			return fmt.Errorf("install: product and region are required")
+		}
+		if err := unique("install", i.Product+"@"+i.Region); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateSources(owner string, sources []SourceSpec) error {
+	if len(sources) == 0 {
+		return fmt.Errorf("%s: at least one source is required", owner)
+	}
+	for _, s := range sources {
+		if s.Chart.RepoURL == "" || s.Chart.TargetRevision == "" {
+			return fmt.Errorf("%s: sources need chart.repo_url and chart.target_revision", owner)
+		}
+	}
+	return nil
+}
+
+// convert copies in to out through their JSON forms. The generated request
+// bodies use deeply nested anonymous structs; this avoids spelling them out.
+func convert(in, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
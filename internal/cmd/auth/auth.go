@@ -2,14 +2,14 @@ package auth
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
-	"net/http"
+	"os"
 	"strings"
 
+	"github.com/cnap-tech/cli/internal/cmdutil"
 	"github.com/cnap-tech/cli/internal/config"
-	"github.com/cnap-tech/cli/internal/useragent"
 	"github.com/spf13/cobra"
 )
 
@@ -22,12 +22,14 @@ func NewCmdAuth() *cobra.Command {
 	cmd.AddCommand(newCmdLogin())
 	cmd.AddCommand(newCmdLogout())
 	cmd.AddCommand(newCmdStatus())
+	cmd.AddCommand(newCmdRefresh())
 
 	return cmd
 }
 
 func newCmdLogin() *cobra.Command {
-	var token string
+	var token, tokenFile string
+	var tokenStdin bool
 
 	cmd := &cobra.Command{
 		Use:   "login",
@@ -38,14 +40,33 @@ Without flags, opens your browser to authenticate via the device flow
 and stores a session token. Sessions are long-lived and auto-refresh on use.
 
 With --token, stores the given token directly (PAT or session token).
+--token-file and --token-stdin do the same without the token appearing in
+shell history or the process list, e.g. for CI secret files and mounted
+Kubernetes secrets:
 
-Create PATs at https://cnap.tech/settings/tokens`,
+  cnap auth login --token-file /run/secrets/cnap-token
+  op read op://ci/cnap/token | cnap auth login --token-stdin
+
+Create PATs on the dashboard's /settings/tokens page
+(https://cnap.tech/settings/tokens unless --auth-url or --api-url points
+elsewhere; cnap auth status shows the dashboard URL).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := config.Load()
+			cfg, err := cmdutil.LoadConfig()
 			if err != nil {
 				return err
 			}
 
+			switch {
+			case tokenFile != "":
+				if token, err = readTokenFile(tokenFile); err != nil {
+					return err
+				}
+			case tokenStdin:
+				if token, err = readToken(os.Stdin, "stdin"); err != nil {
+					return err
+				}
+			}
+
 			if token != "" {
 				cfg.Auth.Token = token
 				if err := cfg.Save(); err != nil {
@@ -60,16 +81,55 @@ Create PATs at https://cnap.tech/settings/tokens`,
 	}
 
 	cmd.Flags().StringVarP(&token, "token", "t", "", "API token (PAT or session token)")
+	cmd.Flags().StringVar(&tokenFile, "token-file", "", "Read the API token from a file")
+	cmd.Flags().BoolVar(&tokenStdin, "token-stdin", false, "Read the API token from stdin")
+	cmd.MarkFlagsMutuallyExclusive("token", "token-file", "token-stdin")
 
 	return cmd
 }
 
+func readTokenFile(path string) (string, error) {
+	path, err := cmdutil.ExpandPath(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("reading token: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return readToken(f, path)
+}
+
+// maxTokenSize bounds how much readToken reads; tokens are well under 1 KiB.
+const maxTokenSize = 16 << 10
+
+// readToken reads a token from r, trimming surrounding whitespace such as
+// the trailing newline of a secret file or `echo`.
+func readToken(r io.Reader, source string) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxTokenSize+1))
+	if err != nil {
+		return "", fmt.Errorf("reading token from %s: %w", source, err)
+	}
+	if len(data) > maxTokenSize {
+		return "", fmt.Errorf("reading token from %s: more than %d bytes; is this the right file?", source, maxTokenSize)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("no token found in %s", source)
+	}
+	if strings.ContainsAny(token, " \t\r\n") {
+		return "", fmt.Errorf("token from %s contains whitespace; expected a single token", source)
+	}
+	return token, nil
+}
+
 func newCmdLogout() *cobra.Command {
 	return &cobra.Command{
 		Use:   "logout",
 		Short: "Remove stored credentials",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := config.Load()
+			cfg, err := cmdutil.LoadConfig()
 			if err != nil {
 				return err
 			}
@@ -86,6 +146,12 @@ func newCmdLogout() *cobra.Command {
 			if err := cfg.Save(); err != nil {
 				return fmt.Errorf("saving config: %w", err)
 			}
+			// Cached cluster credentials hold admin keys fetched with this login.
+			if dir, err := config.KubeCredentialDir(); err == nil {
+				if err := os.RemoveAll(dir); err != nil {
+					slog.Debug("failed to remove cached cluster credentials", "path", dir, "error", err)
+				}
+			}
 
 			fmt.Println("Logged out. Credentials removed from ~/.cnap/config.yaml")
 			return nil
@@ -93,112 +159,45 @@ func newCmdLogout() *cobra.Command {
 	}
 }
 
-func newCmdStatus() *cobra.Command {
-	return &cobra.Command{
-		Use:   "status",
-		Short: "Show authentication status",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := config.Load()
-			if err != nil {
-				return err
-			}
-
-			token := cfg.Token()
-			if token == "" {
-				fmt.Println("Not authenticated. Run: cnap auth login")
-				return nil
-			}
-
-			tokenType := detectTokenType(token)
-
-			// Show prefix only for security
-			prefix := token
-			if len(prefix) > 16 {
-				prefix = prefix[:16] + "..."
-			}
+// TokenKind is the kind of credential a token is.
+type TokenKind string
 
-			fmt.Printf("Token type: %s\n", tokenType)
-			fmt.Printf("Token: %s\n", prefix)
-			fmt.Printf("API URL: %s\n", cfg.BaseURL())
-			fmt.Printf("Auth URL: %s\n", cfg.AuthBaseURL())
-
-			if tokenType == "Session token" {
-				if err := checkSessionStatus(cmd.Context(), cfg, token); err != nil {
-					fmt.Printf("Session status: invalid or expired (%v)\n", err)
-					fmt.Println("Run 'cnap auth login' to re-authenticate.")
-				}
-			}
-
-			if cfg.ActiveWorkspace != "" {
-				fmt.Printf("Active workspace: %s\n", cfg.ActiveWorkspace)
-			} else {
-				fmt.Println("No active workspace. Run: cnap workspaces switch <id>")
-			}
+const (
+	TokenPAT     TokenKind = "pat"
+	TokenJWT     TokenKind = "jwt"
+	TokenSession TokenKind = "session"
+)
 
-			return nil
-		},
+// TokenKindOf tells a PAT, a JWT and a session token apart by their format.
+func TokenKindOf(token string) TokenKind {
+	switch {
+	case strings.HasPrefix(token, "cnap_pat_"):
+		return TokenPAT
+	case strings.HasPrefix(token, "eyJ"):
+		return TokenJWT
+	default:
+		return TokenSession
 	}
 }
 
-func detectTokenType(token string) string {
-	switch {
-	case strings.HasPrefix(token, "cnap_pat_"):
+// String returns the label shown to users, e.g. in auth status.
+func (k TokenKind) String() string {
+	switch k {
+	case TokenPAT:
 		return "Personal Access Token (PAT)"
-	case strings.HasPrefix(token, "eyJ"):
+	case TokenJWT:
 		return "JWT"
 	default:
 		return "Session token"
 	}
 }
 
-func checkSessionStatus(ctx context.Context, cfg *config.Config, token string) error {
-	authURL := cfg.AuthBaseURL()
-	req, err := http.NewRequestWithContext(ctx, "GET", authURL+"/api/auth/get-session", nil)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("User-Agent", useragent.String())
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close() //nolint:errcheck
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("HTTP %d", resp.StatusCode)
-	}
-
-	var result struct {
-		Session *struct {
-			ExpiresAt string `json:"expiresAt"`
-		} `json:"session"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return err
-	}
-	if result.Session == nil {
-		return fmt.Errorf("session not found or expired")
-	}
-
-	fmt.Printf("Session status: active (expires: %s)\n", result.Session.ExpiresAt)
-	return nil
+// TokenType describes the kind of token: a PAT, a JWT, or a session token.
+func TokenType(token string) string {
+	return TokenKindOf(token).String()
 }
 
 func revokeSession(ctx context.Context, cfg *config.Config, token string) error {
-	authURL := cfg.AuthBaseURL()
-	req, err := http.NewRequestWithContext(ctx, "POST", authURL+"/api/auth/sign-out", nil)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("User-Agent", useragent.String())
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close() //nolint:errcheck
-	return nil
+	_, _, err := authRequest(ctx, "POST", cfg.AuthBaseURL()+"/api/auth/sign-out", token, nil)
+	return err
 }
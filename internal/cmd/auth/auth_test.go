@@ -0,0 +1,331 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdtest"
+	"github.com/cnap-tech/cli/internal/config"
+)
+
+func TestReadToken(t *testing.T) {
+	tests := []struct {
+		in, want, wantErr string
+	}{
+		{"cnap_pat_abc\n", "cnap_pat_abc", ""},
+		{"  cnap_pat_abc \r\n", "cnap_pat_abc", ""},
+		{"\n", "", "no token found"},
+		{"cnap_pat_abc\ncnap_pat_def\n", "", "contains whitespace"},
+		{strings.Repeat("x", maxTokenSize+1), "", "more than"},
+	}
+	for _, tt := range tests {
+		got, err := readToken(strings.NewReader(tt.in), "stdin")
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("readToken(%.20q) error = %v, want %q", tt.in, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("readToken(%.20q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestLoginTokenFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("cnap_pat_fromfile\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmdtest.Run(newCmdLogin(), "--token-file", path); err != nil {
+		t.Fatalf("login --token-file: %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Auth.Token != "cnap_pat_fromfile" {
+		t.Errorf("stored token = %q, want cnap_pat_fromfile", cfg.Auth.Token)
+	}
+
+	if err := cmdtest.Run(newCmdLogin(), "--token", "x", "--token-file", path); err == nil {
+		t.Error("--token with --token-file: want mutually exclusive error")
+	}
+}
+
+func TestLoginOverDamagedConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CNAP_API_TOKEN", "")
+	t.Setenv("CNAP_AUTH_URL", "http://127.0.0.1:1") // logout's revoke fails fast
+	path := filepath.Join(home, ".cnap", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	damaged := []byte("version: 1\napi_url: https://api.cnap.tech\nauth:\n  token: cnap_pa\noutput:\n  format: xml\n")
+	if err := os.WriteFile(path, damaged, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmdtest.Run(newCmdLogin(), "--token", "cnap_pat_good"); err != nil {
+		t.Fatalf("login over a damaged token: %v", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Auth.Token != "cnap_pat_good" {
+		t.Errorf("stored token = %q, want cnap_pat_good", cfg.Auth.Token)
+	}
+
+	if err := os.WriteFile(path, damaged, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmdtest.Run(newCmdLogout()); err != nil {
+		t.Fatalf("logout with a damaged token: %v", err)
+	}
+	if cfg, err = config.Load(); err != nil || cfg.Auth.Token != "" {
+		t.Errorf("after logout: token = %q, err = %v; want it removed", cfg.Auth.Token, err)
+	}
+}
+
+func TestLogoutRemovesClusterCredentials(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CNAP_API_TOKEN", "")
+	dir, err := config.KubeCredentialDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached := filepath.Join(dir, "0123456789abcdef", "clu_1.json")
+	if err := os.MkdirAll(filepath.Dir(cached), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cached, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmdtest.Run(newCmdLogout()); err != nil {
+		t.Fatalf("logout: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("%s after logout: %v, want it removed", dir, err)
+	}
+}
+
+func TestTokenMasking(t *testing.T) {
+	pat := "cnap_pat_0123456789abcdef0123456789abcdef"
+	if got := tokenPrefix(pat); got != "cnap_pat..." {
+		t.Errorf("tokenPrefix(PAT) = %q, want the fixed prefix only", got)
+	}
+	if got := tokenPrefix("abcdefgh"); got != "ab..." {
+		t.Errorf("tokenPrefix(short) = %q, want a quarter of the token", got)
+	}
+
+	fp := tokenFingerprint(pat)
+	if fp != tokenFingerprint(pat) || fp == tokenFingerprint(pat+"x") {
+		t.Errorf("tokenFingerprint not stable or not distinct: %q", fp)
+	}
+	if !strings.HasPrefix(fp, "sha256:") || len(fp) != len("sha256:")+16 {
+		t.Errorf("tokenFingerprint = %q", fp)
+	}
+}
+
+func TestTokenKind(t *testing.T) {
+	for _, tt := range []struct {
+		token string
+		kind  TokenKind
+		label string
+	}{
+		{"cnap_pat_abc", TokenPAT, "Personal Access Token (PAT)"},
+		{"eyJhbGciOi", TokenJWT, "JWT"},
+		{"sess_abc", TokenSession, "Session token"},
+	} {
+		if got := TokenKindOf(tt.token); got != tt.kind {
+			t.Errorf("TokenKindOf(%q) = %q, want %q", tt.token, got, tt.kind)
+		}
+		if got := TokenType(tt.token); got != tt.label {
+			t.Errorf("TokenType(%q) = %q, want %q", tt.token, got, tt.label)
+		}
+	}
+}
+
+func TestRefreshRotatesPAT(t *testing.T) {
+	var calls []string
+	var createBody api.PostV1UserTokensJSONRequestBody
+	now := float32(time.Now().Unix())
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/user":
+			expires := now + 80*86400
+			u := api.CurrentUser{Name: "Dev"}
+			u.Token = &struct {
+				ExpiresAt *float32 `json:"expires_at"`
+				Id        string   `json:"id"`
+				Name      string   `json:"name"`
+				Scopes    []string `json:"scopes"`
+			}{ExpiresAt: &expires, Id: "tok_old", Name: "ci"}
+			_ = json.NewEncoder(w).Encode(u)
+		case "GET /v1/user/tokens":
+			_ = json.NewEncoder(w).Encode(api.ApiTokenList{Data: []api.ApiToken{{Id: "tok_old", CreatedAt: now - 10*86400}}})
+		case "POST /v1/user/tokens":
+			_ = json.NewDecoder(r.Body).Decode(&createBody)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(api.CreatedToken{Id: "tok_new", Name: "ci", Token: "cnap_pat_new"})
+		case "DELETE /v1/user/tokens/tok_old":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			cmdtest.WriteError(w, http.StatusNotFound, "not found")
+		}
+	}))
+
+	if err := cmdtest.Run(newCmdRefresh()); err == nil || !strings.Contains(err.Error(), "CNAP_API_TOKEN") {
+		t.Errorf("with CNAP_API_TOKEN: err = %v, want refusal", err)
+	}
+
+	t.Setenv("CNAP_API_TOKEN", "")
+	cfg := config.DefaultConfig()
+	cfg.Auth.Token = "cnap_pat_old"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmdtest.Run(newCmdRefresh()); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if cfg, _ = config.Load(); cfg.Auth.Token != "cnap_pat_new" {
+		t.Errorf("stored token = %q, want cnap_pat_new", cfg.Auth.Token)
+	}
+	if last := calls[len(calls)-1]; last != "DELETE /v1/user/tokens/tok_old" {
+		t.Errorf("last call = %q, want the old token revoked after saving", last)
+	}
+	// The old token was valid for 90 days, so the new one should be too.
+	if createBody.Name != "ci" || createBody.ExpiresAt == nil {
+		t.Fatalf("create body = %+v", createBody)
+	}
+	if lifetime := time.Until(time.Unix(int64(*createBody.ExpiresAt), 0)); lifetime < 89*24*time.Hour || lifetime > 91*24*time.Hour {
+		t.Errorf("new token lifetime = %v, want about 90 days", lifetime)
+	}
+}
+
+func TestAuthRequestTimeout(t *testing.T) {
+	authRequestTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { authRequestTimeout = 10 * time.Second })
+
+	// The server accepts the request but doesn't answer until the test ends.
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	start := time.Now()
+	_, err := requestDeviceCode(context.Background(), srv.URL)
+	if err == nil || !strings.Contains(err.Error(), "did not respond") {
+		t.Errorf("err = %v, want auth server did not respond", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("took %s, want a timely failure", elapsed)
+	}
+}
+
+func TestVerifyAPIAccess(t *testing.T) {
+	status := http.StatusUnauthorized
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer session_tok" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(status)
+	}))
+	t.Setenv("CNAP_AUTH_URL", "https://cnap.tech")
+
+	err := verifyAPIAccess(context.Background(), config.DefaultConfig(), "session_tok")
+	if err == nil || !strings.Contains(err.Error(), "rejected the new session") || !strings.Contains(err.Error(), "CNAP_AUTH_URL") {
+		t.Errorf("err = %v, want a mismatch warning", err)
+	}
+
+	status = http.StatusOK
+	if err := verifyAPIAccess(context.Background(), config.DefaultConfig(), "session_tok"); err != nil {
+		t.Errorf("err = %v, want nil when the API accepts the session", err)
+	}
+}
+
+func TestProbeAPIWithoutToken(t *testing.T) {
+	var auth []string
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Setenv("CNAP_API_TOKEN", "")
+
+	p := probeAPI(context.Background(), config.DefaultConfig(), "")
+	if !p.Reachable || p.StatusCode != http.StatusUnauthorized {
+		t.Errorf("probe = %+v, want reachable with HTTP 401", p)
+	}
+	if len(auth) != 1 || auth[0] != "" {
+		t.Errorf("Authorization headers = %q, want one request without one", auth)
+	}
+
+	auth = nil
+	if err := cmdtest.Run(newCmdStatus(), "--check"); err != nil {
+		t.Fatalf("status --check: %v", err)
+	}
+	if len(auth) != 1 {
+		t.Errorf("status --check sent %d requests, want a probe without a token", len(auth))
+	}
+}
+
+func TestDescribeExpiry(t *testing.T) {
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		raw       string
+		wantSuf   string
+		remaining time.Duration
+		ok        bool
+	}{
+		{"2025-01-02T12:12:00.000Z", ", in 12m", 12 * time.Minute, true},
+		{"2025-01-05T12:00:00Z", ", in 3d", 72 * time.Hour, true},
+		{"2025-01-02T09:00:00Z", ", 3h ago", -3 * time.Hour, true},
+		{"next tuesday", "next tuesday", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			text, remaining, ok := describeExpiry(tt.raw, now)
+			if !strings.HasSuffix(text, tt.wantSuf) || remaining != tt.remaining || ok != tt.ok {
+				t.Errorf("describeExpiry(%q) = %q, %v, %v; want suffix %q, %v, %v", tt.raw, text, remaining, ok, tt.wantSuf, tt.remaining, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRefreshUnidentifiedPAT(t *testing.T) {
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(api.CurrentUser{Name: "Dev"})
+	}))
+	t.Setenv("CNAP_API_TOKEN", "")
+	t.Setenv("CNAP_AUTH_URL", "https://dash.example.com")
+	cfg := config.DefaultConfig()
+	cfg.Auth.Token = "cnap_pat_old"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := cmdtest.Run(newCmdRefresh())
+	if err == nil || !strings.Contains(err.Error(), "create a new one at https://dash.example.com/settings/tokens") {
+		t.Errorf("err = %v, want a link to the configured dashboard", err)
+	}
+}
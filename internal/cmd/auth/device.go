@@ -19,15 +19,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"os"
 	"os/exec"
 	"runtime"
 	"time"
 
+	"github.com/cnap-tech/cli/internal/cmdutil"
 	"github.com/cnap-tech/cli/internal/config"
+	"github.com/cnap-tech/cli/internal/debug"
+	"github.com/cnap-tech/cli/internal/env"
 	"github.com/cnap-tech/cli/internal/useragent"
 )
 
@@ -70,9 +75,12 @@ func runDeviceFlow(ctx context.Context, cfg *config.Config) error {
 	fmt.Printf("  %s\n\n", verificationURL)
 	fmt.Printf("And verify this code: %s\n\n", formatUserCode(code.UserCode))
 
-	if err := openBrowser(verificationURL); err != nil {
+	switch {
+	case env.IsOffline():
+		fmt.Println("Offline mode: not opening a browser. Waiting for authorization...")
+	case openBrowser(verificationURL) != nil:
 		fmt.Println("(Could not open browser automatically)")
-	} else {
+	default:
 		fmt.Println("Browser opened. Waiting for authorization...")
 	}
 
@@ -81,9 +89,9 @@ func runDeviceFlow(ctx context.Context, cfg *config.Config) error {
 	if interval < 5*time.Second {
 		interval = 5 * time.Second
 	}
-	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	expiresIn := time.Duration(code.ExpiresIn) * time.Second
 
-	sessionToken, err := pollForToken(ctx, authURL, code.DeviceCode, interval, deadline)
+	sessionToken, err := pollForToken(ctx, authURL, code.DeviceCode, interval, expiresIn)
 	if err != nil {
 		return err
 	}
@@ -95,98 +103,145 @@ func runDeviceFlow(ctx context.Context, cfg *config.Config) error {
 	}
 
 	fmt.Println("\nLogged in successfully. Session token saved to ~/.cnap/config.yaml")
+	if err := verifyAPIAccess(ctx, cfg, sessionToken); err != nil {
+		fmt.Fprintf(os.Stderr, "\nWarning: %s\n", err)
+	}
 	return nil
 }
 
-func requestDeviceCode(ctx context.Context, authURL string) (*deviceCodeResponse, error) {
-	body, _ := json.Marshal(map[string]string{
-		"client_id": clientID,
-	})
-	req, err := http.NewRequestWithContext(ctx, "POST", authURL+"/api/auth/device/code", bytes.NewReader(body))
+// verifyAPIAccess checks that the API commands will use accepts a token
+// issued by the auth server. When the two URLs point at different CNAP
+// instances (e.g. --api-url or CNAP_API_URL set to a self-hosted API while
+// the auth URL is the default), login succeeds but every API call is
+// rejected, so this explains how to line them up.
+func verifyAPIAccess(ctx context.Context, cfg *config.Config, token string) error {
+	probe := probeAPI(ctx, cfg, token)
+
+	var problem string
+	switch {
+	case !probe.Reachable:
+		problem = fmt.Sprintf("couldn't reach the API at %s to check the new session: %s", cfg.BaseURL(), probe.Error)
+	case probe.StatusCode == http.StatusUnauthorized || probe.StatusCode == http.StatusForbidden:
+		problem = fmt.Sprintf("the API at %s rejected the new session (HTTP %d)", cfg.BaseURL(), probe.StatusCode)
+	default:
+		return nil
+	}
+	return fmt.Errorf("%s.\nYou signed in at %s; if that's a different CNAP instance than the API, pass --auth-url, or set CNAP_AUTH_URL or auth_url in ~/.cnap/config.yaml, to the API's dashboard URL and log in again", problem, cfg.AuthBaseURL())
+}
+
+// authRequestTimeout bounds each request to the auth server, so a hung
+// server fails login instead of stalling it. Replaced in tests.
+var authRequestTimeout = 10 * time.Second
+
+// authRequest sends a request to the auth server and returns the response
+// status and body. A JSON body and a bearer token are sent when non-empty.
+// Each request gets authRequestTimeout on top of ctx's own deadline.
+func authRequest(ctx context.Context, method, url, token string, body []byte) (int, []byte, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, authRequestTimeout)
+	defer cancel()
+
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(reqCtx, method, url, r)
 	if err != nil {
-		return nil, err
+		return 0, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
-	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", useragent.String())
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := debug.Client().Do(req)
+	if err == nil {
+		defer resp.Body.Close() //nolint:errcheck
+		var data []byte
+		if data, err = io.ReadAll(resp.Body); err == nil {
+			return resp.StatusCode, data, nil
+		}
+	}
+	// Only our own timeout gets the friendly message; ctx's (--timeout)
+	// is reported by the root command.
+	if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		return 0, nil, fmt.Errorf("auth server %s did not respond within %s", req.URL.Host, authRequestTimeout)
+	}
+	return 0, nil, err
+}
+
+func requestDeviceCode(ctx context.Context, authURL string) (*deviceCodeResponse, error) {
+	body, _ := json.Marshal(map[string]string{
+		"client_id": clientID,
+	})
+	status, data, err := authRequest(ctx, "POST", authURL+"/api/auth/device/code", "", body)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close() //nolint:errcheck
 
-	if resp.StatusCode != 200 {
-		data, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(data))
+	if status != 200 {
+		return nil, fmt.Errorf("unexpected status %d: %s", status, string(data))
 	}
 
 	var result deviceCodeResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-func pollForToken(ctx context.Context, authURL, deviceCode string, interval time.Duration, deadline time.Time) (string, error) {
-	for {
-		if time.Now().After(deadline) {
-			return "", fmt.Errorf("device authorization expired — please try again")
-		}
-
-		select {
-		case <-ctx.Done():
-			return "", ctx.Err()
-		case <-time.After(interval):
-		}
-
-		body, _ := json.Marshal(map[string]string{
-			"grant_type":  "urn:ietf:params:oauth:grant-type:device_code",
-			"device_code": deviceCode,
-			"client_id":   clientID,
-		})
-
-		req, err := http.NewRequestWithContext(ctx, "POST", authURL+"/api/auth/device/token", bytes.NewReader(body))
-		if err != nil {
-			return "", fmt.Errorf("polling for token: %w", err)
-		}
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("User-Agent", useragent.String())
+// pollForToken polls for the session token until the user approves the
+// device code, backing off when the server asks to slow down, until the
+// code expires after expiresIn.
+func pollForToken(ctx context.Context, authURL, deviceCode string, interval, expiresIn time.Duration) (string, error) {
+	body, _ := json.Marshal(map[string]string{
+		"grant_type":  "urn:ietf:params:oauth:grant-type:device_code",
+		"device_code": deviceCode,
+		"client_id":   clientID,
+	})
 
-		resp, err := http.DefaultClient.Do(req)
+	var token string
+	poll := &cmdutil.PollStrategy{Interval: interval, Timeout: expiresIn}
+	err := cmdutil.PollUntil(ctx, poll, func(ctx context.Context) (bool, error) {
+		status, data, err := authRequest(ctx, "POST", authURL+"/api/auth/device/token", "", body)
 		if err != nil {
-			return "", fmt.Errorf("polling for token: %w", err)
+			return false, fmt.Errorf("polling for token: %w", err)
 		}
 
-		data, _ := io.ReadAll(resp.Body)
-		resp.Body.Close() //nolint:errcheck
-
-		if resp.StatusCode == 200 {
+		if status == 200 {
 			var tokenResp deviceTokenResponse
 			if err := json.Unmarshal(data, &tokenResp); err != nil {
-				return "", fmt.Errorf("parsing token response: %w", err)
+				return false, fmt.Errorf("parsing token response: %w", err)
 			}
-			return tokenResp.AccessToken, nil
+			token = tokenResp.AccessToken
+			return true, nil
 		}
 
 		var errResp deviceTokenError
 		if err := json.Unmarshal(data, &errResp); err != nil {
-			return "", fmt.Errorf("parsing error response: %w", err)
+			return false, fmt.Errorf("parsing error response: %w", err)
 		}
 
 		switch errResp.Error {
 		case "authorization_pending":
-			continue
+			return false, nil
 		case "slow_down":
-			interval += 5 * time.Second
-			continue
+			poll.Interval += 5 * time.Second
+			return false, nil
 		case "expired_token":
-			return "", fmt.Errorf("device code expired — please try again")
+			return false, fmt.Errorf("device code expired — please try again")
 		case "access_denied":
-			return "", fmt.Errorf("authorization was denied")
+			return false, fmt.Errorf("authorization was denied")
 		default:
-			return "", fmt.Errorf("unexpected error: %s — %s", errResp.Error, errResp.ErrorDescription)
+			return false, fmt.Errorf("unexpected error: %s — %s", errResp.Error, errResp.ErrorDescription)
 		}
+	})
+	if errors.Is(err, cmdutil.ErrPollTimeout) {
+		return "", fmt.Errorf("device authorization expired — please try again")
 	}
+	return token, err
 }
 
 func formatUserCode(code string) string {
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newCmdRefresh() *cobra.Command {
+	var show bool
+
+	cmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Rotate the stored token",
+		Long: `Rotates the stored credentials without a full re-login.
+
+For a Personal Access Token, creates a new PAT with the same name and
+lifetime, stores it, then revokes the old one. The new token's secret is
+only printed with --show.
+
+For a session token, renews the session on the auth server and reports
+its new expiry.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if os.Getenv("CNAP_API_TOKEN") != "" {
+				return fmt.Errorf("the token comes from CNAP_API_TOKEN; rotate it where that variable is set")
+			}
+
+			client, cfg, err := cmdutil.NewClient()
+			if err != nil {
+				return err
+			}
+
+			token := cfg.Token()
+			switch TokenKindOf(token) {
+			case TokenPAT:
+				return rotatePAT(cmd.Context(), client, cfg, show)
+			case TokenSession:
+				expiresAt, err := getSession(cmd.Context(), cfg, token)
+				if err != nil {
+					return fmt.Errorf("refreshing session: %w. Run: cnap auth login", err)
+				}
+				fmt.Printf("Session refreshed (expires: %s).\n", expiresAt)
+				return nil
+			default:
+				return fmt.Errorf("JWTs can't be refreshed. Run: cnap auth login")
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&show, "show", false, "Print the new token")
+
+	return cmd
+}
+
+// rotatePAT replaces the current PAT with a new one of the same name and
+// lifetime. The new token is saved before the old one is revoked, so a
+// failed revoke leaves a working login behind.
+func rotatePAT(ctx context.Context, client *api.ClientWithResponses, cfg *config.Config, show bool) error {
+	userResp, err := client.GetV1UserWithResponse(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching current token: %w", err)
+	}
+	if userResp.JSON200 == nil {
+		return cmdutil.APIError(userResp.Status(), userResp.Body, userResp.JSON401)
+	}
+	current := userResp.JSON200.Token
+	if current == nil {
+		return fmt.Errorf("the API didn't identify the current PAT; create a new one at %s", config.TokensURL(cfg.AuthBaseURL()))
+	}
+
+	body := api.PostV1UserTokensJSONRequestBody{Name: current.Name}
+	if current.ExpiresAt != nil {
+		expiresAt := int(*current.ExpiresAt)
+		if lifetime, ok := tokenLifetime(ctx, client, current.Id, *current.ExpiresAt); ok {
+			expiresAt = int(time.Now().Add(lifetime).Unix())
+		}
+		body.ExpiresAt = &expiresAt
+	}
+
+	createResp, err := client.PostV1UserTokensWithResponse(ctx, body)
+	if err != nil {
+		return fmt.Errorf("creating token: %w", err)
+	}
+	if createResp.JSON201 == nil {
+		return cmdutil.APIError(createResp.Status(), createResp.Body, createResp.JSON401)
+	}
+	created := createResp.JSON201
+
+	cfg.Auth.Token = created.Token
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("saving config: %w (new token %s was created; revoke it or store it manually)", err, created.Id)
+	}
+
+	deleteResp, err := client.DeleteV1UserTokensIdWithResponse(ctx, current.Id)
+	if err == nil {
+		if _, ok := cmdutil.Succeeded(deleteResp.StatusCode(), deleteResp.Body, cmdutil.DeleteStatuses...); !ok {
+			err = cmdutil.APIError(deleteResp.Status(), deleteResp.Body, deleteResp.JSON401, deleteResp.JSON404)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("new token saved, but revoking the old one (%s) failed: %w", current.Id, err)
+	}
+
+	fmt.Printf("Rotated token %q: %s replaces %s (revoked).\n", created.Name, created.Id, current.Id)
+	if show {
+		fmt.Println(created.Token)
+	}
+	return nil
+}
+
+// tokenLifetime returns how long the token was originally valid for, so
+// the replacement gets the same lifetime from now. It reports false if
+// the token can't be found in the user's token list.
+func tokenLifetime(ctx context.Context, client *api.ClientWithResponses, id string, expiresAt float32) (time.Duration, bool) {
+	limit := 100
+	tokens, err := cmdutil.ListAll(func(cursor *string) ([]api.ApiToken, *api.Pagination, error) {
+		resp, err := client.GetV1UserTokensWithResponse(ctx, &api.GetV1UserTokensParams{Limit: &limit, Cursor: cursor})
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401)
+		}
+		return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+	})
+	if err != nil {
+		slog.Debug("listing tokens", "error", err)
+		return 0, false
+	}
+	for _, t := range tokens {
+		if t.Id == id {
+			return time.Duration(expiresAt-t.CreatedAt) * time.Second, true
+		}
+	}
+	return 0, false
+}
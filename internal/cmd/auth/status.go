@@ -0,0 +1,361 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/config"
+	"github.com/cnap-tech/cli/internal/debug"
+	"github.com/cnap-tech/cli/internal/namecache"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/useragent"
+	"github.com/spf13/cobra"
+)
+
+// statusReport is what `cnap auth status` reports. Its JSON form is meant
+// for scripts, so the token itself never appears — only its type, a short
+// prefix, its length and a fingerprint.
+type statusReport struct {
+	Authenticated    bool         `json:"authenticated"`
+	TokenType        string       `json:"token_type,omitempty"`
+	TokenPrefix      string       `json:"token_prefix,omitempty"`
+	TokenLength      int          `json:"token_length,omitempty"`
+	TokenFingerprint string       `json:"token_fingerprint,omitempty"`
+	APIURL           string       `json:"api_url"`
+	AuthURL          string       `json:"auth_url"`
+	ActiveWorkspace  string       `json:"active_workspace,omitempty"`
+	Session          *sessionInfo `json:"session,omitempty"`
+	PAT              *patInfo     `json:"pat,omitempty"`
+	API              *apiProbe    `json:"api,omitempty"`
+}
+
+type sessionInfo struct {
+	Active       bool   `json:"active"`
+	ExpiresAt    string `json:"expires_at,omitempty"`
+	ExpiringSoon bool   `json:"expiring_soon,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+type patInfo struct {
+	Active       bool     `json:"active"`
+	User         string   `json:"user,omitempty"`
+	Email        string   `json:"email,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	ExpiresAt    string   `json:"expires_at,omitempty"`
+	ExpiringSoon bool     `json:"expiring_soon,omitempty"`
+	Error        string   `json:"error,omitempty"`
+}
+
+type apiProbe struct {
+	Reachable  bool   `json:"reachable"`
+	StatusCode int    `json:"status_code,omitempty"`
+	LatencyMs  int64  `json:"latency_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func newCmdStatus() *cobra.Command {
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show authentication status",
+		Long: `Show the stored token, configured URLs, and active workspace.
+
+With --check, also probes the API base URL and reports whether it is
+reachable and the round-trip latency, logged in or not. Use -o json for
+machine-readable output.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+
+			r := &statusReport{
+				APIURL:          cfg.BaseURL(),
+				AuthURL:         cfg.AuthBaseURL(),
+				ActiveWorkspace: cfg.ActiveWorkspace,
+			}
+
+			token := cfg.Token()
+			if token != "" {
+				kind := TokenKindOf(token)
+				r.Authenticated = true
+				r.TokenType = kind.String()
+
+				r.TokenPrefix = tokenPrefix(token)
+				r.TokenLength = len(token)
+				r.TokenFingerprint = tokenFingerprint(token)
+
+				switch kind {
+				case TokenSession:
+					r.Session = checkSessionStatus(cmd.Context(), cfg, token)
+				case TokenPAT:
+					r.PAT = checkPATStatus(cmd.Context())
+				}
+			}
+			if check {
+				r.API = probeAPI(cmd.Context(), cfg, token)
+			}
+
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+			if format.IsJSON() {
+				return output.PrintData(format, r)
+			}
+			printStatus(r)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "Probe the API and report reachability and latency")
+
+	return cmd
+}
+
+// tokenPrefixLen is how much of the token auth status shows. For PATs it's
+// exactly the fixed "cnap_pat" prefix, so no secret characters are shown.
+const tokenPrefixLen = 8
+
+// tokenPrefix returns the first tokenPrefixLen characters of token, or at
+// most a quarter of a short token.
+func tokenPrefix(token string) string {
+	n := min(tokenPrefixLen, len(token)/4)
+	return token[:n] + "..."
+}
+
+// tokenFingerprint identifies a token without revealing it: the first 16
+// hex digits of its SHA-256, stable across runs.
+func tokenFingerprint(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "sha256:" + hex.EncodeToString(sum[:])[:16]
+}
+
+func printStatus(r *statusReport) {
+	if !r.Authenticated {
+		fmt.Println("Not authenticated. Run: cnap auth login")
+		printAPIProbe(r.API)
+		return
+	}
+
+	fmt.Printf("Token type: %s\n", r.TokenType)
+	fmt.Printf("Token: %s (%d chars)\n", r.TokenPrefix, r.TokenLength)
+	fmt.Printf("Token fingerprint: %s\n", r.TokenFingerprint)
+	fmt.Printf("API URL: %s\n", r.APIURL)
+	fmt.Printf("Auth URL: %s\n", r.AuthURL)
+
+	now := time.Now()
+	if s := r.Session; s != nil {
+		if s.Active {
+			expires, remaining, ok := describeExpiry(s.ExpiresAt, now)
+			fmt.Printf("Session status: active (expires: %s)\n", expires)
+			switch {
+			case ok && remaining <= 0:
+				// The server still accepts the session, so the local clock is off.
+				fmt.Println("Warning: the session has expired by this machine's clock but the server still accepts it; check the system clock.")
+			case s.ExpiringSoon:
+				fmt.Println("Warning: session expires soon. Run 'cnap auth login' to renew it.")
+			}
+		} else {
+			fmt.Printf("Session status: invalid or expired (%s)\n", s.Error)
+			fmt.Println("Run 'cnap auth login' to re-authenticate.")
+		}
+	}
+
+	if p := r.PAT; p != nil {
+		switch {
+		case !p.Active:
+			fmt.Printf("Token status: invalid, expired, or revoked (%s)\n", p.Error)
+			fmt.Println("Run 'cnap auth login' to re-authenticate.")
+		case p.Name == "":
+			fmt.Printf("User: %s <%s>\n", p.User, p.Email)
+			fmt.Println("Token status: active")
+		default:
+			expires := "never"
+			if p.ExpiresAt != "" {
+				expires, _, _ = describeExpiry(p.ExpiresAt, now)
+			}
+			fmt.Printf("User: %s <%s>\n", p.User, p.Email)
+			fmt.Printf("Token status: active (name: %s, expires: %s)\n", p.Name, expires)
+			if len(p.Scopes) > 0 {
+				fmt.Printf("Token scopes: %s\n", strings.Join(p.Scopes, ", "))
+			}
+			if p.ExpiringSoon {
+				fmt.Printf("Warning: token expires soon. Create a new one at %s\n", config.TokensURL(r.AuthURL))
+			}
+		}
+	}
+
+	if r.ActiveWorkspace != "" {
+		if name, ok := namecache.Lookup(namecache.Workspaces, r.ActiveWorkspace); ok {
+			fmt.Printf("Active workspace: %s (%s)\n", name, r.ActiveWorkspace)
+		} else {
+			fmt.Printf("Active workspace: %s\n", r.ActiveWorkspace)
+		}
+	} else {
+		fmt.Println("No active workspace. Run: cnap workspaces switch <id>")
+	}
+
+	printAPIProbe(r.API)
+}
+
+// printAPIProbe prints the --check result, if there is one.
+func printAPIProbe(a *apiProbe) {
+	if a == nil {
+		return
+	}
+	if a.Reachable {
+		fmt.Printf("API reachability: reachable (HTTP %d, %dms)\n", a.StatusCode, a.LatencyMs)
+	} else {
+		fmt.Printf("API reachability: unreachable (%s)\n", a.Error)
+	}
+}
+
+func checkSessionStatus(ctx context.Context, cfg *config.Config, token string) *sessionInfo {
+	expiresAt, err := getSession(ctx, cfg, token)
+	if err != nil {
+		return &sessionInfo{Error: err.Error()}
+	}
+	info := &sessionInfo{Active: true, ExpiresAt: expiresAt}
+	if t, err := time.Parse(time.RFC3339, expiresAt); err == nil {
+		info.ExpiringSoon = time.Until(t) < sessionExpiryWarning
+	}
+	return info
+}
+
+// sessionExpiryWarning is how far ahead of expiry auth status starts
+// warning about a session.
+const sessionExpiryWarning = 24 * time.Hour
+
+// describeExpiry renders an RFC 3339 expiry in local time with the time
+// left by the local clock, e.g. "2025-01-02 09:00:00 CET, in 12m" or
+// "..., 3h ago". A timestamp that doesn't parse is returned as is, with ok
+// false.
+func describeExpiry(raw string, now time.Time) (text string, remaining time.Duration, ok bool) {
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return raw, 0, false
+	}
+	remaining = t.Sub(now)
+	abs := t.Local().Format("2006-01-02 15:04:05 MST")
+	if remaining <= 0 {
+		return abs + ", " + shortDuration(-remaining) + " ago", remaining, true
+	}
+	return abs + ", in " + shortDuration(remaining), remaining, true
+}
+
+// shortDuration renders d in its largest whole unit, e.g. "45s", "12m",
+// "5h" or "3d".
+func shortDuration(d time.Duration) string {
+	switch {
+	case d < 2*time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < 2*time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// getSession looks up the session on the auth server and returns its expiry.
+func getSession(ctx context.Context, cfg *config.Config, token string) (string, error) {
+	status, data, err := authRequest(ctx, "GET", cfg.AuthBaseURL()+"/api/auth/get-session", token, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if status != 200 {
+		return "", fmt.Errorf("HTTP %d", status)
+	}
+
+	var result struct {
+		Session *struct {
+			ExpiresAt string `json:"expiresAt"`
+		} `json:"session"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", err
+	}
+	if result.Session == nil {
+		return "", fmt.Errorf("session not found or expired")
+	}
+	return result.Session.ExpiresAt, nil
+}
+
+// patExpiryWarning is how far ahead of expiry auth status starts warning.
+const patExpiryWarning = 7 * 24 * time.Hour
+
+// checkPATStatus validates the PAT against the API and reports its name,
+// scopes, and expiry.
+func checkPATStatus(ctx context.Context) *patInfo {
+	client, _, err := cmdutil.NewClient()
+	if err != nil {
+		return &patInfo{Error: err.Error()}
+	}
+
+	resp, err := client.GetV1UserWithResponse(ctx)
+	if err != nil {
+		return &patInfo{Error: err.Error()}
+	}
+	if resp.JSON200 == nil {
+		if resp.JSON401 != nil {
+			return &patInfo{Error: resp.JSON401.Error.Message}
+		}
+		return &patInfo{Error: fmt.Sprintf("HTTP %d", resp.StatusCode())}
+	}
+
+	u := resp.JSON200
+	info := &patInfo{Active: true, User: u.Name, Email: u.Email}
+	if u.Token != nil {
+		info.Name = u.Token.Name
+		info.Scopes = u.Token.Scopes
+		if u.Token.ExpiresAt != nil {
+			expiresAt := time.Unix(int64(*u.Token.ExpiresAt), 0)
+			info.ExpiresAt = expiresAt.Local().Format(time.RFC3339)
+			info.ExpiringSoon = time.Until(expiresAt) < patExpiryWarning
+		}
+	}
+	return info
+}
+
+// probeTimeout bounds the API reachability probe in auth status --check.
+const probeTimeout = 5 * time.Second
+
+// probeAPI sends a request to the API and measures the round-trip time,
+// authenticated unless token is empty. Any HTTP response counts as
+// reachable, so a 401 without a token still answers "is it me or the
+// server?".
+func probeAPI(ctx context.Context, cfg *config.Config, token string) *apiProbe {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", cfg.BaseURL()+"/v1/user", nil)
+	if err != nil {
+		return &apiProbe{Error: err.Error()}
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("User-Agent", useragent.String())
+
+	start := time.Now()
+	resp, err := debug.Client().Do(req)
+	if err != nil {
+		return &apiProbe{Error: err.Error()}
+	}
+	latency := time.Since(start)
+	_ = resp.Body.Close()
+
+	return &apiProbe{Reachable: true, StatusCode: resp.StatusCode, LatencyMs: latency.Milliseconds()}
+}
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
-	"strings"
 
 	"github.com/cnap-tech/cli/internal/api"
 	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/config"
+	"github.com/cnap-tech/cli/internal/namecache"
 	"github.com/cnap-tech/cli/internal/output"
 	"github.com/cnap-tech/cli/internal/prompt"
 	"github.com/spf13/cobra"
@@ -27,6 +29,7 @@ func NewCmdClusters() *cobra.Command {
 	cmd.AddCommand(newCmdUpdate())
 	cmd.AddCommand(newCmdDelete())
 	cmd.AddCommand(newCmdKubeconfig())
+	cmd.AddCommand(newCmdEvents())
 
 	return cmd
 }
@@ -34,6 +37,7 @@ func NewCmdClusters() *cobra.Command {
 func newCmdList() *cobra.Command {
 	var limit int
 	var cursor string
+	var all bool
 
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -45,31 +49,39 @@ func newCmdList() *cobra.Command {
 				return err
 			}
 
-			if cfg.ActiveWorkspace == "" {
-				return fmt.Errorf("no active workspace. Run: cnap workspaces switch <id>")
+			if err := cmdutil.RequireWorkspace(cfg); err != nil {
+				return err
 			}
 
-			params := &api.GetV1ClustersParams{Limit: &limit}
-			if cursor != "" {
-				params.Cursor = &cursor
+			items, page, listErr := cmdutil.ListPages(cursor, all, func(cursor *string) ([]api.Cluster, *api.Pagination, error) {
+				resp, err := client.GetV1ClustersWithResponse(cmd.Context(), &api.GetV1ClustersParams{Limit: &limit, Cursor: cursor})
+				if err != nil {
+					return nil, nil, fmt.Errorf("fetching clusters: %w", err)
+				}
+				if resp.JSON200 == nil {
+					return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
+				}
+				return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+			})
+			if listErr != nil && !cmdutil.IsPartial(listErr) {
+				return listErr
 			}
+			cacheClusterNames(items...)
 
-			resp, err := client.GetV1ClustersWithResponse(cmd.Context(), params)
+			format, err := cmdutil.GetOutputFormat(cfg)
 			if err != nil {
-				return fmt.Errorf("fetching clusters: %w", err)
-			}
-			if resp.JSON200 == nil {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON403)
+				return err
 			}
-
-			format := cmdutil.GetOutputFormat(cfg)
-			if format == output.FormatJSON {
-				return output.PrintJSON(resp.JSON200)
+			if format.IsJSON() {
+				if err := output.PrintData(format, cmdutil.NewListOutput(items, page)); err != nil {
+					return err
+				}
+				return listErr
 			}
 
 			header := []string{"ID", "NAME", "REGION", "TYPE", "STATUS"}
 			var rows [][]string
-			for _, c := range resp.JSON200.Data {
+			for _, c := range items {
 				clusterType := "imported"
 				status := "-"
 				if c.Kaas != nil {
@@ -81,19 +93,18 @@ func newCmdList() *cobra.Command {
 
 			if len(rows) == 0 {
 				fmt.Println("No clusters found in this workspace.")
-				return nil
+				return listErr
 			}
 
-			output.PrintTable(header, rows)
-			if resp.JSON200.Pagination.HasMore {
-				fmt.Printf("\nMore results available. Use --cursor %s to see next page.\n", *resp.JSON200.Pagination.Cursor)
-			}
-			return nil
+			output.PrintStyledTable(header, rows, map[string]output.StyleFunc{"STATUS": output.StatusStyle})
+			cmdutil.PrintPageHint(format, page)
+			return listErr
 		},
 	}
 
 	cmd.Flags().IntVar(&limit, "limit", 50, "Items per page (1-100)")
 	cmd.Flags().StringVar(&cursor, "cursor", "", "Pagination cursor from previous response")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch every page (--limit sets the page size)")
 
 	return cmd
 }
@@ -117,7 +128,7 @@ func newCmdGet() *cobra.Command {
 			if len(args) > 0 {
 				clusterID = args[0]
 			} else {
-				clusterID, err = pickCluster(cmd.Context(), client)
+				clusterID, err = pickCluster(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
@@ -128,12 +139,16 @@ func newCmdGet() *cobra.Command {
 				return fmt.Errorf("fetching cluster: %w", err)
 			}
 			if resp.JSON200 == nil {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON403)
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403, resp.JSON404)
 			}
+			cacheClusterNames(*resp.JSON200)
 
-			format := cmdutil.GetOutputFormat(cfg)
-			if format == output.FormatJSON {
-				return output.PrintJSON(resp.JSON200)
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+			if format.IsJSON() {
+				return output.PrintData(format, resp.JSON200)
 			}
 
 			c := resp.JSON200
@@ -179,7 +194,7 @@ func newCmdUpdate() *cobra.Command {
 				return fmt.Errorf("at least one of --name or --region is required")
 			}
 
-			client, _, err := cmdutil.NewClient()
+			client, cfg, err := cmdutil.NewClient()
 			if err != nil {
 				return err
 			}
@@ -188,7 +203,7 @@ func newCmdUpdate() *cobra.Command {
 			if len(args) > 0 {
 				clusterID = args[0]
 			} else {
-				clusterID, err = pickCluster(cmd.Context(), client)
+				clusterID, err = pickCluster(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
@@ -207,7 +222,7 @@ func newCmdUpdate() *cobra.Command {
 				return fmt.Errorf("updating cluster: %w", err)
 			}
 			if resp.JSON200 == nil {
-				return fmt.Errorf("unexpected response: %s", resp.Status())
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403, resp.JSON404, resp.JSON422)
 			}
 
 			fmt.Printf("Cluster %s updated.\n", resp.JSON200.Name)
@@ -233,7 +248,7 @@ func newCmdDelete() *cobra.Command {
 				return fmt.Errorf("<cluster-id> argument required when not running interactively")
 			}
 
-			client, _, err := cmdutil.NewClient()
+			client, cfg, err := cmdutil.NewClient()
 			if err != nil {
 				return err
 			}
@@ -242,7 +257,7 @@ func newCmdDelete() *cobra.Command {
 			if len(args) > 0 {
 				clusterID = args[0]
 			} else {
-				clusterID, err = pickCluster(cmd.Context(), client)
+				clusterID, err = pickCluster(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
@@ -252,7 +267,7 @@ func newCmdDelete() *cobra.Command {
 				if !prompt.IsInteractive() {
 					return fmt.Errorf("use --yes to confirm deletion in non-interactive mode")
 				}
-				confirmed, err := prompt.Confirm(fmt.Sprintf("Delete cluster %s?", clusterID))
+				confirmed, err := prompt.Confirm(fmt.Sprintf("Delete cluster %s?", displayName(clusterID)))
 				if err != nil {
 					return err
 				}
@@ -266,11 +281,13 @@ func newCmdDelete() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("deleting cluster: %w", err)
 			}
-			if resp.HTTPResponse.StatusCode != 204 {
-				return fmt.Errorf("unexpected response: %s", resp.Status())
+			job, ok := cmdutil.Succeeded(resp.StatusCode(), resp.Body, cmdutil.DeleteStatuses...)
+			if !ok {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403, resp.JSON404, resp.JSON409)
 			}
 
-			fmt.Printf("Cluster %s deleted.\n", clusterID)
+			namecache.Forget(namecache.Clusters, clusterID)
+			cmdutil.PrintDeleted("Cluster "+clusterID, job)
 			return nil
 		},
 	}
@@ -282,18 +299,30 @@ func newCmdDelete() *cobra.Command {
 
 func newCmdKubeconfig() *cobra.Command {
 	var outputFile string
+	var execCredential bool
 
 	cmd := &cobra.Command{
 		Use:   "kubeconfig [cluster-id]",
 		Short: "Get cluster admin kubeconfig",
-		Long:  "Downloads the admin kubeconfig for a KaaS-managed cluster. The cluster must be running.",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Downloads the admin kubeconfig for a KaaS-managed cluster. The cluster must be running.
+
+With --exec-credential the kubeconfig file holds no credentials. Instead
+its user runs "cnap kube credential <cluster-id>" (a client-go exec
+plugin), so kubectl fetches them on demand with your CNAP login. cnap must
+be on kubectl's PATH.
+
+The credential fetched is still the cluster's long-lived admin client
+certificate (or token), not a short-lived one: logging out or revoking
+your CNAP token stops new fetches, but a certificate already handed to
+kubectl or cached in ~/.cnap/kube/ stays valid until the cluster's
+credentials are rotated.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 && !prompt.IsInteractive() {
 				return fmt.Errorf("<cluster-id> argument required when not running interactively")
 			}
 
-			client, _, err := cmdutil.NewClient()
+			client, cfg, err := cmdutil.NewClient()
 			if err != nil {
 				return err
 			}
@@ -302,32 +331,27 @@ func newCmdKubeconfig() *cobra.Command {
 			if len(args) > 0 {
 				clusterID = args[0]
 			} else {
-				clusterID, err = pickCluster(cmd.Context(), client)
+				clusterID, err = pickCluster(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
 			}
 
-			resp, err := client.GetV1ClustersIdKubeconfig(cmd.Context(), clusterID)
-			if err != nil {
-				return fmt.Errorf("fetching kubeconfig: %w", err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			body, err := io.ReadAll(resp.Body)
+			body, err := fetchKubeconfig(cmd.Context(), client, clusterID)
 			if err != nil {
-				return fmt.Errorf("reading response: %w", err)
+				return err
 			}
-
-			if resp.StatusCode != 200 {
-				var apiErr api.Error
-				if json.Unmarshal(body, &apiErr) == nil {
-					return fmt.Errorf("%s", apiErr.Error.Message)
+			if execCredential {
+				if body, err = execKubeconfig(body, clusterID); err != nil {
+					return err
 				}
-				return fmt.Errorf("unexpected response: %s", resp.Status)
 			}
 
 			if outputFile != "" {
+				outputFile, err := cmdutil.ExpandPath(outputFile)
+				if err != nil {
+					return err
+				}
 				if err := os.WriteFile(outputFile, body, 0600); err != nil {
 					return fmt.Errorf("writing kubeconfig: %w", err)
 				}
@@ -341,39 +365,71 @@ func newCmdKubeconfig() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "Write kubeconfig to file (mode 0600)")
+	cmd.Flags().BoolVar(&execCredential, "exec-credential", false, "Fetch credentials on demand through cnap instead of embedding them")
 
 	return cmd
 }
 
+// fetchKubeconfig downloads the cluster's admin kubeconfig.
+func fetchKubeconfig(ctx context.Context, client *api.ClientWithResponses, clusterID string) ([]byte, error) {
+	resp, err := client.GetV1ClustersIdKubeconfig(ctx, clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching kubeconfig: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		if resp.StatusCode == http.StatusNotFound && !json.Valid(body) {
+			return nil, fmt.Errorf("cluster %q not found", clusterID)
+		}
+		return nil, cmdutil.APIError(resp.Status, body)
+	}
+	return body, nil
+}
+
 // pickCluster shows an interactive cluster picker. Returns the selected cluster ID.
-func pickCluster(ctx context.Context, client *api.ClientWithResponses) (string, error) {
+// It lists the active workspace, so it requires one.
+func pickCluster(ctx context.Context, client *api.ClientWithResponses, cfg *config.Config) (string, error) {
+	if err := cmdutil.RequireWorkspace(cfg); err != nil {
+		return "", err
+	}
 	limit := 100
 	listResp, err := client.GetV1ClustersWithResponse(ctx, &api.GetV1ClustersParams{Limit: &limit})
 	if err != nil {
 		return "", fmt.Errorf("fetching clusters: %w", err)
 	}
 	if listResp.JSON200 == nil {
-		return "", apiError(listResp.Status(), listResp.JSON401, listResp.JSON403)
+		return "", cmdutil.APIError(listResp.Status(), listResp.Body, listResp.JSON401, listResp.JSON403)
 	}
 	if len(listResp.JSON200.Data) == 0 {
 		return "", fmt.Errorf("no clusters found in this workspace")
 	}
+	cacheClusterNames(listResp.JSON200.Data...)
 	options := make([]prompt.SelectOption, len(listResp.JSON200.Data))
 	for i, c := range listResp.JSON200.Data {
 		options[i] = prompt.SelectOption{Label: c.Name + " (" + c.Id + ")", Value: c.Id}
 	}
-	return prompt.Select("Select a cluster", options)
+	return prompt.Select("Select a cluster", options, prompt.WithCancel())
 }
 
-func apiError(status string, errs ...*api.Error) error {
-	for _, e := range errs {
-		if e != nil {
-			parts := []string{e.Error.Message}
-			if e.Error.Suggestion != nil {
-				parts = append(parts, *e.Error.Suggestion)
-			}
-			return fmt.Errorf("%s", strings.Join(parts, ". "))
-		}
+// cacheClusterNames records cluster names for later display without a lookup.
+func cacheClusterNames(clusters ...api.Cluster) {
+	names := make(map[string]string, len(clusters))
+	for _, c := range clusters {
+		names[c.Id] = c.Name
+	}
+	namecache.Store(namecache.Clusters, names)
+}
+
+// displayName renders "name (id)" when the cluster name is cached, else just the ID.
+func displayName(clusterID string) string {
+	if name, ok := namecache.Lookup(namecache.Clusters, clusterID); ok {
+		return name + " (" + clusterID + ")"
 	}
-	return fmt.Errorf("unexpected response: %s", status)
+	return clusterID
 }
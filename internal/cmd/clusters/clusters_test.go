@@ -0,0 +1,178 @@
+package clusters
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/cmdtest"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func TestNotFound(t *testing.T) {
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.WriteError(w, http.StatusNotFound, "Cluster not found")
+	}))
+
+	tests := []struct {
+		name string
+		cmd  func() *cobra.Command
+		args []string
+	}{
+		{"get", newCmdGet, []string{"missing"}},
+		{"update", newCmdUpdate, []string{"missing", "--name", "staging"}},
+		{"delete", newCmdDelete, []string{"missing", "--yes"}},
+		{"kubeconfig", newCmdKubeconfig, []string{"missing"}},
+		{"events", newCmdEvents, []string{"missing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cmdtest.Run(tt.cmd(), tt.args...)
+			if err == nil || !strings.Contains(err.Error(), "Cluster not found") {
+				t.Errorf("got error %v, want %q", err, "Cluster not found")
+			}
+		})
+	}
+}
+
+func TestKubeconfigNotFoundWithoutBody(t *testing.T) {
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	err := cmdtest.Run(newCmdKubeconfig(), "missing")
+	if err == nil || err.Error() != `cluster "missing" not found` {
+		t.Errorf("got error %v, want cluster not found", err)
+	}
+}
+
+func TestEventsRequiresKaaS(t *testing.T) {
+	var paths []string
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"id":"cls_1","name":"byo","workspace_id":"ws_1","region_id":"r","kaas":null,"created_at":0}`)
+	}))
+
+	err := cmdtest.Run(newCmdEvents(), "cls_1")
+	if err == nil || !strings.Contains(err.Error(), "not KaaS-managed") {
+		t.Errorf("got error %v, want not KaaS-managed", err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("requests = %v, want only the cluster lookup", paths)
+	}
+}
+
+const adminKubeconfig = `apiVersion: v1
+kind: Config
+current-context: admin@prod
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com:6443
+contexts:
+- name: admin@prod
+  context:
+    cluster: prod
+    user: admin
+users:
+- name: admin
+  user:
+    client-certificate-data: Q0VSVA==
+    client-key-data: S0VZ
+`
+
+func TestKubeconfigExecCredential(t *testing.T) {
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, adminKubeconfig)
+	}))
+
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := cmdtest.Run(newCmdKubeconfig(), "clu_1", "--exec-credential", "-o", path); err != nil {
+		t.Fatalf("kubeconfig: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"server: https://prod.example.com:6443", "command: cnap", "- credential", "- clu_1", "interactiveMode: Never"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("kubeconfig missing %q:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "client-key-data") {
+		t.Errorf("kubeconfig still embeds credentials:\n%s", data)
+	}
+}
+
+func TestNewExecCredential(t *testing.T) {
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+	cred, err := newExecCredential([]byte(adminKubeconfig), now)
+	if err != nil {
+		t.Fatalf("newExecCredential: %v", err)
+	}
+	if cred.Kind != "ExecCredential" || cred.APIVersion != execCredentialAPIVersion {
+		t.Errorf("kind/apiVersion = %s %s", cred.Kind, cred.APIVersion)
+	}
+	if s := cred.Status; s.ClientCertificateData != "CERT" || s.ClientKeyData != "KEY" || s.ExpirationTimestamp != "2025-01-02T12:10:00Z" {
+		t.Errorf("status = %+v", s)
+	}
+
+	if _, err := newExecCredential([]byte("users: []\n"), now); err == nil {
+		t.Error("want error for a kubeconfig without credentials")
+	}
+}
+
+func TestClusterCredentialCache(t *testing.T) {
+	requests := 0
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = io.WriteString(w, adminKubeconfig)
+	}))
+
+	now := time.Now()
+	for _, at := range []time.Time{now, now.Add(5 * time.Minute), now.Add(credentialTTL - credentialMinValidity/2)} {
+		if _, err := clusterCredential(context.Background(), "clu_1", at); err != nil {
+			t.Fatalf("clusterCredential: %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2 (cached, then refreshed near expiry)", requests)
+	}
+
+	cfg, err := cmdutil.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := credentialPath(cfg, "clu_1")
+	if info, err := os.Stat(p); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("cache file %s: %v, %v; want mode 0600", p, info, err)
+	}
+	if info, err := os.Stat(filepath.Dir(p)); err != nil || info.Mode().Perm() != 0700 {
+		t.Errorf("cache dir %s: %v, %v; want mode 0700", filepath.Dir(p), info, err)
+	}
+
+	// Another login never reuses the cached admin key.
+	t.Setenv("CNAP_API_TOKEN", "cnap_pat_other")
+	if _, err := clusterCredential(context.Background(), "clu_1", now); err != nil {
+		t.Fatalf("clusterCredential: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3 (a new token misses the cache)", requests)
+	}
+}
+
+func TestPickClusterRequiresWorkspace(t *testing.T) {
+	_, err := pickCluster(context.Background(), nil, &config.Config{})
+	if err == nil || !strings.Contains(err.Error(), "no active workspace") {
+		t.Errorf("got error %v, want no active workspace", err)
+	}
+}
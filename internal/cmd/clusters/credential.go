@@ -0,0 +1,255 @@
+package clusters
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// execCredentialAPIVersion is the client-go exec plugin API cnap speaks.
+const execCredentialAPIVersion = "client.authentication.k8s.io/v1"
+
+// credentialTTL is the expiry given to kubectl for fetched credentials.
+// The admin certificate itself doesn't expire with it: it only makes
+// kubectl (and the cache) fetch again, so a logout or revoked CNAP token
+// stops new kubectl processes within this time.
+const credentialTTL = 10 * time.Minute
+
+// NewCmdKube is the hidden `cnap kube` command, the client-go exec plugin
+// behind `clusters kubeconfig --exec-credential`. It's run by kubectl, not
+// by users.
+func NewCmdKube() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "kube",
+		Short:  "Kubernetes client integration",
+		Hidden: true,
+	}
+	cmd.AddCommand(newCmdCredential())
+	return cmd
+}
+
+func newCmdCredential() *cobra.Command {
+	return &cobra.Command{
+		Use:   "credential <cluster-id>",
+		Short: "Print an ExecCredential for a cluster (client-go exec plugin)",
+		Long: `Prints a client.authentication.k8s.io/v1 ExecCredential for the cluster.
+It holds the cluster's admin client certificate (or token) from its
+kubeconfig; the expiry only tells kubectl when to run the plugin again.
+
+The credential is cached in ~/.cnap/kube/ (mode 0600, separately per API
+URL and login) until shortly before it expires, so repeated kubectl calls
+don't each hit the API. auth logout deletes the cache. Errors are
+written to stderr as JSON ({"error": "..."}), which kubectl shows as is.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cred, err := clusterCredential(cmd.Context(), args[0], time.Now())
+			if err != nil {
+				_ = json.NewEncoder(os.Stderr).Encode(map[string]string{"error": err.Error()})
+				return &cmdutil.ExitError{Code: 1}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(cred)
+		},
+	}
+}
+
+// clusterCredential returns the cached credential for the cluster if it's
+// still valid for credentialMinValidity, else fetches and caches a new one.
+func clusterCredential(ctx context.Context, clusterID string, now time.Time) (*execCredential, error) {
+	cfg, err := cmdutil.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	p, err := credentialPath(cfg, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if cred := loadCredential(p, now); cred != nil {
+		return cred, nil
+	}
+
+	client, _, err := cmdutil.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	body, err := fetchKubeconfig(ctx, client, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	cred, err := newExecCredential(body, now)
+	if err != nil {
+		return nil, err
+	}
+	saveCredential(p, cred)
+	return cred, nil
+}
+
+// credentialMinValidity is how long a cached credential must still be valid
+// to be reused, so kubectl never gets one about to expire.
+const credentialMinValidity = time.Minute
+
+// credentialPath returns the cache file of the cluster's credential. It
+// holds the admin private key, so it's kept per API URL and token: a
+// credential is never reused by another account or CNAP instance, and
+// logging in again starts afresh.
+func credentialPath(cfg *config.Config, clusterID string) (string, error) {
+	dir, err := config.KubeCredentialDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, cfg.CacheScope(), filepath.Base(clusterID)+".json"), nil
+}
+
+// loadCredential returns the credential cached at p, or nil if there's
+// none valid. Like the name cache, failures count as a miss.
+func loadCredential(p string, now time.Time) *execCredential {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil
+	}
+	var cred execCredential
+	if err := json.Unmarshal(data, &cred); err != nil {
+		slog.Debug("ignoring unreadable cached credential", "path", p, "error", err)
+		return nil
+	}
+	expiry, err := time.Parse(time.RFC3339, cred.Status.ExpirationTimestamp)
+	if err != nil || expiry.Sub(now) < credentialMinValidity {
+		return nil
+	}
+	return &cred
+}
+
+// saveCredential caches cred at p, best-effort. The directories are 0700
+// and the file 0600, as it holds key material.
+func saveCredential(p string, cred *execCredential) {
+	data, err := json.Marshal(cred)
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(p), 0700); err == nil {
+			err = os.WriteFile(p, data, 0600)
+		}
+	}
+	if err != nil {
+		slog.Debug("caching credential failed", "path", p, "error", err)
+	}
+}
+
+// execCredential is a client.authentication.k8s.io/v1 ExecCredential.
+type execCredential struct {
+	Kind       string               `json:"kind"`
+	APIVersion string               `json:"apiVersion"`
+	Spec       struct{}             `json:"spec"`
+	Status     execCredentialStatus `json:"status"`
+}
+
+type execCredentialStatus struct {
+	ExpirationTimestamp   string `json:"expirationTimestamp"`
+	Token                 string `json:"token,omitempty"`
+	ClientCertificateData string `json:"clientCertificateData,omitempty"`
+	ClientKeyData         string `json:"clientKeyData,omitempty"`
+}
+
+// kubeconfigUsers is the part of a kubeconfig newExecCredential reads.
+type kubeconfigUsers struct {
+	CurrentContext string `yaml:"current-context"`
+	Contexts       []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			User string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string `yaml:"token"`
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKeyData         string `yaml:"client-key-data"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// newExecCredential turns the credentials of the admin kubeconfig's current
+// context into an ExecCredential that kubectl refreshes credentialTTL after
+// now. The credentials themselves are the long-lived admin ones.
+func newExecCredential(kubeconfig []byte, now time.Time) (*execCredential, error) {
+	var kc kubeconfigUsers
+	if err := yaml.Unmarshal(kubeconfig, &kc); err != nil {
+		return nil, fmt.Errorf("parsing kubeconfig: %w", err)
+	}
+	userName := ""
+	for _, c := range kc.Contexts {
+		if c.Name == kc.CurrentContext || kc.CurrentContext == "" {
+			userName = c.Context.User
+			break
+		}
+	}
+
+	cred := &execCredential{Kind: "ExecCredential", APIVersion: execCredentialAPIVersion}
+	cred.Status.ExpirationTimestamp = now.Add(credentialTTL).UTC().Format(time.RFC3339)
+	for _, u := range kc.Users {
+		if u.Name != userName && userName != "" {
+			continue
+		}
+		cred.Status.Token = u.User.Token
+		// ExecCredential takes PEM, the kubeconfig base64-encoded PEM.
+		if u.User.ClientCertificateData != "" {
+			cert, err := base64.StdEncoding.DecodeString(u.User.ClientCertificateData)
+			if err != nil {
+				return nil, fmt.Errorf("parsing kubeconfig: client-certificate-data: %w", err)
+			}
+			key, err := base64.StdEncoding.DecodeString(u.User.ClientKeyData)
+			if err != nil {
+				return nil, fmt.Errorf("parsing kubeconfig: client-key-data: %w", err)
+			}
+			cred.Status.ClientCertificateData, cred.Status.ClientKeyData = string(cert), string(key)
+		}
+		break
+	}
+	if cred.Status.Token == "" && cred.Status.ClientCertificateData == "" {
+		return nil, fmt.Errorf("kubeconfig has no token or client certificate for context %q", kc.CurrentContext)
+	}
+	return cred, nil
+}
+
+// execKubeconfig rewrites an admin kubeconfig so that each user runs
+// `cnap kube credential <cluster-id>` instead of embedding credentials.
+// A global --api-url is passed along so the plugin talks to the same API.
+func execKubeconfig(kubeconfig []byte, clusterID string) ([]byte, error) {
+	var kc map[string]any
+	if err := yaml.Unmarshal(kubeconfig, &kc); err != nil {
+		return nil, fmt.Errorf("parsing kubeconfig: %w", err)
+	}
+	users, _ := kc["users"].([]any)
+	if len(users) == 0 {
+		return nil, fmt.Errorf("parsing kubeconfig: no users")
+	}
+
+	args := []string{"kube", "credential", clusterID}
+	if cmdutil.APIURL != "" {
+		args = append(args, "--api-url", cmdutil.APIURL)
+	}
+	for _, u := range users {
+		if u, ok := u.(map[string]any); ok {
+			u["user"] = map[string]any{
+				"exec": map[string]any{
+					"apiVersion":      execCredentialAPIVersion,
+					"command":         "cnap",
+					"args":            args,
+					"interactiveMode": "Never",
+					"installHint":     "cnap is required: see https://github.com/cnap-tech/cli#install",
+				},
+			}
+		}
+	}
+	return yaml.Marshal(kc)
+}
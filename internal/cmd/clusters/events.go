@@ -0,0 +1,118 @@
+package clusters
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+func newCmdEvents() *cobra.Command {
+	var watch bool
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "events [cluster-id]",
+		Short: "Show provisioning events for a KaaS cluster",
+		Long: `Shows events from a KaaS-managed cluster's provisioning workflows and
+control plane, oldest first, below its current status.
+
+Use this when a cluster is stuck provisioning and its status message
+doesn't say why. Clusters not managed by KaaS have no events.
+With --watch, keeps polling and prints new events as they arrive.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !prompt.IsInteractive() {
+				return fmt.Errorf("<cluster-id> argument required when not running interactively")
+			}
+
+			client, cfg, err := cmdutil.NewClient()
+			if err != nil {
+				return err
+			}
+
+			clusterID := ""
+			if len(args) > 0 {
+				clusterID = args[0]
+			} else {
+				clusterID, err = pickCluster(cmd.Context(), client, cfg)
+				if err != nil {
+					return err
+				}
+			}
+
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+
+			getResp, err := client.GetV1ClustersIdWithResponse(cmd.Context(), clusterID)
+			if err != nil {
+				return fmt.Errorf("fetching cluster: %w", err)
+			}
+			if getResp.JSON200 == nil {
+				return cmdutil.APIError(getResp.Status(), getResp.Body, getResp.JSON401, getResp.JSON404)
+			}
+			kaas := getResp.JSON200.Kaas
+			if kaas == nil {
+				return fmt.Errorf("cluster %s is not KaaS-managed; events are only available for KaaS clusters", clusterID)
+			}
+
+			events, err := fetchEvents(cmd.Context(), client, clusterID, &api.GetV1ClustersIdEventsParams{Limit: &limit})
+			if err != nil {
+				return err
+			}
+
+			if !format.IsJSON() {
+				status := string(kaas.Status)
+				if output.ColorEnabled() {
+					status = output.StatusStyle(status).Render(status)
+				}
+				if kaas.StatusMessage != nil && *kaas.StatusMessage != "" {
+					status += " (" + *kaas.StatusMessage + ")"
+				}
+				fmt.Printf("Status: %s\n\n", status)
+			}
+
+			if !watch {
+				if format.IsJSON() {
+					return output.PrintData(format, events)
+				}
+				if len(events) == 0 {
+					fmt.Println("No events found for this cluster.")
+					return nil
+				}
+				cmdutil.PrintEvents(events)
+				return nil
+			}
+
+			return cmdutil.WatchEvents(cmd.Context(), events, format, func(ctx context.Context, since float64) ([]api.Event, error) {
+				params := &api.GetV1ClustersIdEventsParams{}
+				if since > 0 {
+					params.Since = &since
+				}
+				return fetchEvents(ctx, client, clusterID, params)
+			})
+		},
+	}
+
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for new events")
+	cmd.Flags().IntVar(&limit, "limit", 100, "Maximum number of recent events to show (1-500)")
+
+	return cmd
+}
+
+func fetchEvents(ctx context.Context, client *api.ClientWithResponses, clusterID string, params *api.GetV1ClustersIdEventsParams) ([]api.Event, error) {
+	resp, err := client.GetV1ClustersIdEventsWithResponse(ctx, clusterID, params)
+	if err != nil {
+		return nil, fmt.Errorf("fetching events: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404, resp.JSON422)
+	}
+	return resp.JSON200.Data, nil
+}
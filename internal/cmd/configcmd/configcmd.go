@@ -0,0 +1,87 @@
+package configcmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/cnap-tech/cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func NewCmdConfig() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the CLI configuration file",
+	}
+
+	cmd.AddCommand(newCmdEdit())
+
+	return cmd
+}
+
+func newCmdEdit() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Open the config file in your editor",
+		Long: `Opens ~/.cnap/config.yaml in $VISUAL or $EDITOR (vi, or notepad on
+Windows, if neither is set), creating it with defaults first if needed.
+
+When the editor exits the file is loaded again. If it no longer parses or
+holds an invalid setting, the previous version is restored, so a typo
+can't break every other command.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.Path()
+			if err != nil {
+				return err
+			}
+			if !config.Exists() {
+				if err := config.DefaultConfig().Save(); err != nil {
+					return fmt.Errorf("creating config: %w", err)
+				}
+			}
+			backup, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("reading config: %w", err)
+			}
+
+			editor := editorCommand()
+			c := exec.CommandContext(cmd.Context(), editor[0], append(editor[1:], path)...)
+			c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+			if err := c.Run(); err != nil {
+				return fmt.Errorf("running editor %q: %w", strings.Join(editor, " "), err)
+			}
+
+			cfg, err := config.Load()
+			if err == nil {
+				err = cfg.CheckFile()
+			}
+			if err != nil {
+				if restoreErr := config.WriteFileAtomic(path, backup, 0o600); restoreErr != nil {
+					return fmt.Errorf("%w (restoring the previous config also failed: %v)", err, restoreErr)
+				}
+				return fmt.Errorf("%w; the previous config was restored", err)
+			}
+
+			fmt.Printf("Saved %s\n", path)
+			return nil
+		},
+	}
+}
+
+// editorCommand returns the user's editor command and its arguments, from
+// $VISUAL or $EDITOR (e.g. "code --wait"), else the platform default.
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
@@ -0,0 +1,65 @@
+package configcmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cnap-tech/cli/internal/cmdtest"
+)
+
+func TestEdit(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"valid", "api_url: https://api.example.com\nactive_workspace: ws_2\n", ""},
+		{"unparseable", "api_url: [unclosed\n", "parsing config"},
+		{"invalid setting", "api_url: not-a-url\n", `:1: api_url "not-a-url"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("CNAP_API_URL", "")
+			t.Setenv("CNAP_AUTH_URL", "")
+
+			// The "editor" overwrites the file it's given.
+			edited := filepath.Join(t.TempDir(), "edited.yaml")
+			if err := os.WriteFile(edited, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			script := filepath.Join(t.TempDir(), "editor.sh")
+			if err := os.WriteFile(script, []byte("cp \""+edited+"\" \"$1\"\n"), 0o700); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("VISUAL", "")
+			t.Setenv("EDITOR", "sh "+script)
+
+			err := cmdtest.Run(newCmdEdit())
+
+			data, readErr := os.ReadFile(filepath.Join(home, ".cnap", "config.yaml"))
+			if readErr != nil {
+				t.Fatal(readErr)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("edit: %v", err)
+				}
+				if !strings.Contains(string(data), "active_workspace: ws_2") {
+					t.Errorf("config = %q, want the edited content", data)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "restored") {
+				t.Errorf("got error %v, want %q and restored", err, tt.wantErr)
+			}
+			if !strings.Contains(string(data), "api_url: https://api.cnap.tech") {
+				t.Errorf("config not restored to defaults: %q", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package get
+
+import (
+	"fmt"
+	"strings"
+
+	clusterscmd "github.com/cnap-tech/cli/internal/cmd/clusters"
+	installscmd "github.com/cnap-tech/cli/internal/cmd/installs"
+	productscmd "github.com/cnap-tech/cli/internal/cmd/products"
+	templatescmd "github.com/cnap-tech/cli/internal/cmd/templates"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// resources are the resource commands `cnap get` can dispatch to. Each
+// needs "get" and "list" subcommands; its name and aliases are the
+// accepted resource types.
+var resources = []func() *cobra.Command{
+	installscmd.NewCmdInstalls,
+	clusterscmd.NewCmdClusters,
+	productscmd.NewCmdProducts,
+	templatescmd.NewCmdTemplates,
+}
+
+func NewCmdGet() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <resource> [id]",
+		Short: "Show a resource (kubectl-style shortcut)",
+		Long: fmt.Sprintf(`Shows one resource by ID, or lists resources of a type when no ID is given.
+
+  cnap get install <id>     same as: cnap installs get <id>
+  cnap get clusters         same as: cnap clusters list
+
+Flags are passed on: cnap get clusters --limit 5 is cnap clusters list --limit 5.
+
+Resource types: %s`, strings.Join(resourceTypes(), ", ")),
+		Args: cobra.MinimumNArgs(1),
+		// Resource types are subcommands; anything else lands here.
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fmt.Errorf("unknown resource type %q (supported: %s)", args[0], strings.Join(resourceTypes(), ", "))
+		},
+	}
+	for _, newCmd := range resources {
+		cmd.AddCommand(newCmdResource(newCmd()))
+	}
+	return cmd
+}
+
+// newCmdResource returns `cnap get <resource>` for res. It takes the flags
+// of both res's "list" and "get" subcommands and runs "get" when an ID is
+// given, "list" otherwise.
+func newCmdResource(res *cobra.Command) *cobra.Command {
+	list, get := subcommand(res, "list"), subcommand(res, "get")
+
+	cmd := &cobra.Command{
+		Use:               res.Name() + " [id]",
+		Aliases:           res.Aliases,
+		Short:             fmt.Sprintf("Same as: cnap %s get, or cnap %s list without an ID", res.Name(), res.Name()),
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: get.ValidArgsFunction,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sub := list
+			if len(args) == 1 {
+				sub = get
+			}
+
+			var err error
+			cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+				if err != nil || !f.Changed || f.Name == "help" {
+					return
+				}
+				target := sub.Flags().Lookup(f.Name)
+				if target == nil {
+					err = fmt.Errorf("--%s is not supported by cnap %s %s", f.Name, res.Name(), sub.Name())
+					return
+				}
+				if target != f {
+					err = copyFlag(target, f)
+				}
+			})
+			if err != nil {
+				return err
+			}
+
+			sub.SetContext(cmd.Context())
+			sub.SetOut(cmd.OutOrStdout())
+			sub.SetErr(cmd.ErrOrStderr())
+			return sub.RunE(sub, args)
+		},
+	}
+	// The flags stay bound to the subcommands' variables, so parsing them
+	// here sets those directly. A flag both subcommands define is added
+	// once and copied to the other when that one runs.
+	cmd.Flags().AddFlagSet(list.Flags())
+	cmd.Flags().AddFlagSet(get.Flags())
+	return cmd
+}
+
+// subcommand returns res's subcommand called name; every entry in
+// resources has both "get" and "list".
+func subcommand(res *cobra.Command, name string) *cobra.Command {
+	sub, _, err := res.Find([]string{name})
+	if err != nil || sub.Name() != name {
+		panic(fmt.Sprintf("get: %s has no %s subcommand", res.Name(), name))
+	}
+	return sub
+}
+
+// copyFlag sets dst to the value parsed into src.
+func copyFlag(dst, src *pflag.Flag) error {
+	var err error
+	s, srcSlice := src.Value.(pflag.SliceValue)
+	d, dstSlice := dst.Value.(pflag.SliceValue)
+	if srcSlice && dstSlice {
+		err = d.Replace(s.GetSlice())
+	} else {
+		err = dst.Value.Set(src.Value.String())
+	}
+	if err != nil {
+		return err
+	}
+	dst.Changed = true
+	return nil
+}
+
+func resourceTypes() []string {
+	var types []string
+	for _, newCmd := range resources {
+		res := newCmd()
+		types = append(types, res.Name())
+		types = append(types, res.Aliases...)
+	}
+	return types
+}
@@ -0,0 +1,92 @@
+package get
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cnap-tech/cli/internal/cmdtest"
+	"github.com/cnap-tech/cli/internal/config"
+)
+
+func TestGetDispatch(t *testing.T) {
+	var paths []string
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		cmdtest.WriteError(w, http.StatusNotFound, "Not found")
+	}))
+
+	tests := []struct {
+		args []string
+		path string
+	}{
+		{[]string{"install", "inst_1"}, "/v1/installs/inst_1"},
+		{[]string{"ins", "inst_1"}, "/v1/installs/inst_1"},
+		{[]string{"clusters", "cls_1"}, "/v1/clusters/cls_1"},
+		{[]string{"cl", "cls_1"}, "/v1/clusters/cls_1"},
+		{[]string{"product", "prod_1"}, "/v1/products/prod_1"},
+		{[]string{"tpl", "tpl_1"}, "/v1/templates/tpl_1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			paths = nil
+			err := cmdtest.Run(NewCmdGet(), tt.args...)
+			if err == nil || !strings.Contains(err.Error(), "Not found") {
+				t.Errorf("got error %v, want the API's not found error", err)
+			}
+			if len(paths) != 1 || paths[0] != tt.path {
+				t.Errorf("requests = %v, want [%s]", paths, tt.path)
+			}
+		})
+	}
+}
+
+func TestGetUnknownResource(t *testing.T) {
+	err := cmdtest.Run(NewCmdGet(), "widgets", "w_1")
+	if err == nil || !strings.Contains(err.Error(), `unknown resource type "widgets"`) {
+		t.Errorf("got error %v, want unknown resource type", err)
+	}
+}
+
+func TestGetPassesFlags(t *testing.T) {
+	var queries []string
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		cmdtest.WriteError(w, http.StatusNotFound, "Not found")
+	}))
+	cfg := config.DefaultConfig()
+	cfg.ActiveWorkspace = "ws_1"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args  []string
+		query string
+	}{
+		{[]string{"clusters", "--limit", "5"}, "/v1/clusters?limit=5"},
+		{[]string{"ins", "--limit=7", "--product", "prod_1", "--no-status"}, "/v1/installs?limit=7&product_id=prod_1"},
+		{[]string{"install", "inst_1", "--no-status"}, "/v1/installs/inst_1?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			queries = nil
+			err := cmdtest.Run(NewCmdGet(), tt.args...)
+			if err == nil || !strings.Contains(err.Error(), "Not found") {
+				t.Errorf("got error %v, want the API's not found error", err)
+			}
+			if len(queries) != 1 || queries[0] != tt.query {
+				t.Errorf("requests = %v, want [%s]", queries, tt.query)
+			}
+		})
+	}
+}
+
+func TestGetRejectsListFlagWithID(t *testing.T) {
+	err := cmdtest.Run(NewCmdGet(), "clusters", "cls_1", "--limit", "5")
+	if err == nil || !strings.Contains(err.Error(), "--limit is not supported by cnap clusters get") {
+		t.Errorf("got error %v, want unsupported flag", err)
+	}
+}
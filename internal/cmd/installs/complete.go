@@ -0,0 +1,56 @@
+package installs
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+// completeTimeout bounds the API call made while completing, so a slow or
+// unreachable API doesn't hang the shell.
+const completeTimeout = 5 * time.Second
+
+// completeInstallIDs completes an [install-id] argument with the installs
+// of the workspace. Completion doesn't run PersistentPreRunE, but cobra has
+// already parsed the flags on the line, so cmdutil.NewClient picks up
+// --workspace and --api-url just as the command itself would.
+func completeInstallIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	client, cfg, err := cmdutil.NewClient()
+	if err != nil || cmdutil.RequireWorkspace(cfg) != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, completeTimeout)
+	defer cancel()
+
+	limit := 100
+	resp, err := client.GetV1InstallsWithResponse(ctx, &api.GetV1InstallsParams{Limit: &limit})
+	if err != nil || resp.JSON200 == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cacheInstallNames(resp.JSON200.Data...)
+
+	var ids []string
+	for _, inst := range resp.JSON200.Data {
+		if !strings.HasPrefix(inst.Id, toComplete) {
+			continue
+		}
+		if inst.Name != nil {
+			ids = append(ids, inst.Id+"\t"+*inst.Name)
+		} else {
+			ids = append(ids, inst.Id)
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,528 @@
+package installs
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/config"
+	"github.com/cnap-tech/cli/internal/prompt"
+	"github.com/cnap-tech/cli/internal/wsterm"
+	"github.com/spf13/cobra"
+)
+
+func newCmdCp() *cobra.Command {
+	var container string
+
+	cmd := &cobra.Command{
+		Use:   "cp <src> <dest>",
+		Short: "Copy files to or from a pod container",
+		Long: `Copies files and directories between the local machine and a pod container.
+One side is local, the other is <install-id>:<pod>/<path>; leave out the
+pod (<install-id>:/<path>) to pick it interactively.
+
+  cnap installs cp inst_123:web-0/etc/nginx/nginx.conf ./nginx.conf
+  cnap installs cp ./config inst_123:web-0/app/config
+
+Directories are copied recursively and file modes are preserved. As with
+cp, a destination that is an existing directory gets the source inside it
+(named as the source), on either side. Like kubectl cp, this streams a tar archive over an exec session, so the
+container needs tar and base64 (busybox and most distro images have both).`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			src, srcErr := parseCpRemote(args[0])
+			dest, destErr := parseCpRemote(args[1])
+			var remote *cpRemote
+			var local string
+			switch {
+			case srcErr == nil && destErr == nil:
+				return fmt.Errorf("copying between two pods is not supported; one side must be a local path")
+			case srcErr == nil:
+				remote, local = src, args[1]
+			case destErr == nil:
+				remote, local = dest, args[0]
+			default:
+				return fmt.Errorf("one side must be <install-id>:<pod>/<path>")
+			}
+
+			client, cfg, err := cmdutil.NewClient()
+			if err != nil {
+				return err
+			}
+
+			if remote.pod == "" && !prompt.IsInteractive() {
+				return fmt.Errorf("pod name required when not running interactively (<install-id>:<pod>/<path>)")
+			}
+			remote.pod, container, err = selectPodContainer(cmd.Context(), client, remote.installID, remote.pod, container)
+			if err != nil {
+				return err
+			}
+			if remote.pod == "" || container == "" {
+				return fmt.Errorf("--container is required")
+			}
+
+			if remote == src {
+				return copyFromPod(cmd.Context(), cfg, remote, container, local)
+			}
+			return copyToPod(cmd.Context(), cfg, local, remote, container)
+		},
+	}
+
+	cmd.Flags().StringVar(&container, "container", "", "Container name")
+
+	return cmd
+}
+
+// cpRemote is the pod side of a copy: <install-id>:<pod>/<path>.
+type cpRemote struct {
+	installID string
+	pod       string // empty to pick interactively
+	path      string // absolute path in the container
+}
+
+// parseCpRemote parses a remote cp argument. Local paths (no "id:" prefix,
+// or a Windows drive letter) return an error.
+func parseCpRemote(arg string) (*cpRemote, error) {
+	id, rest, ok := strings.Cut(arg, ":")
+	if !ok || id == "" || len(id) == 1 || strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("%q is a local path", arg)
+	}
+
+	pod, p := "", rest
+	if !strings.HasPrefix(rest, "/") {
+		var found bool
+		if pod, p, found = strings.Cut(rest, "/"); !found || p == "" {
+			return nil, fmt.Errorf("%q: missing path after pod name", arg)
+		}
+	}
+	p = path.Clean("/" + p)
+	if p == "/" {
+		return nil, fmt.Errorf("%q: refusing to copy the container's root directory", arg)
+	}
+	return &cpRemote{installID: id, pod: pod, path: p}, nil
+}
+
+// Remote scripts print their payload between these markers, followed by
+// an exit status. The shell echoes the script line before `stty -echo`
+// takes effect, so the markers are printed in two halves that never
+// appear joined in the echo.
+const (
+	cpBeginMarker = "__CNAP_CP_BEGIN__"
+	cpEndMarker   = "__CNAP_CP_END__"
+	cpPrelude     = `stty -echo 2>/dev/null; PS1=''; printf '%s%s\n' __CNAP_CP _BEGIN__; `
+	cpEpilogue    = `printf '\n%s%s %s\n' __CNAP_CP _END__ "$s"; exit` + "\n"
+	cpToolsCheck  = `! command -v tar >/dev/null 2>&1 || ! command -v base64 >/dev/null 2>&1`
+)
+
+// Statuses reported by the remote scripts besides tar's own.
+const (
+	cpStatusNoTools  = 127
+	cpStatusNotFound = 2
+)
+
+// downloadScript archives p and prints it base64-encoded. The
+// { { ...; echo $? >&3; } | base64 >&4; } 3>&1 dance captures tar's exit
+// status, since POSIX sh has no pipefail.
+func downloadScript(p string) string {
+	dir, base := path.Split(p)
+	return cpPrelude +
+		fmt.Sprintf(`if %s; then s=%d; elif [ ! -e %s ]; then s=%d; else { s=$( { { tar cf - -C %s %s 2>/dev/null; echo $? >&3; } | base64 >&4; } 3>&1 ); } 4>&1; fi; `,
+			cpToolsCheck, cpStatusNoTools, shellQuote(p), cpStatusNotFound, shellQuote(dir), shellQuote(base)) +
+		cpEpilogue
+}
+
+// uploadScript reads a base64 tar archive of name from stdin (ended by
+// EOF) and unpacks it to dest. Like cp, an existing directory dest gets
+// name inside it; otherwise name is unpacked in a staging directory next
+// to dest and moved to dest. On failure stdin is still drained so the
+// archive isn't run as shell input.
+func uploadScript(dest, name string) string {
+	d, dir, n := shellQuote(dest), shellQuote(path.Dir(dest)), shellQuote(name)
+	return cpPrelude +
+		fmt.Sprintf(`if %s; then s=%d; cat >/dev/null; `, cpToolsCheck, cpStatusNoTools) +
+		fmt.Sprintf(`elif [ -d %s ]; then base64 -d | tar xf - -C %s 2>/dev/null; s=$?; `, d, d) +
+		fmt.Sprintf(`elif ! mkdir -p %s 2>/dev/null || ! t=$(mktemp -d %s/.cnap-cp.XXXXXX 2>/dev/null); then s=1; cat >/dev/null; `, dir, dir) +
+		fmt.Sprintf(`else base64 -d | tar xf - -C "$t" 2>/dev/null && mv "$t"/%s %s 2>/dev/null; s=$?; rm -rf "$t"; fi; `, n, d) +
+		cpEpilogue
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// runCpScript runs script in a shell in the container, followed by stdin
+// (if any), passing what the script prints between the markers on to
+// payload as it arrives.
+func runCpScript(ctx context.Context, cfg *config.Config, remote *cpRemote, container, script string, stdin io.Reader, payload io.Writer) error {
+	u, err := execURL(cfg, remote.installID, remote.pod, container, "/bin/sh")
+	if err != nil {
+		return err
+	}
+	conn, err := dialExec(ctx, cfg, u)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.CloseNow() }()
+
+	input := make(chan []byte, 1)
+	go wsterm.ReadInput(io.MultiReader(strings.NewReader(script), stdin), input)
+
+	out := &cpOutput{payload: payload}
+	var errOut bytes.Buffer
+	if _, err := wsterm.NewSession(conn, out, &errOut, -1).Run(ctx, input); err != nil {
+		return err
+	}
+	if errOut.Len() > 0 {
+		return fmt.Errorf("%s", strings.TrimSpace(errOut.String()))
+	}
+	return out.result()
+}
+
+// maxCpLine bounds how much of a line cpOutput holds back while looking
+// for a marker. base64 wraps its output at 76 columns.
+const maxCpLine = 4096
+
+// cpOutput parses the output of a cp script as it arrives: it skips
+// everything up to the begin marker, passes the payload lines on (without
+// line endings, which base64 decoding ignores anyway) and records the
+// status printed after the end marker.
+type cpOutput struct {
+	payload io.Writer
+	line    []byte // incomplete line
+	begun   bool
+	ended   bool
+	status  string
+}
+
+func (o *cpOutput) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 && !o.ended {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			o.line = append(o.line, p...)
+			if len(o.line) > maxCpLine {
+				o.flushLong()
+			}
+			break
+		}
+		o.line = append(o.line, p[:i]...)
+		p = p[i+1:]
+		o.endLine()
+	}
+	return n, nil
+}
+
+// endLine handles a complete line. The remote side is a TTY, which turns
+// \n into \r\n.
+func (o *cpOutput) endLine() {
+	line := bytes.TrimRight(o.line, "\r")
+	switch {
+	case !o.begun:
+		o.begun = string(line) == cpBeginMarker
+	case bytes.HasPrefix(line, []byte(cpEndMarker+" ")):
+		o.ended, o.status = true, string(line[len(cpEndMarker)+1:])
+	default:
+		o.write(line)
+	}
+	o.line = o.line[:0]
+}
+
+// flushLong handles a line too long to be a marker.
+func (o *cpOutput) flushLong() {
+	if o.begun {
+		o.write(o.line)
+	}
+	o.line = o.line[:0]
+}
+
+// write passes payload on. Once the reader gives up (e.g. extraction
+// failed) the rest is dropped; the reader reports its own error.
+func (o *cpOutput) write(b []byte) {
+	if o.payload != nil {
+		if _, err := o.payload.Write(b); err != nil {
+			o.payload = nil
+		}
+	}
+}
+
+// result turns the status the script reported into an error.
+func (o *cpOutput) result() error {
+	switch {
+	case !o.begun:
+		return fmt.Errorf("unexpected output from container (is /bin/sh available?)")
+	case !o.ended:
+		return fmt.Errorf("copy ended early: no completion marker from container")
+	}
+	status, err := strconv.Atoi(o.status)
+	if err != nil {
+		return fmt.Errorf("unexpected status %q from container", o.status)
+	}
+
+	switch status {
+	case 0:
+		return nil
+	case cpStatusNoTools:
+		return fmt.Errorf("tar and base64 are required in the container for cp")
+	case cpStatusNotFound:
+		return fmt.Errorf("no such file or directory in container")
+	default:
+		return fmt.Errorf("tar failed in container (exit status %d)", status)
+	}
+}
+
+// copyFromPod streams the archive from the container through a base64
+// decoder straight into extractTar, so nothing is held in memory.
+func copyFromPod(ctx context.Context, cfg *config.Config, remote *cpRemote, container, local string) error {
+	// Like cp: copying into an existing directory keeps the source name.
+	dest := local
+	if info, err := os.Stat(local); err == nil && info.IsDir() {
+		dest = filepath.Join(local, path.Base(remote.path))
+	}
+
+	pr, pw := io.Pipe()
+	type extracted struct {
+		files int
+		err   error
+	}
+	done := make(chan extracted, 1)
+	go func() {
+		n, err := extractTar(base64.NewDecoder(base64.StdEncoding, pr), path.Base(remote.path), dest)
+		if err == nil {
+			// Drain the tar padding after the end-of-archive marker.
+			_, err = io.Copy(io.Discard, pr)
+		}
+		_ = pr.CloseWithError(err)
+		done <- extracted{n, err}
+	}()
+
+	err := runCpScript(ctx, cfg, remote, container, downloadScript(remote.path), strings.NewReader(""), pw)
+	_ = pw.Close()
+	x := <-done
+	if err != nil {
+		return err
+	}
+	if x.err != nil {
+		return fmt.Errorf("extracting archive from container: %w", x.err)
+	}
+	fmt.Printf("Copied %d file(s) to %s.\n", x.files, dest)
+	return nil
+}
+
+// copyToPod streams a tar archive of local to the container, base64
+// encoded and wrapped like base64(1) output: the remote TTY reads input a
+// line at a time and caps line length. EOF (Ctrl-D) at the start of a line
+// ends it.
+func copyToPod(ctx context.Context, cfg *config.Config, local string, remote *cpRemote, container string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	abs, err := filepath.Abs(local)
+	if err != nil {
+		return err
+	}
+	name := filepath.Base(abs)
+
+	pr, pw := io.Pipe()
+	var files int
+	var archiveErr error
+	archived := make(chan struct{})
+	go func() {
+		defer close(archived)
+		lines := &lineWrapper{w: pw, width: 76}
+		enc := base64.NewEncoder(base64.StdEncoding, lines)
+		files, archiveErr = writeTar(enc, local, name)
+		if archiveErr == nil {
+			if archiveErr = enc.Close(); archiveErr == nil {
+				archiveErr = lines.Close()
+			}
+		}
+		if archiveErr != nil {
+			// The container is waiting for the rest of the archive.
+			cancel()
+		}
+		_ = pw.CloseWithError(archiveErr)
+	}()
+
+	stdin := io.MultiReader(pr, strings.NewReader("\x04"))
+	err = runCpScript(ctx, cfg, remote, container, uploadScript(remote.path, name), stdin, nil)
+	_ = pr.Close()
+	<-archived
+	if archiveErr != nil && !errors.Is(archiveErr, io.ErrClosedPipe) {
+		return archiveErr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Copied %d file(s) to %s:%s.\n", files, remote.pod, remote.path)
+	return nil
+}
+
+// lineWrapper breaks what is written to it into lines of width bytes.
+// Close ends the last line.
+type lineWrapper struct {
+	w     io.Writer
+	width int
+	col   int
+}
+
+func (l *lineWrapper) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		chunk := p[:min(len(p), l.width-l.col)]
+		if _, err := l.w.Write(chunk); err != nil {
+			return n - len(p), err
+		}
+		p = p[len(chunk):]
+		if l.col += len(chunk); l.col == l.width {
+			if _, err := l.w.Write([]byte("\n")); err != nil {
+				return n - len(p), err
+			}
+			l.col = 0
+		}
+	}
+	return n, nil
+}
+
+func (l *lineWrapper) Close() error {
+	if l.col == 0 {
+		return nil
+	}
+	l.col = 0
+	_, err := l.w.Write([]byte("\n"))
+	return err
+}
+
+// writeTar archives the file or directory at src with its root entry
+// named name, preserving modes. It returns the number of regular files.
+func writeTar(w io.Writer, src, name string) (int, error) {
+	tw := tar.NewWriter(w)
+	files := 0
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			_, _ = fmt.Fprintf(os.Stderr, "Skipping %s: not a regular file or directory\n", p)
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(name, filepath.ToSlash(rel))
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		if _, err := io.Copy(tw, f); err != nil {
+			return err
+		}
+		files++
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("archiving %s: %w", src, err)
+	}
+	return files, tw.Close()
+}
+
+// extractTar unpacks an archive whose entries are rooted at name into
+// dest, preserving modes. Entries outside name (e.g. "../x") are rejected,
+// as are absolute paths (Clean keeps them from matching name), and
+// symlinks and other special files are skipped. It returns the number
+// of regular files written.
+func extractTar(r io.Reader, name, dest string) (int, error) {
+	tr := tar.NewReader(r)
+	files := 0
+	// Directory modes are applied last, deepest first, so a read-only
+	// directory doesn't stop its own entries from being written.
+	type dirMode struct {
+		path string
+		mode fs.FileMode
+	}
+	var dirs []dirMode
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			for i := len(dirs) - 1; i >= 0; i-- {
+				if err := os.Chmod(dirs[i].path, dirs[i].mode); err != nil {
+					return files, err
+				}
+			}
+			return files, nil
+		}
+		if err != nil {
+			return files, fmt.Errorf("reading archive: %w", err)
+		}
+
+		entry := path.Clean(hdr.Name)
+		rel, ok := strings.CutPrefix(entry, name)
+		if !ok || (rel != "" && !strings.HasPrefix(rel, "/")) {
+			return files, fmt.Errorf("unexpected path %q in archive", hdr.Name)
+		}
+		target := filepath.Join(dest, filepath.FromSlash(rel))
+		mode := hdr.FileInfo().Mode().Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return files, err
+			}
+			dirs = append(dirs, dirMode{target, mode})
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return files, err
+			}
+			f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+			if err != nil {
+				return files, err
+			}
+			_, err = io.Copy(f, tr)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return files, fmt.Errorf("writing %s: %w", target, err)
+			}
+			// OpenFile's mode is masked by the umask and ignored for
+			// existing files.
+			if err := os.Chmod(target, mode); err != nil {
+				return files, err
+			}
+			files++
+		default:
+			_, _ = fmt.Fprintf(os.Stderr, "Skipping %s: not a regular file or directory\n", hdr.Name)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package installs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+// describeEventLimit caps how many recent events describe includes.
+const describeEventLimit = 20
+
+// installDescription is the aggregated report printed by describe.
+// Sections that failed to load are nil and their error is recorded in Errors.
+type installDescription struct {
+	Install *api.Install       `json:"install"`
+	Status  *api.InstallStatus `json:"status"`
+	Pods    []api.Pod          `json:"pods"`
+	Events  []api.Event        `json:"events"`
+	Errors  map[string]string  `json:"errors,omitempty"`
+}
+
+func newCmdDescribe() *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe [install-id]",
+		Short: "Show a full diagnostic report for an install",
+		Long: `Combines install details, live status, pods, and recent events into a
+single report. This is the command to run (and paste) when asking for support.
+
+Sections that can't be fetched are reported inline; the rest still print.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !prompt.IsInteractive() {
+				return fmt.Errorf("<install-id> argument required when not running interactively")
+			}
+
+			client, cfg, err := cmdutil.NewClient()
+			if err != nil {
+				return err
+			}
+
+			installID := ""
+			if len(args) > 0 {
+				installID = args[0]
+			} else {
+				installID, err = pickInstall(cmd.Context(), client, cfg)
+				if err != nil {
+					return err
+				}
+			}
+
+			resp, err := client.GetV1InstallsIdWithResponse(cmd.Context(), installID)
+			if err != nil {
+				return fmt.Errorf("fetching install: %w", err)
+			}
+			if resp.JSON200 == nil {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+			}
+
+			d := describeInstall(cmd.Context(), client, resp.JSON200)
+
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+			if format.IsJSON() {
+				return output.PrintData(format, d)
+			}
+
+			printDescription(d)
+			return nil
+		},
+	}
+}
+
+// describeInstall fetches status, pods, and events concurrently.
+// A failed fetch leaves its section empty and records the error.
+func describeInstall(ctx context.Context, client *api.ClientWithResponses, install *api.Install) *installDescription {
+	d := &installDescription{Install: install}
+
+	var mu sync.Mutex
+	fail := func(section string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if d.Errors == nil {
+			d.Errors = make(map[string]string)
+		}
+		d.Errors[section] = err.Error()
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+
+	go func() {
+		defer wg.Done()
+		resp, err := client.GetV1InstallsIdStatusWithResponse(ctx, install.Id)
+		if err != nil {
+			fail("status", err)
+			return
+		}
+		if resp.JSON200 == nil {
+			fail("status", cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404))
+			return
+		}
+		d.Status = resp.JSON200
+	}()
+
+	go func() {
+		defer wg.Done()
+		resp, err := client.GetV1InstallsIdPodsWithResponse(ctx, install.Id)
+		if err != nil {
+			fail("pods", err)
+			return
+		}
+		if resp.JSON200 == nil {
+			fail("pods", cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404))
+			return
+		}
+		d.Pods = resp.JSON200.Data
+	}()
+
+	go func() {
+		defer wg.Done()
+		limit := describeEventLimit
+		events, err := fetchEvents(ctx, client, install.Id, &api.GetV1InstallsIdEventsParams{Limit: &limit})
+		if err != nil {
+			fail("events", err)
+			return
+		}
+		d.Events = events
+	}()
+
+	wg.Wait()
+	return d
+}
+
+func printDescription(d *installDescription) {
+	i := d.Install
+	fmt.Printf("Name:       %s\n", deref(i.Name))
+	fmt.Printf("ID:         %s\n", i.Id)
+	fmt.Printf("Workspace:  %s\n", i.WorkspaceId)
+	fmt.Printf("Product:    %s\n", deref(i.ProductId))
+	fmt.Printf("Template:   %s\n", deref(i.TemplateId))
+	fmt.Printf("Cluster:    %s\n", i.ClusterId)
+	fmt.Printf("Created:    %s\n", formatTime(i.CreatedAt))
+
+	fmt.Println("\nStatus:")
+	switch {
+	case d.Status != nil:
+		health := d.Status.Health
+		if output.ColorEnabled() {
+			health = output.StatusStyle(health).Render(health)
+		}
+		fmt.Printf("  Health:    %s\n", health)
+		fmt.Printf("  Workflow:  %s\n", d.Status.Phase)
+		if d.Status.Message != nil && *d.Status.Message != "" {
+			fmt.Printf("  Message:   %s\n", *d.Status.Message)
+		}
+	default:
+		fmt.Printf("  <unavailable: %s>\n", d.Errors["status"])
+	}
+
+	fmt.Println("\nPods:")
+	switch {
+	case d.Errors["pods"] != "":
+		fmt.Printf("  <unavailable: %s>\n", d.Errors["pods"])
+	case len(d.Pods) == 0:
+		fmt.Println("  <none>")
+	default:
+		rows := make([][]string, len(d.Pods))
+		for n, p := range d.Pods {
+			rows[n] = []string{"  " + p.Name, deref(p.Phase), podReady(p), podRestarts(p), strings.Join(containerLabels(p), ", ")}
+		}
+		output.PrintStyledTable(
+			[]string{"  NAME", "PHASE", "READY", "RESTARTS", "CONTAINERS"},
+			rows,
+			map[string]output.StyleFunc{"PHASE": output.StatusStyle},
+		)
+	}
+
+	fmt.Println("\nEvents:")
+	switch {
+	case d.Errors["events"] != "":
+		fmt.Printf("  <unavailable: %s>\n", d.Errors["events"])
+	case len(d.Events) == 0:
+		fmt.Println("  <none>")
+	default:
+		rows := make([][]string, len(d.Events))
+		for n, e := range d.Events {
+			rows[n] = []string{"  " + cmdutil.FormatEventTime(e.Timestamp), string(e.Type), e.Reason, e.Object, e.Message}
+		}
+		output.PrintStyledTable(
+			[]string{"  TIME", "TYPE", "REASON", "OBJECT", "MESSAGE"},
+			rows,
+			map[string]output.StyleFunc{"TYPE": output.EventTypeStyle},
+		)
+	}
+}
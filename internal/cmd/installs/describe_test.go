@@ -0,0 +1,48 @@
+package installs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdtest"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+)
+
+func TestDescribeDegradesGracefully(t *testing.T) {
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/installs/inst_1/pods":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": []map[string]any{{"name": "web-0", "containers": []string{"web"}}},
+			})
+		case "/v1/installs/inst_1/events":
+			cmdtest.WriteError(w, http.StatusInternalServerError, "cluster unreachable")
+		default:
+			cmdtest.WriteError(w, http.StatusNotFound, "not found")
+		}
+	}))
+
+	client, _, err := cmdutil.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := describeInstall(context.Background(), client, &api.Install{Id: "inst_1"})
+
+	if len(d.Pods) != 1 || d.Pods[0].Name != "web-0" {
+		t.Errorf("pods = %+v, want web-0", d.Pods)
+	}
+	if d.Status != nil || d.Errors["status"] == "" {
+		t.Errorf("status = %+v, errors = %v; want status error", d.Status, d.Errors)
+	}
+	if d.Events != nil || d.Errors["events"] == "" {
+		t.Errorf("events = %+v, errors = %v; want events error", d.Events, d.Errors)
+	}
+	if _, ok := d.Errors["pods"]; ok {
+		t.Errorf("unexpected pods error: %s", d.Errors["pods"])
+	}
+}
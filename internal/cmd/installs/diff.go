@@ -0,0 +1,146 @@
+package installs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/prompt"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func newCmdDiff() *cobra.Command {
+	var sourceID, valuesFile string
+
+	cmd := &cobra.Command{
+		Use:   "diff [install-id]",
+		Short: "Show how a values file differs from the current values",
+		Long: `Compares a helm source's current template values with a values file and
+prints a unified diff of the YAML, as update-values would change it.
+--source can be left out when the template has a single helm source.
+
+Like diff(1), exits with status 1 when there are differences.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !prompt.IsInteractive() {
+				return fmt.Errorf("<install-id> argument required when not running interactively")
+			}
+
+			client, cfg, err := cmdutil.NewClient()
+			if err != nil {
+				return err
+			}
+
+			installID := ""
+			if len(args) > 0 {
+				installID = args[0]
+			} else {
+				installID, err = pickInstall(cmd.Context(), client, cfg)
+				if err != nil {
+					return err
+				}
+			}
+
+			proposed, err := readValuesFile(valuesFile)
+			if err != nil {
+				return err
+			}
+
+			resp, err := client.GetV1InstallsIdWithResponse(cmd.Context(), installID)
+			if err != nil {
+				return fmt.Errorf("fetching install: %w", err)
+			}
+			if resp.JSON200 == nil {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+			}
+			if resp.JSON200.TemplateId == nil {
+				return fmt.Errorf("install %s has no template", installID)
+			}
+			sources, err := fetchHelmSources(cmd.Context(), client, *resp.JSON200.TemplateId)
+			if err != nil {
+				return err
+			}
+
+			source, err := findSource(sources, sourceID)
+			if err != nil {
+				return err
+			}
+			sourceID = source.Id
+
+			var current any = source.Values
+			before, err := valuesYAML(current)
+			if err != nil {
+				return err
+			}
+			after, err := valuesYAML(proposed)
+			if err != nil {
+				return err
+			}
+
+			diff := output.UnifiedDiff(before, after, sourceID+" (current)", valuesFile)
+			if diff == "" {
+				fmt.Println("No changes.")
+				return nil
+			}
+			fmt.Print(output.ColorDiff(diff))
+			return &cmdutil.ExitError{Code: 1}
+		},
+	}
+
+	cmd.Flags().StringVar(&sourceID, "source", "", "Helm source ID")
+	cmd.Flags().StringVarP(&valuesFile, "values", "f", "", "Values YAML/JSON file")
+	_ = cmd.MarkFlagRequired("values")
+
+	return cmd
+}
+
+// valuesYAML renders values as YAML with sorted keys. It round-trips
+// through JSON first so values from the API and from a file compare equal
+// regardless of how they were decoded (e.g. 2 vs 2.0).
+func valuesYAML(values any) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	var normalized map[string]any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return "", err
+	}
+	if len(normalized) == 0 {
+		return "", nil
+	}
+	out, err := yaml.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// findSource returns the helm source with the given ID, or the only one
+// when id is empty.
+func findSource(sources []api.HelmSource, id string) (api.HelmSource, error) {
+	if id == "" {
+		if len(sources) == 1 {
+			return sources[0], nil
+		}
+		return api.HelmSource{}, fmt.Errorf("--source is required: the template has %d helm sources (%s)", len(sources), sourceIDs(sources))
+	}
+	for _, s := range sources {
+		if s.Id == id {
+			return s, nil
+		}
+	}
+	return api.HelmSource{}, fmt.Errorf("unknown helm source %q (template sources: %s)", id, sourceIDs(sources))
+}
+
+func sourceIDs(sources []api.HelmSource) string {
+	ids := make([]string, len(sources))
+	for i, s := range sources {
+		ids[i] = s.Id
+	}
+	return strings.Join(ids, ", ")
+}
@@ -0,0 +1,94 @@
+package installs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+func newCmdEvents() *cobra.Command {
+	var watch bool
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "events [install-id]",
+		Short: "Show Kubernetes and workflow events for an install",
+		Long: `Shows recent Kubernetes events (image pulls, scheduling, probes) and
+deployment workflow events for an install, oldest first.
+
+Use this when pods aren't starting and there are no logs yet.
+With --watch, keeps polling and prints new events as they arrive.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !prompt.IsInteractive() {
+				return fmt.Errorf("<install-id> argument required when not running interactively")
+			}
+
+			client, cfg, err := cmdutil.NewClient()
+			if err != nil {
+				return err
+			}
+
+			installID := ""
+			if len(args) > 0 {
+				installID = args[0]
+			} else {
+				installID, err = pickInstall(cmd.Context(), client, cfg)
+				if err != nil {
+					return err
+				}
+			}
+
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+
+			events, err := fetchEvents(cmd.Context(), client, installID, &api.GetV1InstallsIdEventsParams{Limit: &limit})
+			if err != nil {
+				return err
+			}
+
+			if !watch {
+				if format.IsJSON() {
+					return output.PrintData(format, events)
+				}
+				if len(events) == 0 {
+					fmt.Println("No events found for this install.")
+					return nil
+				}
+				cmdutil.PrintEvents(events)
+				return nil
+			}
+
+			return cmdutil.WatchEvents(cmd.Context(), events, format, func(ctx context.Context, since float64) ([]api.Event, error) {
+				params := &api.GetV1InstallsIdEventsParams{}
+				if since > 0 {
+					params.Since = &since
+				}
+				return fetchEvents(ctx, client, installID, params)
+			})
+		},
+	}
+
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Watch for new events")
+	cmd.Flags().IntVar(&limit, "limit", 100, "Maximum number of recent events to show (1-500)")
+
+	return cmd
+}
+
+func fetchEvents(ctx context.Context, client *api.ClientWithResponses, installID string, params *api.GetV1InstallsIdEventsParams) ([]api.Event, error) {
+	resp, err := client.GetV1InstallsIdEventsWithResponse(ctx, installID, params)
+	if err != nil {
+		return nil, fmt.Errorf("fetching events: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+	}
+	return resp.JSON200.Data, nil
+}
@@ -1,19 +1,25 @@
 package installs
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
-	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/cnap-tech/cli/internal/api"
 	"github.com/cnap-tech/cli/internal/cmdutil"
 	"github.com/cnap-tech/cli/internal/config"
 	"github.com/cnap-tech/cli/internal/prompt"
 	"github.com/cnap-tech/cli/internal/useragent"
+	"github.com/cnap-tech/cli/internal/wsterm"
 	"github.com/coder/websocket"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
@@ -21,6 +27,8 @@ import (
 
 func newCmdExec() *cobra.Command {
 	var pod, container, shell string
+	var reconnect int
+	var tty bool
 
 	cmd := &cobra.Command{
 		Use:   "exec [install-id]",
@@ -29,12 +37,34 @@ func newCmdExec() *cobra.Command {
 
 When run interactively without arguments, shows pickers to select an
 install, pod, and container. In non-interactive environments, all
-arguments and flags are required.`,
+arguments and flags are required.
+
+With --reconnect, a dropped connection (e.g. a VPN blip) is re-dialed with
+backoff. A clean exit of the remote shell is never retried.
+
+With --tty=false (the default when stdin isn't a terminal), piped stdin is
+run by the remote shell as a script and its output streamed, without echo
+or prompts. The command exits when stdin ends and the script completes,
+with the script's exit status. The exec endpoint only offers a terminal,
+so the script's stdout and stderr both arrive on stdout, and the script
+is sent base64 encoded: the container needs stty and base64.
+
+  echo 'ps aux' | cnap installs exec inst_123 --pod web-0 --container app --tty=false`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 && !prompt.IsInteractive() {
 				return fmt.Errorf("<install-id> argument required when not running interactively")
 			}
+			stdinTTY := term.IsTerminal(int(os.Stdin.Fd()))
+			if !cmd.Flags().Changed("tty") {
+				tty = stdinTTY
+			}
+			if tty && !stdinTTY {
+				return fmt.Errorf("stdin is not a terminal; pass --tty=false to pipe input to the shell")
+			}
+			if !tty && reconnect > 0 {
+				return fmt.Errorf("--reconnect can't be combined with --tty=false: piped input can't be replayed")
+			}
 
 			client, cfg, err := cmdutil.NewClient()
 			if err != nil {
@@ -46,200 +76,389 @@ arguments and flags are required.`,
 			if len(args) > 0 {
 				installID = args[0]
 			} else {
-				installID, err = pickInstall(cmd.Context(), client)
+				installID, err = pickInstall(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
 			}
 
-			// Interactive pod picker if --pod not set
-			if pod == "" && prompt.IsInteractive() {
-				podsResp, err := client.GetV1InstallsIdPodsWithResponse(cmd.Context(), installID)
-				if err != nil {
-					return fmt.Errorf("fetching pods: %w", err)
-				}
-				if podsResp.JSON200 != nil && len(podsResp.JSON200.Data) > 0 {
-					podOpts := make([]prompt.SelectOption, len(podsResp.JSON200.Data))
-					for i, p := range podsResp.JSON200.Data {
-						podOpts[i] = prompt.SelectOption{
-							Label: p.Name + " [" + strings.Join(p.Containers, ", ") + "]",
-							Value: p.Name,
-						}
-					}
-
-					pod, err = prompt.Select("Select a pod", podOpts)
-					if err != nil {
-						return err
-					}
-
-					// Interactive container picker if pod has multiple containers
-					if container == "" {
-						for _, p := range podsResp.JSON200.Data {
-							if p.Name == pod {
-								if len(p.Containers) > 1 {
-									containerOpts := make([]prompt.SelectOption, len(p.Containers))
-									for i, c := range p.Containers {
-										containerOpts[i] = prompt.SelectOption{Label: c, Value: c}
-									}
-									container, err = prompt.Select("Select a container", containerOpts)
-									if err != nil {
-										return err
-									}
-								} else if len(p.Containers) == 1 {
-									container = p.Containers[0]
-								}
-								break
-							}
-						}
-					}
-				}
+			pod, container, err = selectPodContainer(cmd.Context(), client, installID, pod, container)
+			if err != nil {
+				return err
 			}
 
 			if pod == "" || container == "" {
 				return fmt.Errorf("--pod and --container are required")
 			}
 
-			return runExec(cmd.Context(), cfg, installID, pod, container, shell)
+			if !tty {
+				execURL, err := execURL(cfg, installID, pod, container, shell)
+				if err != nil {
+					return err
+				}
+				return runExecPiped(cmd.Context(), cfg, execURL, shell, os.Stdin, os.Stdout, os.Stderr)
+			}
+			return runExec(cmd.Context(), cfg, installID, pod, container, shell, reconnect)
 		},
 	}
 
 	cmd.Flags().StringVar(&pod, "pod", "", "Pod name")
 	cmd.Flags().StringVar(&container, "container", "", "Container name")
 	cmd.Flags().StringVar(&shell, "shell", "/bin/sh", "Shell to use")
+	cmd.Flags().IntVar(&reconnect, "reconnect", 0, "Reconnect up to N times if the connection drops (--reconnect alone means 3)")
+	cmd.Flags().Lookup("reconnect").NoOptDefVal = "3"
+	cmd.Flags().BoolVar(&tty, "tty", true, "Attach the terminal; --tty=false pipes stdin to the shell (default false when stdin isn't a terminal)")
 
 	return cmd
 }
 
-// runExec connects to the WebSocket exec endpoint and bridges it to the local terminal.
-func runExec(parentCtx context.Context, cfg *config.Config, installID, podName, containerName, shell string) error {
-	// Build WebSocket URL from the dashboard/auth URL (where exec handler lives)
-	baseURL := cfg.AuthBaseURL()
-	u, err := url.Parse(baseURL)
+// selectPodContainer shows pod and container pickers for whichever of pod
+// and container is empty, when running interactively. A pod with a single
+// container selects it without asking.
+func selectPodContainer(ctx context.Context, client *api.ClientWithResponses, installID, pod, container string) (string, string, error) {
+	if pod != "" || !prompt.IsInteractive() {
+		return pod, container, nil
+	}
+
+	podsResp, err := client.GetV1InstallsIdPodsWithResponse(ctx, installID)
 	if err != nil {
-		return fmt.Errorf("parsing auth URL: %w", err)
+		return "", "", fmt.Errorf("fetching pods: %w", err)
+	}
+	if podsResp.JSON200 == nil || len(podsResp.JSON200.Data) == 0 {
+		return pod, container, nil
 	}
 
-	// Convert http(s) to ws(s)
-	switch u.Scheme {
-	case "https":
-		u.Scheme = "wss"
-	default:
-		u.Scheme = "ws"
+	podOpts := make([]prompt.SelectOption, len(podsResp.JSON200.Data))
+	for i, p := range podsResp.JSON200.Data {
+		podOpts[i] = prompt.SelectOption{Label: podLabel(p), Value: p.Name}
+	}
+	pod, err = prompt.Select("Select a pod", podOpts)
+	if err != nil {
+		return "", "", err
+	}
+
+	if container != "" {
+		return pod, container, nil
+	}
+	for _, p := range podsResp.JSON200.Data {
+		if p.Name != pod {
+			continue
+		}
+		switch {
+		case len(p.Containers) > 1:
+			containerOpts := make([]prompt.SelectOption, len(p.Containers))
+			for i, c := range p.Containers {
+				containerOpts[i] = prompt.SelectOption{Label: containerLabel(p, c), Value: c}
+			}
+			container, err = prompt.Select("Select a container", containerOpts)
+			if err != nil {
+				return "", "", err
+			}
+		case len(p.Containers) == 1:
+			container = p.Containers[0]
+		}
+		break
+	}
+	return pod, container, nil
+}
+
+// Backoff between reconnect attempts for exec --reconnect and logs --follow.
+var (
+	reconnectInitialBackoff = time.Second
+	reconnectMaxBackoff     = 10 * time.Second
+)
+
+// runExec connects to the WebSocket exec endpoint and bridges it to the local terminal.
+// If reconnect > 0, an unexpected drop is retried up to that many times.
+func runExec(parentCtx context.Context, cfg *config.Config, installID, podName, containerName, shell string, reconnect int) error {
+	execURL, err := execURL(cfg, installID, podName, containerName, shell)
+	if err != nil {
+		return err
 	}
-	u.Path = fmt.Sprintf("/api/exec/installs/%s/shell", installID)
-	q := u.Query()
-	q.Set("podName", podName)
-	q.Set("containerName", containerName)
-	q.Set("shell", shell)
-	u.RawQuery = q.Encode()
 
 	ctx, cancel := context.WithCancel(parentCtx)
 	defer cancel()
 
+	dial := func() (*websocket.Conn, error) {
+		return dialExec(ctx, cfg, execURL)
+	}
+
 	// Connect
-	conn, resp, err := websocket.Dial(ctx, u.String(), &websocket.DialOptions{
-		HTTPHeader: http.Header{
-			"Authorization": []string{"Bearer " + cfg.Token()},
-			"User-Agent":    []string{useragent.String()},
-		},
-	})
+	conn, err := dial()
 	if err != nil {
-		if resp != nil {
-			return fmt.Errorf("WebSocket connection failed (HTTP %d): %w", resp.StatusCode, err)
-		}
-		return fmt.Errorf("WebSocket connection failed: %w", err)
+		return err
 	}
-	defer func() { _ = conn.CloseNow() }()
 
 	// Put terminal in raw mode
 	fd := int(os.Stdin.Fd())
 	if !term.IsTerminal(fd) {
+		_ = conn.CloseNow()
 		return fmt.Errorf("stdin is not a terminal")
 	}
 	oldState, err := term.MakeRaw(fd)
 	if err != nil {
+		_ = conn.CloseNow()
 		return fmt.Errorf("setting raw terminal mode: %w", err)
 	}
 	defer func() { _ = term.Restore(fd, oldState) }()
+	cmdutil.MarkRawTerminal()
 
-	// Send initial terminal size
-	sendResize(ctx, conn)
+	// stdin is read for the lifetime of the command rather than per connection,
+	// so keystrokes typed while reconnecting are buffered for the next session.
+	input := make(chan []byte, 64)
+	go wsterm.ReadInput(os.Stdin, input)
 
-	// Start platform-specific resize monitoring (SIGWINCH on Unix, polling on Windows)
-	resizeStop := make(chan struct{})
-	go monitorResize(ctx, conn, resizeStop)
-
-	done := make(chan struct{})
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
 
-	// Goroutine: read from WebSocket → write to stdout
+	// Ctrl+C from outside the terminal (e.g. kill -INT) ends the session cleanly
+	interrupted := make(chan struct{})
+	var current atomic.Pointer[wsterm.Session]
 	go func() {
-		defer close(done)
-		for {
-			_, data, err := conn.Read(ctx)
-			if err != nil {
-				return
+		select {
+		case <-sigCh:
+			close(interrupted)
+			if s := current.Load(); s != nil {
+				_ = s.Close()
+			}
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		session := wsterm.NewSession(conn, os.Stdout, os.Stderr, int(os.Stdout.Fd()))
+		current.Store(session)
+		code, err := session.Run(ctx, input)
+		_ = conn.CloseNow()
+		if isClosed(interrupted) {
+			return nil
+		}
+		if code != 0 {
+			return &cmdutil.ExitError{Code: code}
+		}
+		// Only dropped connections are retried, never a clean remote exit.
+		if !errors.Is(err, wsterm.ErrConnLost) {
+			return err
+		}
+
+		// Each drop gets a fresh set of attempts.
+		backoff := reconnectInitialBackoff
+		for attempt := 1; ; attempt++ {
+			if attempt > reconnect {
+				return err
 			}
-			var msg wsMessage
-			if json.Unmarshal(data, &msg) != nil {
-				continue
+			_, _ = fmt.Fprintf(os.Stderr, "\r\n%s; reconnecting (%d/%d)...\r\n", err, attempt, reconnect)
+
+			select {
+			case <-time.After(backoff):
+			case <-interrupted:
+				return nil
+			case <-ctx.Done():
+				return nil
 			}
-			switch msg.Type {
-			case "output":
-				_, _ = os.Stdout.Write([]byte(msg.Data))
-			case "error":
-				_, _ = fmt.Fprintf(os.Stderr, "\r\nError: %s\r\n", msg.Message)
-			case "close":
-				return
+			backoff = min(backoff*2, reconnectMaxBackoff)
+
+			if conn, err = dial(); err == nil {
+				break
 			}
 		}
-	}()
+	}
+}
 
-	// Goroutine: read from stdin → send to WebSocket
+// The exec endpoint only offers a TTY, which echoes input, interprets
+// control bytes and caps line length. For piped input the prelude turns
+// off echo and prompts, prints a marker, then decodes the rest of the
+// input with base64(1) and runs it in the shell; output up to the marker
+// is the echoed prelude. The marker is printed in two halves that never
+// appear joined in the echo.
+const execPipedMarker = "__CNAP_EXEC_BEGIN__"
+
+func execPipedPrelude(shell string) string {
+	return `stty -echo 2>/dev/null; PS1=''; PS2=''; printf '%s%s\n' __CNAP_EXEC _BEGIN__; base64 -d | ` + shellQuote(shell) + "; exit $?\n"
+}
+
+// execPipedStartTimeout bounds the wait for the prelude's marker, e.g. when
+// the shell never runs it; a variable so tests can shorten it.
+var execPipedStartTimeout = 30 * time.Second
+
+// runExecPiped runs stdin as a script in the remote shell and copies its
+// output to stdout. The remote TTY merges the script's stderr into its
+// stdout. stdin is sent base64 encoded once the prelude has taken effect,
+// and followed by EOF (Ctrl-D at the start of a line), which ends the
+// shell with the status of the script.
+func runExecPiped(parentCtx context.Context, cfg *config.Config, execURL, shell string, stdin io.Reader, stdout, stderr io.Writer) error {
+	ctx, cancel := context.WithCancelCause(parentCtx)
+	defer cancel(nil)
+
+	conn, err := dialExec(ctx, cfg, execURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.CloseNow() }()
+
+	errNoStart := fmt.Errorf("the remote shell didn't start the script within %s (does the container have stty and base64?)", execPipedStartTimeout)
+	out := &pipedOutput{w: stdout, ready: make(chan struct{})}
+	input := make(chan []byte, 64)
 	go func() {
-		buf := make([]byte, 1024)
-		for {
-			n, err := os.Stdin.Read(buf)
-			if err != nil || n == 0 {
-				return
+		defer close(input)
+		send := func(data []byte) bool {
+			select {
+			case input <- data:
+				return true
+			case <-ctx.Done():
+				return false
 			}
-			msg, _ := json.Marshal(wsMessage{Type: "input", Data: string(buf[:n])})
-			if conn.Write(ctx, websocket.MessageText, msg) != nil {
+		}
+		if !send([]byte(execPipedPrelude(shell))) {
+			return
+		}
+		select {
+		case <-out.ready:
+		case <-time.After(execPipedStartTimeout):
+			cancel(errNoStart)
+			return
+		case <-ctx.Done():
+			return
+		}
+
+		// Wrapped like base64(1) output, each chunk of stdin is sent as
+		// soon as it is read.
+		var buf bytes.Buffer
+		lines := &lineWrapper{w: &buf, width: 76}
+		enc := base64.NewEncoder(base64.StdEncoding, lines)
+		piped := make(chan []byte, 64)
+		go wsterm.ReadInput(stdin, piped)
+		for chunk := range piped {
+			_, _ = enc.Write(chunk)
+			if buf.Len() > 0 && !send(bytes.Clone(buf.Bytes())) {
 				return
 			}
+			buf.Reset()
 		}
+		_ = enc.Close()
+		_ = lines.Close()
+		buf.WriteString("\x04")
+		send(buf.Bytes())
 	}()
 
-	// Goroutine: handle Ctrl+C
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt)
-	defer signal.Stop(sigCh)
+	code, err := wsterm.NewSession(conn, out, stderr, -1).Run(ctx, input)
+	out.flush()
+	if cause := context.Cause(ctx); errors.Is(cause, errNoStart) {
+		return cause
+	}
+	if code != 0 {
+		return &cmdutil.ExitError{Code: code}
+	}
+	return err
+}
 
-	go func() {
-		select {
-		case <-sigCh:
-			_ = conn.Close(websocket.StatusNormalClosure, "")
-		case <-done:
+// pipedOutput copies remote output to w from the line after
+// execPipedMarker on, closing ready when the marker arrives. It undoes the
+// TTY's \n to \r\n translation, holding back a trailing \r until the
+// next write shows whether a \n follows.
+type pipedOutput struct {
+	w     io.Writer
+	ready chan struct{}
+
+	started bool
+	head    []byte // output before the marker line, as far as it can matter
+	cr      bool   // a \r held back from the last write
+}
+
+func (o *pipedOutput) Write(p []byte) (int, error) {
+	n := len(p)
+	if !o.started {
+		o.head = append(o.head, p...)
+		i := bytes.Index(o.head, []byte(execPipedMarker))
+		if i < 0 {
+			// Keep only what could be the start of a marker.
+			o.head = o.head[max(0, len(o.head)-len(execPipedMarker)+1):]
+			return n, nil
 		}
-		close(resizeStop)
-	}()
+		o.head = o.head[i:]
+		_, rest, ok := bytes.Cut(o.head, []byte("\n"))
+		if !ok {
+			return n, nil
+		}
+		o.started, o.head = true, nil
+		close(o.ready)
+		p = rest
+	}
 
-	<-done
-	return nil
+	data := p
+	if o.cr {
+		data = append([]byte{'\r'}, p...)
+		o.cr = false
+	}
+	if len(data) > 0 && data[len(data)-1] == '\r' {
+		o.cr = true
+		data = data[:len(data)-1]
+	}
+	if _, err := o.w.Write(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))); err != nil {
+		return 0, err
+	}
+	return n, nil
 }
 
-type wsMessage struct {
-	Type    string `json:"type"`
-	Data    string `json:"data,omitempty"`
-	Message string `json:"message,omitempty"`
-	Cols    int    `json:"cols,omitempty"`
-	Rows    int    `json:"rows,omitempty"`
+// flush writes a held-back \r.
+func (o *pipedOutput) flush() {
+	if o.cr {
+		_, _ = o.w.Write([]byte{'\r'})
+		o.cr = false
+	}
+}
+
+// execURL builds the exec WebSocket URL. The handler lives on the
+// dashboard/auth origin, not the API.
+func execURL(cfg *config.Config, installID, podName, containerName, shell string) (string, error) {
+	u, err := url.Parse(cfg.AuthBaseURL())
+	if err != nil {
+		return "", fmt.Errorf("parsing auth URL: %w", err)
+	}
+
+	// Convert http(s) to ws(s)
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.Path = fmt.Sprintf("/api/exec/installs/%s/shell", installID)
+	q := u.Query()
+	q.Set("podName", podName)
+	q.Set("containerName", containerName)
+	q.Set("shell", shell)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
 }
 
-func sendResize(ctx context.Context, conn *websocket.Conn) {
-	w, h, err := term.GetSize(int(os.Stdout.Fd()))
+func dialExec(ctx context.Context, cfg *config.Config, execURL string) (*websocket.Conn, error) {
+	httpClient, err := cmdutil.WebSocketClient(execURL)
+	if err != nil {
+		return nil, err
+	}
+	conn, resp, err := websocket.Dial(ctx, execURL, &websocket.DialOptions{
+		HTTPClient: httpClient,
+		HTTPHeader: http.Header{
+			"Authorization": []string{"Bearer " + cfg.Token()},
+			"User-Agent":    []string{useragent.String()},
+		},
+	})
 	if err != nil {
-		return
+		if resp != nil {
+			return nil, fmt.Errorf("WebSocket connection failed (HTTP %d): %w", resp.StatusCode, err)
+		}
+		return nil, fmt.Errorf("WebSocket connection failed: %w", err)
+	}
+	return conn, nil
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
 	}
-	msg, _ := json.Marshal(wsMessage{Type: "resize", Cols: w, Rows: h})
-	_ = conn.Write(ctx, websocket.MessageText, msg)
 }
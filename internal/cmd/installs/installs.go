@@ -4,13 +4,26 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/cnap-tech/cli/internal/api"
 	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/config"
+	"github.com/cnap-tech/cli/internal/namecache"
 	"github.com/cnap-tech/cli/internal/output"
 	"github.com/cnap-tech/cli/internal/prompt"
 	"github.com/spf13/cobra"
@@ -20,19 +33,35 @@ import (
 func NewCmdInstalls() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "installs",
-		Aliases: []string{"install", "inst"},
+		Aliases: []string{"install", "inst", "ins"},
 		Short:   "Manage installs",
 	}
 
 	cmd.AddCommand(newCmdList())
 	cmd.AddCommand(newCmdGet())
+	cmd.AddCommand(newCmdDescribe())
 	cmd.AddCommand(newCmdCreate())
 	cmd.AddCommand(newCmdDelete())
 	cmd.AddCommand(newCmdUpdateValues())
 	cmd.AddCommand(newCmdUpdateOverrides())
+	cmd.AddCommand(newCmdRename())
 	cmd.AddCommand(newCmdPods())
+	cmd.AddCommand(newCmdEvents())
 	cmd.AddCommand(newCmdLogs())
 	cmd.AddCommand(newCmdExec())
+	cmd.AddCommand(newCmdCp())
+	cmd.AddCommand(newCmdTop())
+	cmd.AddCommand(newCmdRevisions())
+	cmd.AddCommand(newCmdRollback())
+	cmd.AddCommand(newCmdDiff())
+	cmd.AddCommand(newCmdValuesSchema())
+	cmd.AddCommand(newCmdScale())
+
+	for _, c := range cmd.Commands() {
+		if strings.Contains(c.Use, "[install-id]") && c.ValidArgsFunction == nil {
+			c.ValidArgsFunction = completeInstallIDs
+		}
+	}
 
 	return cmd
 }
@@ -40,47 +69,81 @@ func NewCmdInstalls() *cobra.Command {
 func newCmdList() *cobra.Command {
 	var limit int
 	var cursor string
+	var all, noStatus bool
+	var productID, clusterID, regionID string
 
 	cmd := &cobra.Command{
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List installs in the active workspace",
+		Long: `Lists installs in the active workspace.
+
+--product, --cluster and --region filter on the server. --product can be
+combined with either of the others; a cluster belongs to a single region,
+so --cluster and --region are mutually exclusive.
+
+The STATUS column is each install's health, or Failed when its latest
+workflow failed, looked up concurrently. Use --no-status to skip those
+lookups on large workspaces.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, cfg, err := cmdutil.NewClient()
 			if err != nil {
 				return err
 			}
 
-			if cfg.ActiveWorkspace == "" {
-				return fmt.Errorf("no active workspace. Run: cnap workspaces switch <id>")
+			if err := cmdutil.RequireWorkspace(cfg); err != nil {
+				return err
 			}
 
-			params := &api.GetV1InstallsParams{Limit: &limit}
-			if cursor != "" {
-				params.Cursor = &cursor
+			items, page, listErr := cmdutil.ListPages(cursor, all, func(cursor *string) ([]api.Install, *api.Pagination, error) {
+				resp, err := client.GetV1InstallsWithResponse(cmd.Context(), &api.GetV1InstallsParams{
+					Limit:     &limit,
+					Cursor:    cursor,
+					ProductId: optional(productID),
+					ClusterId: optional(clusterID),
+					RegionId:  optional(regionID),
+				})
+				if err != nil {
+					return nil, nil, fmt.Errorf("fetching installs: %w", err)
+				}
+				if resp.JSON200 == nil {
+					return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403, resp.JSON422)
+				}
+				return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+			})
+			if listErr != nil && !cmdutil.IsPartial(listErr) {
+				return listErr
 			}
+			cacheInstallNames(items...)
 
-			resp, err := client.GetV1InstallsWithResponse(cmd.Context(), params)
+			format, err := cmdutil.GetOutputFormat(cfg)
 			if err != nil {
-				return fmt.Errorf("fetching installs: %w", err)
-			}
-			if resp.JSON200 == nil {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON403)
+				return err
 			}
-
-			format := cmdutil.GetOutputFormat(cfg)
-			if format == output.FormatJSON {
-				return output.PrintJSON(resp.JSON200)
+			if format.IsJSON() {
+				if err := output.PrintData(format, cmdutil.NewListOutput(items, page)); err != nil {
+					return err
+				}
+				return listErr
 			}
 
-			if len(resp.JSON200.Data) == 0 {
+			if len(items) == 0 {
+				if productID != "" || clusterID != "" || regionID != "" {
+					fmt.Println("No installs match the given filters.")
+					return listErr
+				}
 				fmt.Println("No installs found in this workspace.")
-				return nil
+				return listErr
 			}
 
 			header := []string{"ID", "NAME", "PRODUCT", "CLUSTER", "CREATED"}
+			var statuses []string
+			if !noStatus {
+				header = append(header, "STATUS")
+				statuses = fetchInstallStatuses(cmd.Context(), client, items)
+			}
 			var rows [][]string
-			for _, i := range resp.JSON200.Data {
+			for n, i := range items {
 				name := "-"
 				if i.Name != nil {
 					name = *i.Name
@@ -89,28 +152,41 @@ func newCmdList() *cobra.Command {
 				if i.ProductId != nil {
 					productId = *i.ProductId
 				}
-				rows = append(rows, []string{i.Id, name, productId, i.ClusterId, formatTime(i.CreatedAt)})
+				row := []string{i.Id, name, productId, i.ClusterId, formatTime(i.CreatedAt)}
+				if statuses != nil {
+					row = append(row, statuses[n])
+				}
+				rows = append(rows, row)
 			}
 
-			output.PrintTable(header, rows)
-			if resp.JSON200.Pagination.HasMore {
-				fmt.Printf("\nMore results available. Use --cursor %s to see next page.\n", *resp.JSON200.Pagination.Cursor)
-			}
-			return nil
+			output.PrintStyledTable(header, rows, map[string]output.StyleFunc{"STATUS": output.StatusStyle})
+			cmdutil.PrintPageHint(format, page)
+			return listErr
 		},
 	}
 
 	cmd.Flags().IntVar(&limit, "limit", 50, "Items per page (1-100)")
 	cmd.Flags().StringVar(&cursor, "cursor", "", "Pagination cursor from previous response")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch every page (--limit sets the page size)")
+	cmd.Flags().BoolVar(&noStatus, "no-status", false, "Skip fetching each install's status")
+	cmd.Flags().StringVar(&productID, "product", "", "Only installs of this product ID")
+	cmd.Flags().StringVar(&clusterID, "cluster", "", "Only installs on this cluster ID")
+	cmd.Flags().StringVar(&regionID, "region", "", "Only installs in this region ID")
+	cmd.MarkFlagsMutuallyExclusive("cluster", "region")
 
 	return cmd
 }
 
 func newCmdGet() *cobra.Command {
-	return &cobra.Command{
+	var noStatus bool
+
+	cmd := &cobra.Command{
 		Use:   "get [install-id]",
 		Short: "Get install details",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Shows install details along with its live status and a pod summary.
+
+Use --no-status to skip the extra status and pod lookups.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 && !prompt.IsInteractive() {
 				return fmt.Errorf("<install-id> argument required when not running interactively")
@@ -125,7 +201,7 @@ func newCmdGet() *cobra.Command {
 			if len(args) > 0 {
 				installID = args[0]
 			} else {
-				installID, err = pickInstall(cmd.Context(), client)
+				installID, err = pickInstall(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
@@ -136,30 +212,180 @@ func newCmdGet() *cobra.Command {
 				return fmt.Errorf("fetching install: %w", err)
 			}
 			if resp.JSON200 == nil {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON404)
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
 			}
+			cacheInstallNames(*resp.JSON200)
 
-			format := cmdutil.GetOutputFormat(cfg)
-			if format == output.FormatJSON {
-				return output.PrintJSON(resp.JSON200)
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+			if format.IsJSON() {
+				return output.PrintData(format, resp.JSON200)
 			}
 
 			i := resp.JSON200
 
-			output.PrintTable(
-				[]string{"FIELD", "VALUE"},
-				[][]string{
-					{"ID", i.Id},
-					{"Name", deref(i.Name)},
-					{"Workspace", i.WorkspaceId},
-					{"Product", deref(i.ProductId)},
-					{"Template", deref(i.TemplateId)},
-					{"Cluster", i.ClusterId},
-				},
-			)
+			rows := [][]string{
+				{"ID", i.Id},
+				{"Name", deref(i.Name)},
+				{"Workspace", i.WorkspaceId},
+				{"Product", deref(i.ProductId)},
+				{"Template", deref(i.TemplateId)},
+				{"Cluster", i.ClusterId},
+			}
+			if !noStatus {
+				status, pods := fetchInstallHealth(cmd.Context(), client, installID)
+				rows = append(rows, []string{"Status", status}, []string{"Pods", pods})
+			}
+
+			output.PrintTable([]string{"FIELD", "VALUE"}, rows)
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&noStatus, "no-status", false, "Skip fetching live status and pods")
+
+	return cmd
+}
+
+// fetchInstallHealth fetches the install status and pods concurrently and
+// returns a display string for each. A failed lookup yields "-" so that get
+// still works while the cluster is unreachable.
+func fetchInstallHealth(ctx context.Context, client *api.ClientWithResponses, installID string) (status, pods string) {
+	status, pods = "-", "-"
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		resp, err := client.GetV1InstallsIdStatusWithResponse(ctx, installID)
+		if err != nil || resp.JSON200 == nil {
+			slog.Debug("fetching install status failed", "install", installID, "error", err)
+			return
+		}
+		status = formatInstallStatus(resp.JSON200)
+	}()
+
+	go func() {
+		defer wg.Done()
+		resp, err := client.GetV1InstallsIdPodsWithResponse(ctx, installID)
+		if err != nil || resp.JSON200 == nil {
+			slog.Debug("fetching install pods failed", "install", installID, "error", err)
+			return
+		}
+		pods = podSummary(resp.JSON200.Data)
+	}()
+
+	wg.Wait()
+	return status, pods
+}
+
+// fetchInstallStatuses looks up the status of each install concurrently,
+// returning "-" for any that can't be fetched.
+func fetchInstallStatuses(ctx context.Context, client *api.ClientWithResponses, installs []api.Install) []string {
+	statuses, errs := cmdutil.MapConcurrent(ctx, installs, cmdutil.DefaultConcurrency, func(ctx context.Context, i api.Install) (string, error) {
+		resp, err := client.GetV1InstallsIdStatusWithResponse(ctx, i.Id)
+		if err != nil {
+			return "", err
+		}
+		if resp.JSON200 == nil {
+			return "", cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+		}
+		return listStatus(resp.JSON200), nil
+	})
+	for n, err := range errs {
+		if err != nil {
+			slog.Debug("fetching install status failed", "install", installs[n].Id, "error", err)
+			statuses[n] = "-"
+		}
+	}
+	return statuses
+}
+
+// listStatus condenses an install status to one word for list tables:
+// Failed if the latest workflow failed, else the health.
+func listStatus(s *api.InstallStatus) string {
+	if strings.EqualFold(s.Phase, "failed") {
+		return "Failed"
+	}
+	if s.Health == "" {
+		return "-"
+	}
+	return s.Health
+}
+
+// formatInstallStatus renders health and workflow phase, e.g. "Healthy (workflow: succeeded)".
+func formatInstallStatus(s *api.InstallStatus) string {
+	out := s.Health + " (workflow: " + s.Phase + ")"
+	if s.Message != nil && *s.Message != "" {
+		out += " — " + *s.Message
+	}
+	return out
+}
+
+// podSummary renders a one-line readiness summary, e.g. "2/3 pods ready".
+// Falls back to a plain count if the server doesn't report readiness.
+func podSummary(pods []api.Pod) string {
+	ready, known := 0, false
+	for _, p := range pods {
+		if p.Ready != nil {
+			known = true
+			if *p.Ready {
+				ready++
+			}
+		}
+	}
+	if !known {
+		return fmt.Sprintf("%d pod(s)", len(pods))
+	}
+	return fmt.Sprintf("%d/%d pods ready", ready, len(pods))
+}
+
+// podLabel renders a pod for pickers with each container's state, e.g.
+// "web-0 [app(ready), sidecar(crashloop)]", so a dead container is obvious
+// before it's picked.
+func podLabel(p api.Pod) string {
+	return p.Name + " [" + strings.Join(containerLabels(p), ", ") + "]"
+}
+
+// containerLabels renders each of the pod's containers with its state.
+func containerLabels(p api.Pod) []string {
+	labels := make([]string, len(p.Containers))
+	for i, c := range p.Containers {
+		labels[i] = containerLabel(p, c)
+	}
+	return labels
+}
+
+// containerLabel renders a container name with its state, e.g. "app(ready)".
+// Returns the bare name if the server didn't report container statuses.
+func containerLabel(p api.Pod, container string) string {
+	if p.ContainerStatuses == nil {
+		return container
+	}
+	for _, cs := range *p.ContainerStatuses {
+		if cs.Name == container {
+			return container + "(" + containerState(cs) + ")"
+		}
+	}
+	return container
+}
+
+func containerState(cs api.ContainerStatus) string {
+	switch {
+	case cs.Ready:
+		return "ready"
+	case cs.Reason != nil && *cs.Reason == "CrashLoopBackOff":
+		return "crashloop"
+	case cs.Reason != nil && *cs.Reason != "":
+		return strings.ToLower(*cs.Reason)
+	case cs.State == api.Running:
+		return "not ready"
+	default:
+		return string(cs.State)
+	}
 }
 
 func newCmdDelete() *cobra.Command {
@@ -175,7 +401,7 @@ func newCmdDelete() *cobra.Command {
 				return fmt.Errorf("<install-id> argument required when not running interactively")
 			}
 
-			client, _, err := cmdutil.NewClient()
+			client, cfg, err := cmdutil.NewClient()
 			if err != nil {
 				return err
 			}
@@ -184,7 +410,7 @@ func newCmdDelete() *cobra.Command {
 			if len(args) > 0 {
 				installID = args[0]
 			} else {
-				installID, err = pickInstall(cmd.Context(), client)
+				installID, err = pickInstall(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
@@ -194,7 +420,7 @@ func newCmdDelete() *cobra.Command {
 				if !prompt.IsInteractive() {
 					return fmt.Errorf("use --yes to confirm deletion in non-interactive mode")
 				}
-				confirmed, err := prompt.Confirm(fmt.Sprintf("Delete install %s?", installID))
+				confirmed, err := prompt.Confirm(fmt.Sprintf("Delete install %s?", displayName(installID)))
 				if err != nil {
 					return err
 				}
@@ -208,11 +434,13 @@ func newCmdDelete() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("deleting install: %w", err)
 			}
-			if resp.HTTPResponse.StatusCode != 202 {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON404)
+			job, ok := cmdutil.Succeeded(resp.StatusCode(), resp.Body, cmdutil.DeleteStatuses...)
+			if !ok {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
 			}
 
-			fmt.Printf("Install %s deletion started.\n", installID)
+			namecache.Forget(namecache.Installs, installID)
+			fmt.Printf("Install %s deletion started%s.\n", installID, cmdutil.QueuedAs(job))
 			return nil
 		},
 	}
@@ -223,42 +451,89 @@ func newCmdDelete() *cobra.Command {
 }
 
 func newCmdCreate() *cobra.Command {
-	var productID, regionID string
+	var productID, regionID, name, valuesDir string
+	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a product install",
-		Long:  "Deploys a product to a region. Starts an async workflow.",
+		Long: `Deploys a product to a region. Starts an async workflow.
+
+--name labels the install in list and get output; without it the install
+is unnamed.
+
+With --from-values-dir, each *.yaml file in the directory is applied as
+initial value overrides for the helm source named by the file (e.g.
+<source-id>.yaml). Use --dry-run to preview the mapping.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, cfg, err := cmdutil.NewClient()
 			if err != nil {
 				return err
 			}
 
-			if cfg.ActiveWorkspace == "" {
-				return fmt.Errorf("no active workspace. Run: cnap workspaces switch <id>")
+			if err := cmdutil.RequireWorkspace(cfg); err != nil {
+				return err
 			}
 
 			body := api.PostV1InstallsJSONRequestBody{
 				ProductId: productID,
 				RegionId:  regionID,
 			}
+			if cmd.Flags().Changed("name") {
+				if name = strings.TrimSpace(name); name == "" {
+					return fmt.Errorf("--name must not be empty")
+				}
+				body.Name = &name
+			}
+
+			if valuesDir != "" {
+				files, err := readValuesDir(valuesDir)
+				if err != nil {
+					return err
+				}
+				productResp, err := client.GetV1ProductsIdWithResponse(cmd.Context(), productID)
+				if err != nil {
+					return fmt.Errorf("fetching product: %w", err)
+				}
+				if productResp.JSON200 == nil {
+					return cmdutil.APIError(productResp.Status(), productResp.Body, productResp.JSON401, productResp.JSON404)
+				}
+				sources, err := fetchHelmSources(cmd.Context(), client, productResp.JSON200.TemplateId)
+				if err != nil {
+					return err
+				}
+				overrides, err := matchValuesDir(files, sources)
+				if err != nil {
+					return err
+				}
+				if dryRun {
+					printValuesDirPlan(files, sources)
+					return nil
+				}
+				body.Overrides = &overrides
+			} else if dryRun {
+				return fmt.Errorf("--dry-run requires --from-values-dir")
+			}
 
 			resp, err := client.PostV1InstallsWithResponse(cmd.Context(), nil, body)
 			if err != nil {
 				return fmt.Errorf("creating install: %w", err)
 			}
-			if resp.HTTPResponse.StatusCode != 202 {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON403, resp.JSON422)
+			job, ok := cmdutil.Succeeded(resp.StatusCode(), resp.Body, cmdutil.AsyncStatuses...)
+			if !ok {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403, resp.JSON422)
 			}
 
-			fmt.Println("Install workflow started.")
+			fmt.Printf("Install workflow started%s.\n", cmdutil.QueuedAs(job))
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&productID, "product", "", "Product ID (required)")
 	cmd.Flags().StringVar(&regionID, "region", "", "Region ID (required)")
+	cmd.Flags().StringVar(&name, "name", "", "Install name")
+	cmd.Flags().StringVar(&valuesDir, "from-values-dir", "", "Directory of <source-id>.yaml value override files")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show which file applies to which helm source without creating the install")
 	_ = cmd.MarkFlagRequired("product")
 	_ = cmd.MarkFlagRequired("region")
 
@@ -266,19 +541,24 @@ func newCmdCreate() *cobra.Command {
 }
 
 func newCmdUpdateValues() *cobra.Command {
-	var sourceID, valuesFile string
+	var sourceID, valuesFile, valuesDir string
+	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "update-values [install-id]",
 		Short: "Update install template values",
-		Long:  "Updates template helm source values and regenerates the chart.",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Updates template helm source values and regenerates the chart.
+
+Pass a single source with --source and --values, or a directory with
+--from-values-dir: each *.yaml file in it updates the helm source named by
+the file (e.g. <source-id>.yaml). Use --dry-run to preview the mapping.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 && !prompt.IsInteractive() {
 				return fmt.Errorf("<install-id> argument required when not running interactively")
 			}
 
-			client, _, err := cmdutil.NewClient()
+			client, cfg, err := cmdutil.NewClient()
 			if err != nil {
 				return err
 			}
@@ -287,50 +567,101 @@ func newCmdUpdateValues() *cobra.Command {
 			if len(args) > 0 {
 				installID = args[0]
 			} else {
-				installID, err = pickInstall(cmd.Context(), client)
+				installID, err = pickInstall(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
 			}
 
-			values, err := readValuesFile(valuesFile)
-			if err != nil {
-				return err
+			var updates []sourceValues
+			if valuesDir != "" {
+				updates, err = valuesDirUpdates(cmd.Context(), client, installID, valuesDir, dryRun)
+				if err != nil || dryRun {
+					return err
+				}
+			} else {
+				if dryRun {
+					return fmt.Errorf("--dry-run requires --from-values-dir")
+				}
+				values, err := readValuesFile(valuesFile)
+				if err != nil {
+					return err
+				}
+				updates = []sourceValues{{TemplateHelmSourceId: sourceID, Values: values}}
 			}
 
-			body := api.PatchV1InstallsIdValuesJSONRequestBody{
-				Updates: []struct {
-					TemplateHelmSourceId string                  `json:"template_helm_source_id"`
-					Values               map[string]*interface{} `json:"values"`
-				}{
-					{
-						TemplateHelmSourceId: sourceID,
-						Values:               values,
-					},
-				},
+			install, err := fetchInstall(cmd.Context(), client, installID)
+			if err == nil {
+				err = recordRevision(cmd.Context(), client, cfg, install, "update-values")
 			}
+			if err != nil {
+				// Don't block the update; there's just nothing to roll back to.
+				_, _ = fmt.Fprintf(os.Stderr, "Warning: couldn't snapshot current values for rollback: %v\n", err)
+			}
+
+			body := api.PatchV1InstallsIdValuesJSONRequestBody{Updates: updates}
 
 			resp, err := client.PatchV1InstallsIdValuesWithResponse(cmd.Context(), installID, body)
 			if err != nil {
 				return fmt.Errorf("updating install values: %w", err)
 			}
-			if resp.HTTPResponse.StatusCode != 202 {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON404, resp.JSON422)
+			job, ok := cmdutil.Succeeded(resp.StatusCode(), resp.Body, cmdutil.AsyncStatuses...)
+			if !ok {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404, resp.JSON422)
 			}
 
-			fmt.Println("Install values update started.")
+			fmt.Printf("Install values update started%s. Undo with: cnap installs rollback %s\n", cmdutil.QueuedAs(job), installID)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&sourceID, "source", "", "Helm source ID (required)")
-	cmd.Flags().StringVarP(&valuesFile, "values", "f", "", "Values YAML/JSON file (required)")
-	_ = cmd.MarkFlagRequired("source")
-	_ = cmd.MarkFlagRequired("values")
+	cmd.Flags().StringVar(&sourceID, "source", "", "Helm source ID")
+	cmd.Flags().StringVarP(&valuesFile, "values", "f", "", "Values YAML/JSON file")
+	cmd.Flags().StringVar(&valuesDir, "from-values-dir", "", "Directory of <source-id>.yaml values files")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show which file applies to which helm source without updating")
+	cmd.MarkFlagsRequiredTogether("source", "values")
+	cmd.MarkFlagsOneRequired("values", "from-values-dir")
+	cmd.MarkFlagsMutuallyExclusive("values", "from-values-dir")
+	cmd.MarkFlagsMutuallyExclusive("source", "from-values-dir")
 
 	return cmd
 }
 
+// valuesDirUpdates builds update-values updates from a --from-values-dir
+// directory, matched against the install's template sources. With dryRun it
+// prints the mapping and returns nil updates.
+func valuesDirUpdates(ctx context.Context, client *api.ClientWithResponses, installID, dir string, dryRun bool) ([]sourceValues, error) {
+	files, err := readValuesDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.GetV1InstallsIdWithResponse(ctx, installID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching install: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+	}
+	if resp.JSON200.TemplateId == nil {
+		return nil, fmt.Errorf("install %s has no template", installID)
+	}
+
+	sources, err := fetchHelmSources(ctx, client, *resp.JSON200.TemplateId)
+	if err != nil {
+		return nil, err
+	}
+	updates, err := matchValuesDir(files, sources)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		printValuesDirPlan(files, sources)
+		return nil, nil
+	}
+	return updates, nil
+}
+
 func newCmdUpdateOverrides() *cobra.Command {
 	var sourceID, valuesFile string
 
@@ -344,7 +675,7 @@ func newCmdUpdateOverrides() *cobra.Command {
 				return fmt.Errorf("<install-id> argument required when not running interactively")
 			}
 
-			client, _, err := cmdutil.NewClient()
+			client, cfg, err := cmdutil.NewClient()
 			if err != nil {
 				return err
 			}
@@ -353,7 +684,7 @@ func newCmdUpdateOverrides() *cobra.Command {
 			if len(args) > 0 {
 				installID = args[0]
 			} else {
-				installID, err = pickInstall(cmd.Context(), client)
+				installID, err = pickInstall(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
@@ -380,11 +711,12 @@ func newCmdUpdateOverrides() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("updating install overrides: %w", err)
 			}
-			if resp.HTTPResponse.StatusCode != 202 {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON404, resp.JSON422)
+			job, ok := cmdutil.Succeeded(resp.StatusCode(), resp.Body, cmdutil.AsyncStatuses...)
+			if !ok {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404, resp.JSON422)
 			}
 
-			fmt.Println("Install overrides update started.")
+			fmt.Printf("Install overrides update started%s.\n", cmdutil.QueuedAs(job))
 			return nil
 		},
 	}
@@ -398,6 +730,10 @@ func newCmdUpdateOverrides() *cobra.Command {
 }
 
 func readValuesFile(path string) (map[string]*interface{}, error) {
+	path, err := cmdutil.ExpandPath(path)
+	if err != nil {
+		return nil, err
+	}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading values file: %w", err)
@@ -421,12 +757,29 @@ func readValuesFile(path string) (map[string]*interface{}, error) {
 	return result, nil
 }
 
+// podsWatchInterval is how often `pods --watch` refreshes.
+var podsWatchInterval = 2 * time.Second
+
 func newCmdPods() *cobra.Command {
-	return &cobra.Command{
+	var name, status string
+	var wide, watchPods bool
+
+	cmd := &cobra.Command{
 		Use:   "pods [install-id]",
 		Short: "List pods for an install",
-		Args:  cobra.MaximumNArgs(1),
+		Long: `Lists the install's pods and their containers.
+
+--name filters by a glob on the pod name (e.g. 'web-*') and --status by
+phase (Pending, Running, Succeeded, Failed, Unknown), both client-side.
+--wide adds phase, readiness, restarts, age and node columns.
+
+With --watch, shows the wide view and refreshes it every 2 seconds,
+highlighting pods whose phase, readiness, restarts or node changed.`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := path.Match(name, ""); err != nil {
+				return fmt.Errorf("invalid --name pattern %q: %w", name, err)
+			}
 			if len(args) == 0 && !prompt.IsInteractive() {
 				return fmt.Errorf("<install-id> argument required when not running interactively")
 			}
@@ -440,46 +793,192 @@ func newCmdPods() *cobra.Command {
 			if len(args) > 0 {
 				installID = args[0]
 			} else {
-				installID, err = pickInstall(cmd.Context(), client)
+				installID, err = pickInstall(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
 			}
 
-			resp, err := client.GetV1InstallsIdPodsWithResponse(cmd.Context(), installID)
+			format, err := cmdutil.GetOutputFormat(cfg)
 			if err != nil {
-				return fmt.Errorf("fetching pods: %w", err)
-			}
-			if resp.JSON200 == nil {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON404)
+				return err
 			}
 
-			format := cmdutil.GetOutputFormat(cfg)
-			if format == output.FormatJSON {
-				return output.PrintJSON(resp.JSON200.Data)
+			fetch := func() ([]api.Pod, error) {
+				resp, err := client.GetV1InstallsIdPodsWithResponse(cmd.Context(), installID)
+				if err != nil {
+					return nil, fmt.Errorf("fetching pods: %w", err)
+				}
+				if resp.JSON200 == nil {
+					return nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+				}
+				return filterPods(resp.JSON200.Data, name, status), nil
 			}
-
-			if len(resp.JSON200.Data) == 0 {
-				fmt.Println("No pods found for this install.")
-				return nil
+			filtered := name != "" || status != ""
+
+			if watchPods {
+				var last map[string]string
+				return watch(cmd.Context(), podsWatchInterval, format, fetch, func(pods []api.Pod) {
+					changed := map[string]bool{}
+					current := make(map[string]string, len(pods))
+					for _, p := range pods {
+						current[p.Name] = podState(p)
+						if last != nil && last[p.Name] != current[p.Name] {
+							changed[p.Name] = true
+						}
+					}
+					last = current
+					printPods(pods, true, filtered, changed)
+				})
 			}
 
-			header := []string{"POD", "CONTAINERS"}
-			var rows [][]string
-			for _, p := range resp.JSON200.Data {
-				rows = append(rows, []string{p.Name, strings.Join(p.Containers, ", ")})
+			pods, err := fetch()
+			if err != nil {
+				return err
 			}
-
-			output.PrintTable(header, rows)
+			if format.IsJSON() {
+				return output.PrintData(format, pods)
+			}
+			printPods(pods, wide, filtered, nil)
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Only pods whose name matches this glob, e.g. 'web-*'")
+	cmd.Flags().StringVar(&status, "status", "", "Only pods in this phase, e.g. Running")
+	cmd.Flags().BoolVar(&wide, "wide", false, "Show phase, readiness, restarts, age and node")
+	cmd.Flags().BoolVarP(&watchPods, "watch", "w", false, "Refresh the list until interrupted")
+
+	return cmd
+}
+
+// printPods prints the pods table. Pods named in changed are highlighted.
+func printPods(pods []api.Pod, wide, filtered bool, changed map[string]bool) {
+	if len(pods) == 0 {
+		if filtered {
+			fmt.Println("No pods match the given filters.")
+		} else {
+			fmt.Println("No pods found for this install.")
+		}
+		return
+	}
+
+	if !wide {
+		header := []string{"POD", "CONTAINERS"}
+		var rows [][]string
+		for _, p := range pods {
+			rows = append(rows, []string{p.Name, strings.Join(p.Containers, ", ")})
+		}
+		output.PrintTable(header, rows)
+		return
+	}
+
+	rows := make([][]string, len(pods))
+	for i, p := range pods {
+		rows[i] = []string{
+			p.Name,
+			deref(p.Phase),
+			podReady(p),
+			podRestarts(p),
+			formatAge(p.CreatedAt),
+			deref(p.NodeName),
+			strings.Join(p.Containers, ", "),
+		}
+	}
+	output.PrintStyledTable(
+		[]string{"POD", "PHASE", "READY", "RESTARTS", "AGE", "NODE", "CONTAINERS"},
+		rows,
+		map[string]output.StyleFunc{
+			"POD": func(name string) lipgloss.Style {
+				if changed[name] {
+					return output.HighlightStyle()
+				}
+				return lipgloss.NewStyle()
+			},
+			"PHASE": output.StatusStyle,
+		},
+	)
+}
+
+// podState summarizes what --watch compares between refreshes.
+func podState(p api.Pod) string {
+	return strings.Join([]string{deref(p.Phase), podReady(p), podRestarts(p), deref(p.NodeName)}, "|")
+}
+
+// filterPods keeps the pods whose name matches the glob name and whose
+// phase is status (case-insensitive). Empty filters match everything.
+func filterPods(pods []api.Pod, name, status string) []api.Pod {
+	out := []api.Pod{}
+	for _, p := range pods {
+		if name != "" {
+			if ok, _ := path.Match(name, p.Name); !ok {
+				continue
+			}
+		}
+		if status != "" && !strings.EqualFold(deref(p.Phase), status) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// podReady formats readiness as ready/total containers, like kubectl.
+func podReady(p api.Pod) string {
+	if p.ContainerStatuses == nil {
+		if p.Ready != nil {
+			return strconv.FormatBool(*p.Ready)
+		}
+		return "-"
+	}
+	ready := 0
+	for _, c := range *p.ContainerStatuses {
+		if c.Ready {
+			ready++
+		}
+	}
+	return fmt.Sprintf("%d/%d", ready, len(*p.ContainerStatuses))
+}
+
+// podRestarts sums the restart counts of the pod's containers.
+func podRestarts(p api.Pod) string {
+	if p.ContainerStatuses == nil {
+		return "-"
+	}
+	n := 0
+	for _, c := range *p.ContainerStatuses {
+		n += c.RestartCount
+	}
+	return strconv.Itoa(n)
+}
+
+// formatAge formats the time since a Unix timestamp, or "-" if unknown.
+func formatAge(createdAt *float32) string {
+	if createdAt == nil {
+		return "-"
+	}
+	return age(time.Since(time.Unix(int64(*createdAt), 0)))
+}
+
+// age formats d the way kubectl does: 45s, 12m, 5h, 3d.
+func age(d time.Duration) string {
+	switch {
+	case d < 2*time.Minute:
+		return fmt.Sprintf("%ds", max(int(d.Seconds()), 0))
+	case d < 2*time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
 }
 
 func newCmdLogs() *cobra.Command {
-	var pod, container string
-	var follow bool
-	var tail, sinceSeconds int
+	var pod, container, selector string
+	var follow, jsonParse, allPods bool
+	var tail, maxLines int
+	var since, sinceTime string
 
 	cmd := &cobra.Command{
 		Use:   "logs [install-id]",
@@ -488,56 +987,77 @@ func newCmdLogs() *cobra.Command {
 
 When run interactively without arguments, shows pickers to select an
 install, pod, and container. In non-interactive environments (CI, pipes),
-the install ID argument is required.`,
+the install ID argument is required.
+
+--all-pods streams every pod of the install without the pod picker (also
+what happens non-interactively without --pod). --selector (-l) streams
+the pods matching a label selector, e.g. -l app=web,tier!=cache.
+
+Logs are followed by default. With --tail N the last N lines are printed
+and the command exits, unless --follow is also given.
+
+With --pod, a pod that has a single container streams it. For a pod with
+several (e.g. an app and its sidecars), every container is streamed at
+once with each line prefixed by its container name, as with --container
+all; name a container to stream only that one.
+
+While following, a connection that drops is re-established (up to 5
+attempts in a row), resuming from when the last line arrived. Lines the
+new stream repeats from before the drop are skipped. If the install is
+deleted meanwhile, logs stops with exit status 4 instead of retrying.
+
+--max-lines N stops after printing N lines and closes the stream, as a
+client-side guard when --tail is large or unset.
+
+With -o json each line is printed as a JSON object on its own line
+(NDJSON), e.g. {"line":"...","pod":"web-0","container":"app"}. The stream
+doesn't carry per-line metadata, so pod and container are only set when
+selected with --pod and --container (or the pickers), or when every
+container of a pod is streamed.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 && !prompt.IsInteractive() {
 				return fmt.Errorf("<install-id> argument required when not running interactively")
 			}
 
+			sinceSeconds, err := parseSince(since, sinceTime, time.Now())
+			if err != nil {
+				return err
+			}
+			if maxLines < 0 {
+				return fmt.Errorf("--max-lines must be 0 or more, got %d", maxLines)
+			}
+			if selector != "" {
+				if err := validateSelector(selector); err != nil {
+					return err
+				}
+			}
+
 			client, cfg, err := cmdutil.NewClient()
 			if err != nil {
 				return err
 			}
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+			if jsonParse && format.IsJSON() {
+				return fmt.Errorf("--json-parse can't be combined with -o %s", format)
+			}
 
 			var installID string
 
 			if len(args) > 0 {
 				installID = args[0]
 			} else {
-				// Interactive install picker
-				if cfg.ActiveWorkspace == "" {
-					return fmt.Errorf("no active workspace. Run: cnap workspaces switch <id>")
-				}
-				limit := 100
-				listResp, err := client.GetV1InstallsWithResponse(cmd.Context(), &api.GetV1InstallsParams{Limit: &limit})
-				if err != nil {
-					return fmt.Errorf("fetching installs: %w", err)
-				}
-				if listResp.JSON200 == nil {
-					return apiError(listResp.Status(), listResp.JSON401, listResp.JSON403)
-				}
-				if len(listResp.JSON200.Data) == 0 {
-					return fmt.Errorf("no installs found in this workspace")
-				}
-
-				options := make([]prompt.SelectOption, len(listResp.JSON200.Data))
-				for i, inst := range listResp.JSON200.Data {
-					label := inst.Id
-					if inst.Name != nil {
-						label = *inst.Name + " (" + inst.Id + ")"
-					}
-					options[i] = prompt.SelectOption{Label: label, Value: inst.Id}
-				}
-
-				installID, err = prompt.Select("Select an install", options)
+				installID, err = pickInstall(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
 			}
 
 			// Interactive pod picker if --pod not set
-			if pod == "" && prompt.IsInteractive() {
+			if pod == "" && !allPods && selector == "" && prompt.IsInteractive() {
 				podsResp, err := client.GetV1InstallsIdPodsWithResponse(cmd.Context(), installID)
 				if err != nil {
 					return fmt.Errorf("fetching pods: %w", err)
@@ -545,10 +1065,7 @@ the install ID argument is required.`,
 				if podsResp.JSON200 != nil && len(podsResp.JSON200.Data) > 0 {
 					podOpts := make([]prompt.SelectOption, len(podsResp.JSON200.Data))
 					for i, p := range podsResp.JSON200.Data {
-						podOpts[i] = prompt.SelectOption{
-							Label: p.Name + " [" + strings.Join(p.Containers, ", ") + "]",
-							Value: p.Name,
-						}
+						podOpts[i] = prompt.SelectOption{Label: podLabel(p), Value: p.Name}
 					}
 
 					pod, err = prompt.Select("Select a pod", podOpts)
@@ -559,11 +1076,16 @@ the install ID argument is required.`,
 					// Interactive container picker if pod has multiple containers
 					if container == "" {
 						for _, p := range podsResp.JSON200.Data {
+							if p.Name == pod && len(p.Containers) == 1 {
+								container = p.Containers[0]
+								break
+							}
 							if p.Name == pod && len(p.Containers) > 1 {
-								containerOpts := make([]prompt.SelectOption, len(p.Containers))
+								containerOpts := make([]prompt.SelectOption, len(p.Containers), len(p.Containers)+1)
 								for i, c := range p.Containers {
-									containerOpts[i] = prompt.SelectOption{Label: c, Value: c}
+									containerOpts[i] = prompt.SelectOption{Label: containerLabel(p, c), Value: c}
 								}
+								containerOpts = append(containerOpts, prompt.SelectOption{Label: "All containers", Value: allContainers})
 								container, err = prompt.Select("Select a container", containerOpts)
 								if err != nil {
 									return err
@@ -573,6 +1095,28 @@ the install ID argument is required.`,
 						}
 					}
 				}
+			} else if pod != "" && (container == "" || container == allContainers) {
+				containers, err := podContainers(cmd.Context(), client, installID, pod)
+				switch {
+				case err != nil && container == allContainers:
+					return err
+				case err != nil:
+					// Let the server stream every container, as before.
+					slog.Debug("looking up pod containers failed", "pod", pod, "error", err)
+				case len(containers) == 1:
+					container = containers[0]
+				case len(containers) > 1:
+					container = allContainers
+				}
+			}
+			if container == allContainers && pod == "" {
+				return fmt.Errorf("--container all needs a single pod; pass --pod")
+			}
+
+			// Like kubectl, --tail alone prints the last lines and exits;
+			// pass --follow explicitly to keep streaming.
+			if tail > 0 && !cmd.Flags().Changed("follow") {
+				follow = false
 			}
 
 			params := &api.GetV1InstallsIdLogsParams{
@@ -581,9 +1125,12 @@ the install ID argument is required.`,
 			if pod != "" {
 				params.Pod = &pod
 			}
-			if container != "" {
+			if container != "" && container != allContainers {
 				params.Container = &container
 			}
+			if selector != "" {
+				params.Selector = &selector
+			}
 			if tail > 0 {
 				params.Tail = &tail
 			}
@@ -594,53 +1141,474 @@ the install ID argument is required.`,
 			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt)
 			defer cancel()
 
-			// Use raw client to get streaming response
-			resp, err := client.GetV1InstallsIdLogs(ctx, installID, params)
-			if err != nil {
-				return fmt.Errorf("streaming logs: %w", err)
+			connectCtx := func(ctx context.Context, params *api.GetV1InstallsIdLogsParams) (io.ReadCloser, error) {
+				// Use raw client to get streaming response
+				resp, err := client.GetV1InstallsIdLogs(ctx, installID, params)
+				if err != nil {
+					return nil, fmt.Errorf("streaming logs: %w", err)
+				}
+				if resp.StatusCode != 200 {
+					defer func() { _ = resp.Body.Close() }()
+					switch resp.StatusCode {
+					case http.StatusNotFound:
+						return nil, fmt.Errorf("install %q %w", installID, errInstallNotFound)
+					case http.StatusGone:
+						return nil, errInstallGone
+					}
+					body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+					if resp.StatusCode == http.StatusBadRequest && selector != "" {
+						return nil, fmt.Errorf("--selector %q rejected: %w", selector, cmdutil.APIError(resp.Status, body))
+					}
+					return nil, cmdutil.APIError(resp.Status, body)
+				}
+				return resp.Body, nil
 			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != 200 {
-				return fmt.Errorf("unexpected response: %s", resp.Status)
+			connect := func(params *api.GetV1InstallsIdLogsParams) (io.ReadCloser, error) {
+				return connectCtx(ctx, params)
 			}
 
-			// Read SSE stream line by line
-			scanner := bufio.NewScanner(resp.Body)
-			for scanner.Scan() {
-				line := scanner.Text()
-				// SSE format: "data: <log line>"
-				if strings.HasPrefix(line, "data: ") {
-					fmt.Println(line[6:])
+			newStream := func(w io.Writer, container string) *logStream {
+				s := &logStream{w: w, maxLines: maxLines}
+				if format.IsJSON() {
+					s.output, s.pod, s.container = format, pod, container
+				} else if jsonParse {
+					s.format = formatJSONLog
 				}
+				return s
 			}
-
-			return scanner.Err()
+			if container != allContainers {
+				err = newStream(os.Stdout, container).run(ctx, params, follow, connect)
+			} else {
+				var containers []string
+				if containers, err = podContainers(ctx, client, installID, pod); err != nil {
+					return err
+				}
+				err = streamContainers(ctx, os.Stdout, containers, !format.IsJSON(), maxLines, func(ctx context.Context, w io.Writer, c string) error {
+					s := newStream(w, c)
+					s.maxLines = 0 // enforced across containers by streamContainers
+					containerParams := *params
+					containerParams.Container = &c
+					return s.run(ctx, &containerParams, follow, func(params *api.GetV1InstallsIdLogsParams) (io.ReadCloser, error) {
+						return connectCtx(ctx, params)
+					})
+				})
+			}
+			if errors.Is(err, errInstallGone) {
+				_, _ = fmt.Fprintf(os.Stderr, "Install %s was deleted; log stream ended.\n", installID)
+				return &cmdutil.ExitError{Code: exitInstallGone}
+			}
+			return err
 		},
 	}
 
 	cmd.Flags().StringVar(&pod, "pod", "", "Pod name (all pods if omitted)")
-	cmd.Flags().StringVar(&container, "container", "", "Container name")
-	cmd.Flags().BoolVarP(&follow, "follow", "f", true, "Follow log output")
+	cmd.Flags().StringVar(&container, "container", "", "Container name, or \"all\" to stream every container of --pod")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Stream the pods matching this label selector (e.g. app=web,tier!=cache)")
+	cmd.Flags().BoolVar(&allPods, "all-pods", false, "Stream every pod of the install, without the pod picker")
+	// Across the CLI -f names an input file (--values, --filename). -f for
+	// --follow stays for now, hidden and deprecated, so scripts keep working.
+	cmd.Flags().BoolVarP(&follow, "follow", "f", true, "Follow log output (defaults to false when --tail is set)")
+	_ = cmd.Flags().MarkShorthandDeprecated("follow", "use --follow instead; -f is reserved for input files")
+	cmd.Flags().BoolVar(&jsonParse, "json-parse", false, "Pretty-print JSON log lines as \"LEVEL time msg key=val\"")
 	cmd.Flags().IntVar(&tail, "tail", 0, "Number of lines to tail")
-	cmd.Flags().IntVar(&sinceSeconds, "since", 0, "Only return logs newer than this many seconds")
+	cmd.Flags().IntVar(&maxLines, "max-lines", 0, "Stop after printing this many lines (default no limit)")
+	cmd.Flags().StringVar(&since, "since", "", "Only return logs newer than a relative duration (e.g. 10m, 1h) or number of seconds")
+	cmd.Flags().StringVar(&sinceTime, "since-time", "", "Only return logs after this time (RFC3339, e.g. 2025-01-02T09:00:00Z)")
+	cmd.MarkFlagsMutuallyExclusive("since", "since-time")
+	cmd.MarkFlagsMutuallyExclusive("pod", "selector", "all-pods")
 
 	return cmd
 }
 
+// labelName matches a label name or value: up to 63 alphanumerics, '-',
+// '_' and '.', starting and ending with an alphanumeric.
+var labelName = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$`)
+
+// labelPrefix matches the optional DNS subdomain prefix of a label key.
+var labelPrefix = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?$`)
+
+// validateSelector checks an equality-based label selector, as kubectl
+// accepts it: comma-separated key=value, key==value, key!=value, key and
+// !key requirements. The server has the final say.
+func validateSelector(selector string) error {
+	for _, req := range strings.Split(selector, ",") {
+		req = strings.TrimSpace(req)
+		key, value, hasValue := req, "", false
+		for _, op := range []string{"!=", "==", "="} {
+			if k, v, ok := strings.Cut(req, op); ok {
+				key, value, hasValue = k, v, true
+				break
+			}
+		}
+		if !hasValue {
+			key = strings.TrimPrefix(key, "!")
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		if !validLabelKey(key) {
+			return fmt.Errorf("invalid --selector %q: %q is not a valid label key", selector, key)
+		}
+		if value != "" && !labelName.MatchString(value) {
+			return fmt.Errorf("invalid --selector %q: %q is not a valid label value", selector, value)
+		}
+	}
+	return nil
+}
+
+// validLabelKey reports whether key is a label key: a name, optionally
+// prefixed by a DNS subdomain and '/'.
+func validLabelKey(key string) bool {
+	prefix, name, ok := strings.Cut(key, "/")
+	if !ok {
+		return labelName.MatchString(key)
+	}
+	return labelPrefix.MatchString(prefix) && labelName.MatchString(name)
+}
+
+// parseSince converts --since or --since-time into whole seconds before now.
+// --since accepts a Go duration ("10m") or, for backward compatibility,
+// a bare number of seconds. Returns 0 when neither flag is set; --since 0
+// (or 0s) also means unset, as it did when the flag took seconds.
+func parseSince(since, sinceTime string, now time.Time) (int, error) {
+	var d time.Duration
+	switch {
+	case since != "":
+		if n, err := strconv.Atoi(since); err == nil {
+			d = time.Duration(n) * time.Second
+		} else if d, err = time.ParseDuration(since); err != nil {
+			return 0, fmt.Errorf("invalid --since %q: use a duration like 10m or a number of seconds", since)
+		}
+		if d == 0 {
+			return 0, nil
+		}
+	case sinceTime != "":
+		t, err := time.Parse(time.RFC3339, sinceTime)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --since-time %q: use RFC3339, e.g. 2025-01-02T09:00:00Z", sinceTime)
+		}
+		d = now.Sub(t)
+	default:
+		return 0, nil
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("--since and --since-time must be in the past")
+	}
+	// Round up so the requested moment is always included.
+	return int((d + time.Second - 1) / time.Second), nil
+}
+
+// logReconnects is how many times in a row logs --follow reconnects a
+// dropped stream before giving up.
+const logReconnects = 5
+
+// errStreamLost is returned by logStream.copy when the stream ends without
+// the server's close event, i.e. the connection dropped rather than the
+// logs ending.
+var errStreamLost = errors.New("connection lost")
+
+// logSeamLines is how many recent lines logStream remembers to drop the
+// ones a resumed stream sends again: since_seconds is rounded up to whole
+// seconds, so a resumed stream overlaps the end of the dropped one.
+const logSeamLines = 256
+
+// errInstallGone is returned when the install whose logs are streamed was
+// deleted: the server answered 410 Gone, sent a "gone" event, or answered
+// 404 to a reconnect.
+var errInstallGone = errors.New("install was deleted")
+
+// errInstallNotFound is wrapped by the logs connect error for a 404.
+var errInstallNotFound = errors.New("not found")
+
+// exitInstallGone is the exit status of logs when the install is deleted
+// while streaming, so scripts can tell it from other failures.
+const exitInstallGone = 4
+
+// errMaxLines is returned by logStream.copy once maxLines lines are printed.
+var errMaxLines = errors.New("line limit reached")
+
+// logLine is a log line as printed with -o json.
+type logLine struct {
+	Line      string `json:"line"`
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+}
+
+// logStream copies log lines from SSE streams to w, across reconnects.
+type logStream struct {
+	w         io.Writer
+	format    func(string) string // applied to each line if non-nil
+	output    output.Format       // if JSON, lines are encoded as logLine
+	pod       string              // pod and container for logLine
+	container string
+	last      time.Time // when the last line arrived
+	maxLines  int       // stop after this many lines; 0 means no limit
+	printed   int
+
+	// recent holds hashes of the last logSeamLines lines, as a ring
+	// buffer written at n%logSeamLines. While resuming is set, lines found
+	// in it are skipped; the first new line ends the seam.
+	recent   [logSeamLines]uint64
+	n        int
+	resuming bool
+}
+
+// run streams logs from connect until they end. When following, a stream
+// that drops is reconnected after a backoff, resuming from when the last
+// line arrived, up to logReconnects times in a row. Errors from the first
+// connection are returned as is.
+func (s *logStream) run(ctx context.Context, params *api.GetV1InstallsIdLogsParams, follow bool, connect func(*api.GetV1InstallsIdLogsParams) (io.ReadCloser, error)) error {
+	body, err := connect(params)
+	if err != nil {
+		return err
+	}
+	attempt, backoff := 0, reconnectInitialBackoff
+	for {
+		last := s.last
+		err = s.copy(body)
+		_ = body.Close()
+		switch {
+		case err == nil:
+			if follow {
+				_, _ = fmt.Fprintln(os.Stderr, "Log stream ended.")
+			}
+			return nil
+		case errors.Is(err, errInstallGone):
+			return err
+		case err == errMaxLines:
+			_, _ = fmt.Fprintf(os.Stderr, "Output truncated after %d lines (--max-lines).\n", s.maxLines)
+			return nil
+		case ctx.Err() != nil:
+			return err
+		case !follow && err == errStreamLost:
+			// Without follow, some servers end the backlog with a plain EOF.
+			return nil
+		case !follow:
+			return err
+		}
+
+		// A stream that delivered lines gets a fresh set of attempts.
+		if s.last != last {
+			attempt, backoff = 0, reconnectInitialBackoff
+		}
+		for {
+			if attempt++; attempt > logReconnects {
+				return err
+			}
+			_, _ = fmt.Fprintf(os.Stderr, "%s; reconnecting (%d/%d)...\n", err, attempt, logReconnects)
+
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			backoff = min(backoff*2, reconnectMaxBackoff)
+
+			body, err = connect(s.resumeParams(params))
+			if err == nil {
+				s.resuming = !s.last.IsZero()
+				break
+			}
+			// The install streamed before, so a 404 now means it was deleted.
+			if errors.Is(err, errInstallGone) || errors.Is(err, errInstallNotFound) {
+				return errInstallGone
+			}
+		}
+	}
+}
+
+// resumeParams returns params for reconnecting: the original ones until a
+// line has arrived, then the logs since that line, without --tail.
+func (s *logStream) resumeParams(params *api.GetV1InstallsIdLogsParams) *api.GetV1InstallsIdLogsParams {
+	if s.last.IsZero() {
+		return params
+	}
+	resume := *params
+	resume.Tail = nil
+	// Round up so the moment of the last line is always included.
+	since := int((time.Since(s.last) + time.Second - 1) / time.Second)
+	resume.SinceSeconds = &since
+	return &resume
+}
+
+// remember records line in the ring of recent lines and reports whether it
+// should be printed: false for a line repeated at the seam of a resumed
+// stream.
+func (s *logStream) remember(line string) bool {
+	h := fnv.New64a()
+	_, _ = io.WriteString(h, line)
+	sum := h.Sum64()
+
+	if s.resuming {
+		for i := range min(s.n, logSeamLines) {
+			if s.recent[i] == sum {
+				return false
+			}
+		}
+		s.resuming = false
+	}
+	s.recent[s.n%logSeamLines] = sum
+	s.n++
+	return true
+}
+
+// copy copies log lines from one SSE stream to s.w. It returns nil when
+// the server sends a close event (end of backlog with follow disabled, or
+// the pods went away), errInstallGone on a gone event, errMaxLines once
+// s.maxLines lines are printed, and errStreamLost when the stream ends
+// otherwise.
+func (s *logStream) copy(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data: "):
+			// SSE format: "data: <log line>"
+			line = line[6:]
+			if !s.remember(line) {
+				continue
+			}
+			s.last = time.Now()
+			if s.output.IsJSON() {
+				_ = output.EncodeData(s.w, s.output, logLine{Line: line, Pod: s.pod, Container: s.container})
+			} else {
+				if s.format != nil {
+					line = s.format(line)
+				}
+				_, _ = fmt.Fprintln(s.w, line)
+			}
+			if s.printed++; s.maxLines > 0 && s.printed >= s.maxLines {
+				return errMaxLines
+			}
+		case line == "event: close":
+			return nil
+		case line == "event: gone":
+			return errInstallGone
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: %w", errStreamLost, err)
+	}
+	return errStreamLost
+}
+
+// allContainers is the --container value that streams every container of
+// the pod.
+const allContainers = "all"
+
+// podContainers returns the containers of the install's pod.
+func podContainers(ctx context.Context, client *api.ClientWithResponses, installID, pod string) ([]string, error) {
+	resp, err := client.GetV1InstallsIdPodsWithResponse(ctx, installID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching pods: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+	}
+	for _, p := range resp.JSON200.Data {
+		if p.Name == pod {
+			return p.Containers, nil
+		}
+	}
+	return nil, fmt.Errorf("pod %q not found in install %s", pod, installID)
+}
+
+// streamContainers runs stream for each container concurrently, with each
+// line written whole so containers interleave cleanly. If prefix is set,
+// lines are prefixed with "[container] ". maxLines, if set, counts lines
+// across all containers. Reaching it, a failing stream, or ctx ending stops
+// every stream; the first failure is returned.
+func streamContainers(ctx context.Context, w io.Writer, containers []string, prefix bool, maxLines int, stream func(ctx context.Context, w io.Writer, container string) error) error {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	mux := &logMux{w: w, maxLines: maxLines, cancel: cancel}
+	var mu sync.Mutex
+	var firstErr error
+	cmdutil.MapConcurrent(streamCtx, containers, len(containers), func(streamCtx context.Context, c string) (struct{}, error) {
+		mw := &muxWriter{mux: mux}
+		if prefix {
+			mw.prefix = "[" + c + "] "
+		}
+		err := stream(streamCtx, mw, c)
+		mu.Lock()
+		defer mu.Unlock()
+		// Errors after the streams were stopped are consequences, not causes.
+		if err != nil && streamCtx.Err() == nil && firstErr == nil {
+			firstErr = fmt.Errorf("container %s: %w", c, err)
+			cancel()
+		}
+		return struct{}{}, nil
+	})
+
+	switch {
+	case firstErr != nil:
+		return firstErr
+	case mux.truncated():
+		_, _ = fmt.Fprintf(os.Stderr, "Output truncated after %d lines (--max-lines).\n", maxLines)
+		return nil
+	}
+	return ctx.Err()
+}
+
+// logMux serializes lines from concurrent log streams onto w.
+type logMux struct {
+	mu       sync.Mutex
+	w        io.Writer
+	maxLines int
+	printed  int
+	cancel   context.CancelFunc
+}
+
+func (m *logMux) truncated() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.maxLines > 0 && m.printed >= m.maxLines
+}
+
+// muxWriter writes one container's lines to a logMux, relying on logStream
+// writing each line with a single Write call.
+type muxWriter struct {
+	mux    *logMux
+	prefix string
+}
+
+func (w *muxWriter) Write(p []byte) (int, error) {
+	m := w.mux
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.maxLines > 0 && m.printed >= m.maxLines {
+		return len(p), nil
+	}
+	if w.prefix != "" {
+		_, _ = io.WriteString(m.w, w.prefix)
+	}
+	if _, err := m.w.Write(p); err != nil {
+		return 0, err
+	}
+	if m.printed++; m.maxLines > 0 && m.printed >= m.maxLines {
+		m.cancel()
+	}
+	return len(p), nil
+}
+
 // pickInstall shows an interactive install picker. Returns the selected install ID.
-func pickInstall(ctx context.Context, client *api.ClientWithResponses) (string, error) {
+// It lists the active workspace, so it requires one.
+func pickInstall(ctx context.Context, client *api.ClientWithResponses, cfg *config.Config) (string, error) {
+	if err := cmdutil.RequireWorkspace(cfg); err != nil {
+		return "", err
+	}
 	limit := 100
 	listResp, err := client.GetV1InstallsWithResponse(ctx, &api.GetV1InstallsParams{Limit: &limit})
 	if err != nil {
 		return "", fmt.Errorf("fetching installs: %w", err)
 	}
 	if listResp.JSON200 == nil {
-		return "", apiError(listResp.Status(), listResp.JSON401, listResp.JSON403)
+		return "", cmdutil.APIError(listResp.Status(), listResp.Body, listResp.JSON401, listResp.JSON403)
 	}
 	if len(listResp.JSON200.Data) == 0 {
 		return "", fmt.Errorf("no installs found in this workspace")
 	}
+	cacheInstallNames(listResp.JSON200.Data...)
 	options := make([]prompt.SelectOption, len(listResp.JSON200.Data))
 	for i, inst := range listResp.JSON200.Data {
 		label := inst.Id
@@ -649,20 +1617,35 @@ func pickInstall(ctx context.Context, client *api.ClientWithResponses) (string,
 		}
 		options[i] = prompt.SelectOption{Label: label, Value: inst.Id}
 	}
-	return prompt.Select("Select an install", options)
+	return prompt.Select("Select an install", options, prompt.WithCancel())
 }
 
-func apiError(status string, errs ...*api.Error) error {
-	for _, e := range errs {
-		if e != nil {
-			parts := []string{e.Error.Message}
-			if e.Error.Suggestion != nil {
-				parts = append(parts, *e.Error.Suggestion)
-			}
-			return fmt.Errorf("%s", strings.Join(parts, ". "))
+// cacheInstallNames records install names for later display without a lookup.
+// Unnamed installs are skipped.
+func cacheInstallNames(installs ...api.Install) {
+	names := make(map[string]string, len(installs))
+	for _, i := range installs {
+		if i.Name != nil {
+			names[i.Id] = *i.Name
 		}
 	}
-	return fmt.Errorf("unexpected response: %s", status)
+	namecache.Store(namecache.Installs, names)
+}
+
+// displayName renders "name (id)" when the install name is cached, else just the ID.
+func displayName(installID string) string {
+	if name, ok := namecache.Lookup(namecache.Installs, installID); ok {
+		return name + " (" + installID + ")"
+	}
+	return installID
+}
+
+// optional returns nil for an empty flag value, so it's left out of the query.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
 }
 
 func deref(s *string) string {
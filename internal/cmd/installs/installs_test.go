@@ -0,0 +1,1642 @@
+package installs
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdtest"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/config"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/wsterm"
+	"github.com/coder/websocket"
+	"github.com/spf13/cobra"
+)
+
+func TestNotFound(t *testing.T) {
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.WriteError(w, http.StatusNotFound, "Install not found")
+	}))
+
+	tests := []struct {
+		name string
+		cmd  func() *cobra.Command
+		args []string
+		want string
+	}{
+		{"get", newCmdGet, []string{"missing"}, "Install not found"},
+		{"describe", newCmdDescribe, []string{"missing"}, "Install not found"},
+		{"delete", newCmdDelete, []string{"missing", "--yes"}, "Install not found"},
+		{"pods", newCmdPods, []string{"missing"}, "Install not found"},
+		{"events", newCmdEvents, []string{"missing"}, "Install not found"},
+		{"logs", newCmdLogs, []string{"missing", "--pod", "web-0"}, `install "missing" not found`},
+		{"values-schema", newCmdValuesSchema, []string{"missing"}, "Install not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cmdtest.Run(tt.cmd(), tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamLogsStopsOnClose(t *testing.T) {
+	stream := "data: line 1\n\ndata: line 2\n\nevent: close\ndata: \n\ndata: after close\n\n"
+
+	var out strings.Builder
+	s := &logStream{w: &out}
+	if err := s.copy(strings.NewReader(stream)); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if got, want := out.String(), "line 1\nline 2\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	if err := s.copy(strings.NewReader("data: line 3\n\n")); !errors.Is(err, errStreamLost) {
+		t.Errorf("copy without close event = %v, want errStreamLost", err)
+	}
+}
+
+func TestLogStreamSkipsSeam(t *testing.T) {
+	var out strings.Builder
+	s := &logStream{w: &out}
+	if err := s.copy(strings.NewReader("data: a\n\ndata: b\n\ndata: c\n\n")); !errors.Is(err, errStreamLost) {
+		t.Fatalf("copy = %v, want errStreamLost", err)
+	}
+
+	// The resumed stream repeats b and c; once d is new, a later c is
+	// printed again.
+	s.resuming = true
+	if err := s.copy(strings.NewReader("data: b\n\ndata: c\n\ndata: d\n\ndata: c\n\nevent: close\n")); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if got, want := out.String(), "a\nb\nc\nd\nc\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestLogsFollowReconnects(t *testing.T) {
+	reconnectInitialBackoff = time.Millisecond
+	t.Cleanup(func() { reconnectInitialBackoff = time.Second })
+
+	var queries []url.Values
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "text/event-stream")
+		switch len(queries) {
+		case 1:
+			// Drop mid-stream: the response ends without a close event.
+			_, _ = io.WriteString(w, "data: line 1\n\n")
+		case 2:
+			// The reconnect itself fails once.
+			cmdtest.WriteError(w, http.StatusBadGateway, "upstream unavailable")
+		default:
+			_, _ = io.WriteString(w, "data: line 2\n\nevent: close\ndata: \n\n")
+		}
+	}))
+
+	if err := cmdtest.Run(newCmdLogs(), "inst_1", "--pod", "web-0", "--container", "app", "--tail", "50", "--follow"); err != nil {
+		t.Fatalf("logs: %v", err)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("requests = %d, want 3", len(queries))
+	}
+	if q := queries[0]; q.Get("tail") != "50" || q.Has("since_seconds") {
+		t.Errorf("first request = %v, want tail=50 and no since_seconds", q)
+	}
+	if q := queries[2]; q.Has("tail") || q.Get("since_seconds") != "1" || q.Get("follow") != "true" {
+		t.Errorf("resumed request = %v, want since_seconds=1, follow and no tail", q)
+	}
+}
+
+func TestLogsMaxLines(t *testing.T) {
+	release := make(chan struct{})
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: line 1\n\ndata: line 2\n\ndata: line 3\n\n")
+		w.(http.Flusher).Flush()
+		// Keep the stream open, like a noisy install being followed.
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	t.Cleanup(func() { close(release) })
+
+	if err := cmdtest.Run(newCmdLogs(), "inst_1", "--pod", "web-0", "--container", "app", "--max-lines", "2"); err != nil {
+		t.Fatalf("logs: %v", err)
+	}
+
+	var out strings.Builder
+	s := &logStream{w: &out, maxLines: 2}
+	if err := s.copy(strings.NewReader("data: a\n\ndata: b\n\ndata: c\n\n")); err != errMaxLines {
+		t.Errorf("copy = %v, want errMaxLines", err)
+	}
+	if got := out.String(); got != "a\nb\n" {
+		t.Errorf("output = %q, want 2 lines", got)
+	}
+}
+
+func TestLogStreamJSON(t *testing.T) {
+	var out strings.Builder
+	s := &logStream{w: &out, output: output.FormatJSON, pod: "web-0"}
+	if err := s.copy(strings.NewReader("data: started\n\ndata: {\"level\":\"info\"}\n\nevent: close\n")); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	want := `{"line":"started","pod":"web-0"}` + "\n" + `{"line":"{\"level\":\"info\"}","pod":"web-0"}` + "\n"
+	if got := out.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestLogsFollowGivesUp(t *testing.T) {
+	reconnectInitialBackoff = time.Millisecond
+	t.Cleanup(func() { reconnectInitialBackoff = time.Second })
+
+	requests := 0
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/event-stream")
+	}))
+
+	err := cmdtest.Run(newCmdLogs(), "inst_1", "--pod", "web-0", "--container", "app")
+	if !errors.Is(err, errStreamLost) {
+		t.Errorf("err = %v, want errStreamLost", err)
+	}
+	if requests != 1+logReconnects {
+		t.Errorf("requests = %d, want %d", requests, 1+logReconnects)
+	}
+}
+
+func TestLogsFollowInstallDeleted(t *testing.T) {
+	reconnectInitialBackoff = time.Millisecond
+	t.Cleanup(func() { reconnectInitialBackoff = time.Second })
+
+	requests := 0
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			cmdtest.WriteError(w, http.StatusGone, "install deleted")
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: line 1\n\n")
+	}))
+
+	err := cmdtest.Run(newCmdLogs(), "inst_1", "--pod", "web-0", "--container", "app", "--follow")
+	var exit *cmdutil.ExitError
+	if !errors.As(err, &exit) || exit.Code != exitInstallGone {
+		t.Fatalf("err = %v, want exit status %d", err, exitInstallGone)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2 (no retries after 410)", requests)
+	}
+
+	s := &logStream{w: io.Discard}
+	if err := s.copy(strings.NewReader("data: a\n\nevent: gone\ndata: \n\n")); err != errInstallGone {
+		t.Errorf("copy = %v, want errInstallGone", err)
+	}
+}
+
+func TestLogsDefaultsSoleContainer(t *testing.T) {
+	tests := []struct {
+		pod       string
+		container string
+		want      []string
+		wantErr   string
+	}{
+		{"web-0", "", []string{"app"}, ""},
+		{"db-0", "", []string{"exporter", "postgres"}, ""}, // several containers: stream each
+		{"db-0", "all", []string{"exporter", "postgres"}, ""},
+		{"web-0", "all", []string{"app"}, ""},
+		{"gone", "", []string{""}, ""},
+		{"gone", "all", nil, `pod "gone" not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pod+"/"+tt.container, func(t *testing.T) {
+			var mu sync.Mutex
+			var containers []string
+			cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/v1/installs/inst_1/pods" {
+					w.Header().Set("Content-Type", "application/json")
+					_ = json.NewEncoder(w).Encode(map[string]any{
+						"data": []map[string]any{
+							{"name": "web-0", "containers": []string{"app"}},
+							{"name": "db-0", "containers": []string{"postgres", "exporter"}},
+						},
+					})
+					return
+				}
+				mu.Lock()
+				containers = append(containers, r.URL.Query().Get("container"))
+				mu.Unlock()
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = io.WriteString(w, "event: close\ndata: \n\n")
+			}))
+
+			args := []string{"inst_1", "--pod", tt.pod, "--tail", "1"}
+			if tt.container != "" {
+				args = append(args, "--container", tt.container)
+			}
+			err := cmdtest.Run(newCmdLogs(), args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("logs: %v", err)
+			}
+			sort.Strings(containers)
+			if !slices.Equal(containers, tt.want) {
+				t.Errorf("containers = %q, want %q", containers, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamContainers(t *testing.T) {
+	var out strings.Builder
+	err := streamContainers(context.Background(), &out, []string{"app", "proxy"}, true, 3, func(ctx context.Context, w io.Writer, c string) error {
+		for i := 0; ; i++ {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+			_, _ = fmt.Fprintf(w, "%s line %d\n", c, i)
+		}
+	})
+	if err != nil {
+		t.Fatalf("streamContainers: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3 (--max-lines across containers): %q", len(lines), out.String())
+	}
+	for _, l := range lines {
+		if !strings.HasPrefix(l, "[app] app line") && !strings.HasPrefix(l, "[proxy] proxy line") {
+			t.Errorf("line %q lacks its container prefix", l)
+		}
+	}
+
+	boom := errors.New("boom")
+	err = streamContainers(context.Background(), io.Discard, []string{"app", "proxy"}, true, 0, func(ctx context.Context, w io.Writer, c string) error {
+		if c == "proxy" {
+			return boom
+		}
+		<-ctx.Done() // torn down by the failing sibling
+		return ctx.Err()
+	})
+	if !errors.Is(err, boom) || !strings.Contains(err.Error(), "container proxy") {
+		t.Errorf("err = %v, want proxy's error", err)
+	}
+}
+
+func TestLogsTailDisablesFollow(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"default", []string{"inst_1"}, "true"},
+		{"tail", []string{"inst_1", "--tail", "10"}, "false"},
+		{"tail with follow", []string{"inst_1", "--tail", "10", "--follow"}, "true"},
+		{"tail with deprecated -f", []string{"inst_1", "--tail", "10", "-f"}, "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var follow string
+			cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				follow = r.URL.Query().Get("follow")
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = io.WriteString(w, "event: close\ndata: \n\n")
+			}))
+
+			if err := cmdtest.Run(newCmdLogs(), append(tt.args, "--pod", "web-0", "--container", "app")...); err != nil {
+				t.Fatalf("logs: %v", err)
+			}
+			if follow != tt.want {
+				t.Errorf("follow = %q, want %q", follow, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSelector(t *testing.T) {
+	valid := []string{"app=web", "app==web", "tier!=cache", "app=web,tier!=cache", "app", "!canary", "app.kubernetes.io/name=web", "app="}
+	for _, s := range valid {
+		if err := validateSelector(s); err != nil {
+			t.Errorf("validateSelector(%q): %v", s, err)
+		}
+	}
+	invalid := []string{"", "=web", "app=we b", "app=web,", "-app=web", "Example.com/app=web", "env in (a,b)"}
+	for _, s := range invalid {
+		if err := validateSelector(s); err == nil {
+			t.Errorf("validateSelector(%q): want error", s)
+		}
+	}
+}
+
+func TestLogsSelector(t *testing.T) {
+	var selector string
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		selector = r.URL.Query().Get("selector")
+		if selector == "unknown=x" {
+			cmdtest.WriteError(w, http.StatusBadRequest, "unsupported selector")
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "event: close\ndata: \n\n")
+	}))
+
+	if err := cmdtest.Run(newCmdLogs(), "inst_1", "-l", "app=web", "--tail", "1"); err != nil {
+		t.Fatalf("logs: %v", err)
+	}
+	if selector != "app=web" {
+		t.Errorf("selector = %q, want app=web", selector)
+	}
+
+	err := cmdtest.Run(newCmdLogs(), "inst_1", "-l", "unknown=x", "--tail", "1")
+	if err == nil || !strings.Contains(err.Error(), "rejected") || !strings.Contains(err.Error(), "unsupported selector") {
+		t.Errorf("rejected selector: got error %v", err)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		since     string
+		sinceTime string
+		want      int
+		wantErr   bool
+	}{
+		{"unset", "", "", 0, false},
+		{"zero is unset", "0", "", 0, false},
+		{"zero duration is unset", "0s", "", 0, false},
+		{"seconds", "90", "", 90, false},
+		{"duration", "10m", "", 600, false},
+		{"fractional duration rounds up", "1.5s", "", 2, false},
+		{"since-time", "", "2025-01-02T09:00:00Z", 3 * 3600, false},
+		{"since-time with offset", "", "2025-01-02T10:00:00+01:00", 3 * 3600, false},
+		{"invalid duration", "ten minutes", "", 0, true},
+		{"invalid since-time", "", "9am", 0, true},
+		{"future since-time", "", "2025-01-02T13:00:00Z", 0, true},
+		{"negative", "-5m", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSince(tt.since, tt.sinceTime, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogsSinceFlagsConflict(t *testing.T) {
+	err := cmdtest.Run(newCmdLogs(), "inst_1", "--since", "10m", "--since-time", "2025-01-02T09:00:00Z")
+	if err == nil || !strings.Contains(err.Error(), "since") {
+		t.Errorf("got error %v, want mutually exclusive flags error", err)
+	}
+}
+
+// exec lives only in exec.go; make sure the installs command wires up
+// exactly one copy of it.
+func TestSingleExecCommand(t *testing.T) {
+	var n int
+	for _, c := range NewCmdInstalls().Commands() {
+		if c.Name() == "exec" {
+			n++
+			if c.Flags().Lookup("reconnect") == nil {
+				t.Errorf("exec command is missing --reconnect; is a stale implementation registered?")
+			}
+		}
+	}
+	if n != 1 {
+		t.Errorf("found %d exec commands, want 1", n)
+	}
+}
+
+func TestPodLabel(t *testing.T) {
+	crash := "CrashLoopBackOff"
+	pull := "ImagePullBackOff"
+	pod := api.Pod{
+		Name:       "web-0",
+		Containers: []string{"app", "sidecar", "init", "proxy"},
+		ContainerStatuses: &[]api.ContainerStatus{
+			{Name: "app", Ready: true, State: api.Running},
+			{Name: "sidecar", State: api.Waiting, Reason: &crash},
+			{Name: "init", State: api.Waiting, Reason: &pull},
+			{Name: "proxy", State: api.Running},
+		},
+	}
+
+	want := "web-0 [app(ready), sidecar(crashloop), init(imagepullbackoff), proxy(not ready)]"
+	if got := podLabel(pod); got != want {
+		t.Errorf("podLabel = %q, want %q", got, want)
+	}
+
+	pod.ContainerStatuses = nil
+	if got, want := podLabel(pod), "web-0 [app, sidecar, init, proxy]"; got != want {
+		t.Errorf("without statuses: podLabel = %q, want %q", got, want)
+	}
+}
+
+func TestFormatJSONLog(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"plain text", "starting server", "starting server"},
+		{"json array", `[1,2]`, `[1,2]`},
+		{"structured", `{"level":"info","ts":"2025-01-02T09:00:00Z","msg":"listening","port":8080,"tls":false}`, "INFO  2025-01-02T09:00:00Z listening port=8080 tls=false"},
+		{"alternate keys", `{"severity":"error","message":"db down","err":"dial tcp: timeout"}`, `ERROR db down err="dial tcp: timeout"`},
+		{"nested", `{"msg":"req","http":{"status":500}}`, `req http={"status":500}`},
+		{"large numbers", `{"ts":1735808400123456789,"msg":"req","id":9007199254740993,"ratio":0.1,"span":{"id":18446744073709551615}}`, `1735808400123456789 req id=9007199254740993 ratio=0.1 span={"id":18446744073709551615}`},
+		{"trailing data", `{"msg":"a"} {"msg":"b"}`, `{"msg":"a"} {"msg":"b"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatJSONLog(tt.line); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateValuesFromDir(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("src_web.yaml", "replicas: 2\n")
+	write("src_db.yml", "storage: 10Gi\n")
+	write("README.md", "not values\n")
+
+	var patched struct {
+		Updates []struct {
+			TemplateHelmSourceID string         `json:"template_helm_source_id"`
+			Values               map[string]any `json:"values"`
+		} `json:"updates"`
+	}
+	serve := func(sources ...string) {
+		cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.URL.Path == "/v1/installs/inst_1" && r.Method == http.MethodGet:
+				_ = json.NewEncoder(w).Encode(map[string]any{"id": "inst_1", "template_id": "tpl_1"})
+			case r.URL.Path == "/v1/templates/tpl_1":
+				var hs []map[string]any
+				for _, id := range sources {
+					hs = append(hs, map[string]any{"id": id, "chart": map[string]any{"repo_url": "https://charts.example.com", "target_revision": "1.0.0"}})
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{"id": "tpl_1", "helm_sources": hs})
+			case r.URL.Path == "/v1/installs/inst_1/values":
+				_ = json.NewDecoder(r.Body).Decode(&patched)
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = io.WriteString(w, `{}`)
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			}
+		}))
+	}
+
+	t.Run("maps files to sources", func(t *testing.T) {
+		serve("src_web", "src_db")
+		if err := cmdtest.Run(newCmdUpdateValues(), "inst_1", "--from-values-dir", dir); err != nil {
+			t.Fatalf("update-values: %v", err)
+		}
+		got := map[string]any{}
+		for _, u := range patched.Updates {
+			got[u.TemplateHelmSourceID] = u.Values
+		}
+		want := map[string]any{
+			"src_db":  map[string]any{"storage": "10Gi"},
+			"src_web": map[string]any{"replicas": float64(2)},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("updates = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		serve("src_web")
+		err := cmdtest.Run(newCmdUpdateValues(), "inst_1", "--from-values-dir", dir)
+		if err == nil || !strings.Contains(err.Error(), `unknown helm source "src_db"`) {
+			t.Errorf("got error %v, want unknown source", err)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		patched.Updates = nil
+		serve("src_web", "src_db")
+		if err := cmdtest.Run(newCmdUpdateValues(), "inst_1", "--from-values-dir", dir, "--dry-run"); err != nil {
+			t.Fatalf("update-values --dry-run: %v", err)
+		}
+		if patched.Updates != nil {
+			t.Error("--dry-run sent an update")
+		}
+	})
+}
+
+// -f is reserved for input files across the CLI; logs keeps it for
+// --follow only as a deprecated alias.
+func TestShortFlagF(t *testing.T) {
+	for _, sub := range NewCmdInstalls().Commands() {
+		if f := sub.Flags().ShorthandLookup("f"); f != nil && f.Name != "values" && f.ShorthandDeprecated == "" {
+			t.Errorf("installs %s: -f is --%s, want it reserved for --values", sub.Name(), f.Name)
+		}
+	}
+}
+
+func TestParseCpRemote(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want *cpRemote // nil for local paths and errors
+	}{
+		{"inst_1:web-0/etc/app.conf", &cpRemote{"inst_1", "web-0", "/etc/app.conf"}},
+		{"inst_1:/etc/app.conf", &cpRemote{"inst_1", "", "/etc/app.conf"}},
+		{"inst_1:web-0/a/../b/", &cpRemote{"inst_1", "web-0", "/b"}},
+		{"./local.txt", nil},
+		{"dir/inst_1:x", nil},
+		{`C:\Users\me`, nil},
+		{"inst_1:web-0", nil},
+		{"inst_1:web-0/", nil},
+		{"inst_1:/", nil},
+	}
+	for _, tt := range tests {
+		got, err := parseCpRemote(tt.arg)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("parseCpRemote(%q) = %+v, want error", tt.arg, got)
+			}
+			continue
+		}
+		if err != nil || *got != *tt.want {
+			t.Errorf("parseCpRemote(%q) = %+v, %v; want %+v", tt.arg, got, err, tt.want)
+		}
+	}
+}
+
+func TestCpOutput(t *testing.T) {
+	// What a TTY returns: the echoed script line, then \r\n line endings.
+	echo := downloadScript("/x") + "\r\n"
+	out := func(payload string, status int) string {
+		return echo + cpBeginMarker + "\r\n" + payload + "\r\n" + cpEndMarker + " " + strconv.Itoa(status) + "\r\n"
+	}
+	// parse feeds s in 3-byte writes, so markers arrive split.
+	parse := func(s string) (string, error) {
+		var payload bytes.Buffer
+		o := &cpOutput{payload: &payload}
+		for len(s) > 0 {
+			n := min(3, len(s))
+			_, _ = o.Write([]byte(s[:n]))
+			s = s[n:]
+		}
+		return payload.String(), o.result()
+	}
+
+	got, err := parse(out("aGVs\r\nbG8=", 0))
+	if err != nil || got != "aGVsbG8=" {
+		t.Errorf("payload = %q, %v", got, err)
+	}
+	long := strings.Repeat("A", 3*maxCpLine)
+	if got, err := parse(out(long, 0)); err != nil || got != long {
+		t.Errorf("long line: payload of %d bytes, %v; want %d", len(got), err, len(long))
+	}
+	if _, err := parse(out("", cpStatusNoTools)); err == nil || !strings.Contains(err.Error(), "tar and base64 are required") {
+		t.Errorf("no tools: err = %v", err)
+	}
+	if _, err := parse(out("", cpStatusNotFound)); err == nil || !strings.Contains(err.Error(), "no such file") {
+		t.Errorf("not found: err = %v", err)
+	}
+	if _, err := parse(echo); err == nil {
+		t.Error("echo only: want error")
+	}
+	if _, err := parse(echo + cpBeginMarker + "\r\naGVs\r\n"); err == nil || !strings.Contains(err.Error(), "ended early") {
+		t.Errorf("no end marker: err = %v", err)
+	}
+}
+
+// TestCpStreams copies a directory to a fake container and back over the
+// exec WebSocket, with output split across frames.
+func TestCpStreams(t *testing.T) {
+	var stored []byte // the archive the "container" holds
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		ctx := r.Context()
+		output := func(s string) {
+			// Small frames, with a TTY's \r\n line endings.
+			s = strings.ReplaceAll(s, "\n", "\r\n")
+			for len(s) > 0 {
+				n := min(100, len(s))
+				data, _ := json.Marshal(wsterm.Message{Type: "output", Data: s[:n]})
+				_ = conn.Write(ctx, websocket.MessageText, data)
+				s = s[n:]
+			}
+		}
+		finish := func() {
+			output("\n" + cpEndMarker + " 0\n")
+			code := 0
+			data, _ := json.Marshal(wsterm.Message{Type: "exit", Code: &code})
+			_ = conn.Write(ctx, websocket.MessageText, data)
+		}
+
+		var input strings.Builder
+		for {
+			_, data, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+			var msg wsterm.Message
+			_ = json.Unmarshal(data, &msg)
+			input.WriteString(msg.Data)
+			in := input.String()
+			switch {
+			case strings.Contains(in, "tar cf -"):
+				output(cpBeginMarker + "\n")
+				encoded := base64.StdEncoding.EncodeToString(stored)
+				for len(encoded) > 76 {
+					output(encoded[:76] + "\n")
+					encoded = encoded[76:]
+				}
+				output(encoded)
+				finish()
+				return
+			case strings.HasSuffix(in, "\x04"):
+				_, payload, _ := strings.Cut(in, "exit\n")
+				lines := strings.Split(strings.TrimSuffix(payload, "\x04"), "\n")
+				for _, l := range lines {
+					if len(l) > 76 {
+						t.Errorf("input line of %d bytes; the TTY caps line length", len(l))
+					}
+				}
+				stored, err = base64.StdEncoding.DecodeString(strings.Join(lines, ""))
+				if err != nil {
+					t.Errorf("decoding upload: %v", err)
+				}
+				output(cpBeginMarker + "\n")
+				finish()
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	src := filepath.Join(t.TempDir(), "conf")
+	if err := os.MkdirAll(src, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := bytes.Repeat([]byte("0123456789"), 10_000)
+	if err := os.WriteFile(filepath.Join(src, "big.bin"), content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{AuthURL: srv.URL}
+	remote := &cpRemote{installID: "inst_1", pod: "web-0", path: "/app/conf"}
+	ctx := context.Background()
+	if err := copyToPod(ctx, cfg, src, remote, "app"); err != nil {
+		t.Fatalf("copyToPod: %v", err)
+	}
+	dest := filepath.Join(t.TempDir(), "out")
+	if err := copyFromPod(ctx, cfg, remote, "app", dest); err != nil {
+		t.Fatalf("copyFromPod: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dest, "big.bin"))
+	if err != nil || !bytes.Equal(got, content) {
+		t.Errorf("copied back %d bytes, %v; want %d", len(got), err, len(content))
+	}
+}
+
+// TestUploadScript runs the upload script in a local shell, as the
+// container's would.
+func TestUploadScript(t *testing.T) {
+	for _, tool := range []string{"sh", "tar", "base64", "mktemp"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not available", tool)
+		}
+	}
+
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "app.conf"), []byte("listen 80;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(src, "config"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "config", "a.yaml"), []byte("a: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	upload := func(local, dest string) {
+		t.Helper()
+		name := filepath.Base(local)
+		var encoded bytes.Buffer
+		lines := &lineWrapper{w: &encoded, width: 76}
+		enc := base64.NewEncoder(base64.StdEncoding, lines)
+		if _, err := writeTar(enc, local, name); err != nil {
+			t.Fatal(err)
+		}
+		_ = enc.Close()
+		_ = lines.Close()
+
+		// The script is passed as an argument: a shell reading it from a
+		// pipe (unlike a TTY) may read ahead into the archive.
+		sh := exec.Command("sh", "-c", uploadScript(dest, name))
+		sh.Stdin = &encoded
+		out, err := sh.CombinedOutput()
+		if err != nil || !strings.Contains(string(out), cpEndMarker+" 0") {
+			t.Fatalf("upload %s to %s: %v\n%s", name, dest, err, out)
+		}
+	}
+	content := func(p string) string {
+		t.Helper()
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	remote := t.TempDir()
+	nginx := filepath.Join(remote, "etc", "nginx")
+	if err := os.MkdirAll(nginx, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A file into an existing directory lands inside it.
+	upload(filepath.Join(src, "app.conf"), nginx)
+	if got := content(filepath.Join(nginx, "app.conf")); got != "listen 80;\n" {
+		t.Errorf("etc/nginx/app.conf = %q", got)
+	}
+	// A file to a new path takes that name.
+	upload(filepath.Join(src, "app.conf"), filepath.Join(nginx, "default.conf"))
+	if got := content(filepath.Join(nginx, "default.conf")); got != "listen 80;\n" {
+		t.Errorf("etc/nginx/default.conf = %q", got)
+	}
+
+	// A directory to a new path becomes it; into an existing one, it's
+	// nested like cp -r.
+	app := filepath.Join(remote, "app", "config")
+	upload(filepath.Join(src, "config"), app)
+	if got := content(filepath.Join(app, "a.yaml")); got != "a: 1\n" {
+		t.Errorf("app/config/a.yaml = %q", got)
+	}
+	upload(filepath.Join(src, "config"), app)
+	if got := content(filepath.Join(app, "config", "a.yaml")); got != "a: 1\n" {
+		t.Errorf("app/config/config/a.yaml = %q", got)
+	}
+
+	// No staging directories are left behind.
+	if leftover, _ := filepath.Glob(filepath.Join(remote, "*", "*", ".cnap-cp.*")); len(leftover) > 0 {
+		t.Errorf("staging directories left: %v", leftover)
+	}
+}
+
+func TestLineWrapper(t *testing.T) {
+	var out bytes.Buffer
+	w := &lineWrapper{w: &out, width: 4}
+	for _, s := range []string{"ab", "cdefg", "hij"} {
+		_, _ = w.Write([]byte(s))
+	}
+	_ = w.Close()
+	if got := out.String(); got != "abcd\nefgh\nij\n" {
+		t.Errorf("wrapped = %q", got)
+	}
+}
+
+func TestTarRoundTrip(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "conf")
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "run.sh"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "app.yaml"), []byte("a: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// A read-only directory still gets its contents extracted.
+	if err := os.Chmod(filepath.Join(src, "sub"), 0o555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(filepath.Join(src, "sub"), 0o755) })
+
+	var archive bytes.Buffer
+	n, err := writeTar(&archive, src, "renamed")
+	if err != nil || n != 2 {
+		t.Fatalf("writeTar = %d, %v; want 2 files", n, err)
+	}
+	dest := filepath.Join(t.TempDir(), "out")
+	if n, err := extractTar(&archive, "renamed", dest); err != nil || n != 2 {
+		t.Fatalf("extractTar = %d, %v; want 2 files", n, err)
+	}
+
+	t.Cleanup(func() { _ = os.Chmod(filepath.Join(dest, "sub"), 0o755) })
+	for file, mode := range map[string]os.FileMode{"run.sh": 0o755, "sub/app.yaml": 0o600, "sub": 0o555} {
+		info, err := os.Stat(filepath.Join(dest, file))
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+		if info.Mode().Perm() != mode {
+			t.Errorf("%s mode = %v, want %v", file, info.Mode().Perm(), mode)
+		}
+	}
+}
+
+func TestExtractTarRejectsEscapes(t *testing.T) {
+	for _, name := range []string{"../evil", "conf/../../evil", "/etc/evil", "other/file"} {
+		var archive bytes.Buffer
+		tw := tar.NewWriter(&archive)
+		_ = tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644})
+		_ = tw.Close()
+
+		if _, err := extractTar(&archive, "conf", t.TempDir()); err == nil {
+			t.Errorf("extractTar accepted %q", name)
+		}
+	}
+}
+
+func TestTopUsage(t *testing.T) {
+	var srvURL string
+	metricsStatus := http.StatusOK
+	namespace := "inst-1"
+	srv := cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/installs/inst_1":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(api.Install{Id: "inst_1", ClusterId: "cls_1", Namespace: &namespace})
+		case "/v1/installs/inst_1/pods":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []api.Pod{{Name: "web-0"}, {Name: "web-1"}}})
+		case "/v1/clusters/cls_1/kubeconfig":
+			_, _ = io.WriteString(w, "current-context: admin\nclusters:\n- name: c\n  cluster: {server: "+srvURL+"}\n"+
+				"contexts:\n- name: admin\n  context: {cluster: c, user: u}\nusers:\n- name: u\n  user: {token: secret}\n")
+		case "/apis/metrics.k8s.io/v1beta1/namespaces/other/pods":
+			// A same-named pod of another tenant must not be mixed in.
+			_, _ = io.WriteString(w, `{"items": [{"metadata": {"name": "web-0"}, "containers": [{"name": "app", "usage": {"cpu": "1", "memory": "1Gi"}}]}]}`)
+		case "/apis/metrics.k8s.io/v1beta1/namespaces/inst-1/pods":
+			if r.Header.Get("Authorization") != "Bearer secret" || metricsStatus != http.StatusOK {
+				w.WriteHeader(max(metricsStatus, http.StatusUnauthorized))
+				return
+			}
+			_, _ = io.WriteString(w, `{"items": [
+				{"metadata": {"name": "web-0"}, "containers": [{"name": "app", "usage": {"cpu": "1500000n", "memory": "64Mi"}}]},
+				{"metadata": {"name": "web-1"}, "containers": [{"name": "app", "usage": {"cpu": "250m", "memory": "128974848"}}]},
+				{"metadata": {"name": "other"}, "containers": [{"name": "app", "usage": {"cpu": "1", "memory": "1Gi"}}]}
+			]}`)
+		default:
+			cmdtest.WriteError(w, http.StatusNotFound, "not found")
+		}
+	}))
+	srvURL = srv.URL
+
+	client, _, err := cmdutil.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	metrics, err := newKubeClient(ctx, client, "cls_1")
+	if err != nil {
+		t.Fatalf("newKubeClient: %v", err)
+	}
+	usage, err := fetchUsage(ctx, client, metrics, "inst_1", "inst-1")
+	if err != nil {
+		t.Fatalf("fetchUsage: %v", err)
+	}
+	want := []containerUsage{
+		{Pod: "web-1", Container: "app", CPUMillis: 250, MemoryBytes: 128974848},
+		{Pod: "web-0", Container: "app", CPUMillis: 1, MemoryBytes: 64 << 20},
+	}
+	if !reflect.DeepEqual(usage, want) {
+		t.Errorf("usage = %+v, want %+v", usage, want)
+	}
+
+	metricsStatus = http.StatusNotFound
+	if _, err := fetchUsage(ctx, client, metrics, "inst_1", "inst-1"); !errors.Is(err, errNoMetrics) {
+		t.Errorf("without metrics-server: err = %v, want errNoMetrics", err)
+	}
+	if err := cmdtest.Run(newCmdTop(), "inst_1"); err == nil || !strings.Contains(err.Error(), "metrics-server isn't running") {
+		t.Errorf("top without metrics-server: err = %v", err)
+	}
+}
+
+func TestListAllPartial(t *testing.T) {
+	var requests int
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		cursor := r.URL.Query().Get("cursor")
+		if cursor == "3" {
+			cmdtest.WriteError(w, http.StatusUnauthorized, "token expired")
+			return
+		}
+		next := "2"
+		if cursor == "2" {
+			next = "3"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(api.InstallList{
+			Data:       []api.Install{{Id: "inst_" + next}},
+			Pagination: api.Pagination{HasMore: true, Cursor: &next},
+		})
+	}))
+	t.Setenv("CNAP_OUTPUT_FORMAT", "json")
+
+	cfg := config.DefaultConfig()
+	cfg.ActiveWorkspace = "ws_1"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := cmdtest.Run(newCmdList(), "--all")
+	var partial *cmdutil.PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("err = %v, want *PartialError", err)
+	}
+	if partial.Pages != 2 || !strings.Contains(err.Error(), "token expired") {
+		t.Errorf("err = %v, want partial after 2 pages wrapping the API error", err)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3", requests)
+	}
+}
+
+func TestListFilters(t *testing.T) {
+	var query url.Values
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		if query.Get("region_id") == "reg_bad" {
+			cmdtest.WriteError(w, http.StatusUnprocessableEntity, "Unknown region reg_bad")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(api.InstallList{Data: []api.Install{}})
+	}))
+	cfg := config.DefaultConfig()
+	cfg.ActiveWorkspace = "ws_1"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmdtest.Run(newCmdList(), "--product", "prod_1", "--cluster", "cls_1"); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if query.Get("product_id") != "prod_1" || query.Get("cluster_id") != "cls_1" || query.Has("region_id") {
+		t.Errorf("query = %v, want product_id and cluster_id only", query)
+	}
+
+	err := cmdtest.Run(newCmdList(), "--region", "reg_bad")
+	if err == nil || err.Error() != "Unknown region reg_bad" {
+		t.Errorf("422: err = %v, want the server's message", err)
+	}
+
+	err = cmdtest.Run(newCmdList(), "--cluster", "cls_1", "--region", "reg_1")
+	if err == nil || !strings.Contains(err.Error(), "none of the others can be") {
+		t.Errorf("--cluster with --region: err = %v, want mutually exclusive error", err)
+	}
+}
+
+func TestFetchInstallStatuses(t *testing.T) {
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/installs/inst_ok/status":
+			_ = json.NewEncoder(w).Encode(api.InstallStatus{Phase: "succeeded", Health: "Healthy"})
+		case "/v1/installs/inst_failed/status":
+			_ = json.NewEncoder(w).Encode(api.InstallStatus{Phase: "failed", Health: "Degraded"})
+		default:
+			cmdtest.WriteError(w, http.StatusNotFound, "Install not found")
+		}
+	}))
+	client, _, err := cmdutil.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	installs := []api.Install{{Id: "inst_ok"}, {Id: "inst_failed"}, {Id: "inst_gone"}}
+	got := fetchInstallStatuses(context.Background(), client, installs)
+	if want := []string{"Healthy", "Failed", "-"}; !slices.Equal(got, want) {
+		t.Errorf("statuses = %q, want %q", got, want)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	values := map[string]any{"replicas": float64(1)}
+	var phase string
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/installs/inst_1" && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "inst_1", "template_id": "tpl_1", "workspace_id": "ws_1"})
+		case r.URL.Path == "/v1/templates/tpl_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "tpl_1", "helm_sources": []map[string]any{{
+				"id": "src_web", "values": values,
+				"chart": map[string]any{"repo_url": "https://charts.example.com", "target_revision": "1.0.0"},
+			}}})
+		case r.URL.Path == "/v1/installs/inst_1/values":
+			var body struct {
+				Updates []struct {
+					Values map[string]any `json:"values"`
+				} `json:"updates"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			values = body.Updates[0].Values
+			phase = "running"
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = io.WriteString(w, `{}`)
+		case r.URL.Path == "/v1/installs/inst_1/status":
+			_ = json.NewEncoder(w).Encode(api.InstallStatus{Health: "Healthy", Phase: phase})
+			phase = "succeeded"
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer func(p cmdutil.PollStrategy) { waitPoll = p }(waitPoll)
+	waitPoll.Interval = time.Millisecond
+	valuesFile := filepath.Join(t.TempDir(), "values.yaml")
+	if err := os.WriteFile(valuesFile, []byte("replicas: 3\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cmdtest.Run(newCmdRollback(), "inst_1", "--yes"); err == nil || !strings.Contains(err.Error(), "no revisions") {
+		t.Errorf("rollback without revisions: err = %v", err)
+	}
+
+	if err := cmdtest.Run(newCmdUpdateValues(), "inst_1", "--source", "src_web", "--values", valuesFile); err != nil {
+		t.Fatalf("update-values: %v", err)
+	}
+	if values["replicas"] != float64(3) {
+		t.Fatalf("values after update = %v", values)
+	}
+
+	if err := cmdtest.Run(newCmdRollback(), "inst_1"); err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Errorf("rollback without --yes in non-interactive mode: err = %v", err)
+	}
+	if err := cmdtest.Run(newCmdRollback(), "inst_1", "--yes", "--wait"); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if values["replicas"] != float64(1) {
+		t.Errorf("values after rollback = %v, want the original", values)
+	}
+
+	// The rollback snapshotted the updated values, so it can be undone.
+	_, cfg, err := cmdutil.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	install := &api.Install{Id: "inst_1", WorkspaceId: "ws_1"}
+	revs, err := loadRevisions(cfg, install)
+	if err != nil || len(revs) != 2 || revs[1].Command != "rollback to 1" {
+		t.Fatalf("revisions = %+v, %v", revs, err)
+	}
+	if err := cmdtest.Run(newCmdRollback(), "inst_1", "--yes", "--to", "2"); err != nil {
+		t.Fatalf("rollback --to 2: %v", err)
+	}
+	if values["replicas"] != float64(3) {
+		t.Errorf("values after rollback --to 2 = %v", values)
+	}
+
+	// Snapshots follow the install's workspace, not the CLI's.
+	cmdutil.Workspace = "ws_2"
+	t.Cleanup(func() { cmdutil.Workspace = "" })
+	if err := cmdtest.Run(newCmdRollback(), "inst_1", "--yes", "--to", "3"); err != nil {
+		t.Errorf("rollback with another --workspace: %v", err)
+	}
+	cmdutil.Workspace = ""
+	if revs, err := loadRevisions(cfg, &api.Install{Id: "inst_1", WorkspaceId: "ws_2"}); err != nil || len(revs) != 0 {
+		t.Errorf("revisions for an install in another workspace = %+v, %v", revs, err)
+	}
+	t.Setenv("CNAP_API_URL", "https://other.example.com")
+	if revs, err := loadRevisions(config.DefaultConfig(), install); err != nil || len(revs) != 0 {
+		t.Errorf("revisions for another API URL = %+v, %v", revs, err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/installs/inst_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "inst_1", "template_id": "tpl_1"})
+		case "/v1/templates/tpl_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "tpl_1", "helm_sources": []map[string]any{{
+				"id": "src_web", "values": map[string]any{"replicas": 2, "image": map[string]any{"tag": "1.0"}},
+				"chart": map[string]any{"repo_url": "https://charts.example.com", "target_revision": "1.0.0"},
+			}}})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	write := func(content string) string {
+		path := filepath.Join(t.TempDir(), "values.yaml")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	same := write("image:\n  tag: \"1.0\"\nreplicas: 2.0\n")
+	if err := cmdtest.Run(newCmdDiff(), "inst_1", "-f", same); err != nil {
+		t.Errorf("diff of equal values: %v", err)
+	}
+
+	changed := write("image:\n  tag: \"1.1\"\nreplicas: 2\n")
+	var exitErr *cmdutil.ExitError
+	if err := cmdtest.Run(newCmdDiff(), "inst_1", "-f", changed); !errors.As(err, &exitErr) || exitErr.Code != 1 {
+		t.Errorf("diff of changed values: err = %v, want exit status 1", err)
+	}
+
+	if err := cmdtest.Run(newCmdDiff(), "inst_1", "--source", "src_db", "-f", changed); err == nil || !strings.Contains(err.Error(), "unknown helm source") {
+		t.Errorf("unknown source: err = %v", err)
+	}
+}
+
+func TestValuesSchema(t *testing.T) {
+	var chartPath string
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		chart := map[string]any{"repo_url": "https://charts.example.com", "chart": "web", "target_revision": "1.0.0"}
+		switch r.URL.Path {
+		case "/v1/installs/inst_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "inst_1", "template_id": "tpl_1"})
+		case "/v1/templates/tpl_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "tpl_1", "helm_sources": []map[string]any{
+				{"id": "src_web", "chart": chart},
+				{"id": "src_db", "chart": chart},
+			}})
+		case "/v1/templates/tpl_1/helm-sources/src_db/chart":
+			chartPath = r.URL.Path
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"template_helm_source_id": "src_db", "chart": chart,
+				"values_schema":  map[string]any{"type": "object"},
+				"default_values": "replicas: 1\n",
+			})
+		default:
+			cmdtest.WriteError(w, http.StatusNotFound, "not found")
+		}
+	}))
+
+	if err := cmdtest.Run(newCmdValuesSchema(), "inst_1"); err == nil || !strings.Contains(err.Error(), "--source is required") {
+		t.Errorf("several sources without --source: err = %v", err)
+	}
+	if err := cmdtest.Run(newCmdValuesSchema(), "inst_1", "--source", "src_db"); err != nil {
+		t.Fatalf("values-schema: %v", err)
+	}
+	if chartPath == "" {
+		t.Error("chart schema was not requested for src_db")
+	}
+	if err := cmdtest.Run(newCmdValuesSchema(), "inst_1", "--source", "src_cache"); err == nil || !strings.Contains(err.Error(), "unknown helm source") {
+		t.Errorf("unknown source: err = %v", err)
+	}
+}
+
+func TestScale(t *testing.T) {
+	var srvURL string
+	var patched []string
+	other := "other"
+	srv := cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/installs/inst_1":
+			_ = json.NewEncoder(w).Encode(api.Install{Id: "inst_1", ClusterId: "cls_1"})
+		case "/v1/installs/inst_2":
+			_ = json.NewEncoder(w).Encode(api.Install{Id: "inst_2", ClusterId: "cls_1"})
+		case "/v1/installs/inst_3":
+			_ = json.NewEncoder(w).Encode(api.Install{Id: "inst_3", ClusterId: "cls_1", Namespace: &other})
+		case "/v1/installs/inst_1/pods":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []api.Pod{{Name: "web-7d9f8b6c5-x2k4j"}, {Name: "db-0"}}})
+		case "/v1/installs/inst_2/pods":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": []api.Pod{{Name: "db-0"}}})
+		case "/v1/clusters/cls_1/kubeconfig":
+			_, _ = io.WriteString(w, "clusters:\n- name: c\n  cluster: {server: "+srvURL+"}\n"+
+				"contexts:\n- name: admin\n  context: {cluster: c, user: u}\nusers:\n- name: u\n  user: {token: secret}\n")
+		case "/api/v1/pods":
+			// db-0 exists in two namespaces; the web pod only in inst-1.
+			switch r.URL.Query().Get("fieldSelector") {
+			case "metadata.name=db-0":
+				_, _ = io.WriteString(w, `{"items": [{"metadata": {"namespace": "inst-1"}}, {"metadata": {"namespace": "other"}}]}`)
+			case "metadata.name=web-7d9f8b6c5-x2k4j":
+				_, _ = io.WriteString(w, `{"items": [{"metadata": {"namespace": "inst-1"}}]}`)
+			default:
+				_, _ = io.WriteString(w, `{"items": []}`)
+			}
+		case "/apis/apps/v1/namespaces/inst-1/deployments":
+			_, _ = io.WriteString(w, `{"items": [
+				{"metadata": {"name": "web", "namespace": "inst-1"}, "spec": {"replicas": 3}},
+				{"metadata": {"name": "worker", "namespace": "inst-1"}, "spec": {"replicas": 0}}
+			]}`)
+		case "/apis/apps/v1/namespaces/inst-1/statefulsets":
+			_, _ = io.WriteString(w, `{"items": [{"metadata": {"name": "db", "namespace": "inst-1"}, "spec": {"replicas": 1}}]}`)
+		case "/apis/apps/v1/namespaces/other/deployments":
+			_, _ = io.WriteString(w, `{"items": [{"metadata": {"name": "web", "namespace": "other"}, "spec": {"replicas": 5}}]}`)
+		case "/apis/apps/v1/namespaces/other/statefulsets":
+			_, _ = io.WriteString(w, `{"items": []}`)
+		default:
+			if r.Method == http.MethodPatch && strings.HasSuffix(r.URL.Path, "/scale") {
+				body, _ := io.ReadAll(r.Body)
+				patched = append(patched, r.URL.Path+" "+string(body))
+				var req struct {
+					Spec struct {
+						Replicas int `json:"replicas"`
+					} `json:"spec"`
+				}
+				_ = json.Unmarshal(body, &req)
+				_ = json.NewEncoder(w).Encode(req)
+				return
+			}
+			cmdtest.WriteError(w, http.StatusNotFound, "not found")
+		}
+	}))
+	srvURL = srv.URL
+
+	if err := cmdtest.Run(newCmdScale(), "inst_1", "--workload", "worker", "--replicas", "2"); err != nil {
+		t.Fatalf("scale: %v", err)
+	}
+	// The namespace reported by the API is used as is.
+	if err := cmdtest.Run(newCmdScale(), "inst_3", "--workload", "web", "--replicas", "4"); err != nil {
+		t.Fatalf("scale: %v", err)
+	}
+	want := []string{
+		`/apis/apps/v1/namespaces/inst-1/deployments/worker/scale {"spec":{"replicas":2}}`,
+		`/apis/apps/v1/namespaces/other/deployments/web/scale {"spec":{"replicas":4}}`,
+	}
+	if !reflect.DeepEqual(patched, want) {
+		t.Errorf("patched = %q, want %q", patched, want)
+	}
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"inst_1", "--workload", "web", "--replicas", "-1"}, "--replicas must be 0 or more"},
+		{[]string{"inst_1", "--workload", "web", "--replicas", "0"}, "use --yes to confirm"},
+		{[]string{"inst_1", "--workload", "api", "--replicas", "1"}, "statefulset/db, deployment/web, deployment/worker)"},
+		{[]string{"inst_1", "--replicas", "1"}, "--workload required"},
+		{[]string{"inst_2", "--workload", "db", "--replicas", "2"}, "its pod names match in inst-1, other"},
+	} {
+		err := cmdtest.Run(newCmdScale(), tt.args...)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("scale %v: err = %v, want %q", tt.args, err, tt.want)
+		}
+	}
+	if len(patched) != 2 {
+		t.Errorf("unexpected scale requests: %q", patched[2:])
+	}
+
+	// A no-op scale still prints the result as JSON rather than prose.
+	cmdutil.OutputFormat = "json"
+	t.Cleanup(func() { cmdutil.OutputFormat = "" })
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	err = cmdtest.Run(newCmdScale(), "inst_1", "--workload", "web", "--replicas", "3")
+	os.Stdout = stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unchanged scale: %v", err)
+	}
+	var res struct {
+		Replicas int `json:"replicas"`
+		Previous int `json:"previous_replicas"`
+	}
+	if err := json.Unmarshal(out, &res); err != nil || res.Replicas != 3 || res.Previous != 3 {
+		t.Errorf("unchanged scale output = %q (%v), want JSON with 3 replicas before and after", out, err)
+	}
+	if len(patched) != 2 {
+		t.Errorf("unchanged scale sent a scale request: %q", patched[2:])
+	}
+}
+
+func TestKubeError(t *testing.T) {
+	for _, tt := range []struct {
+		body, want string
+	}{
+		{`{"kind":"Status","message":"deployments.apps \"web\" is forbidden"}`, `deployments.apps "web" is forbidden (403 Forbidden)`},
+		{"<html>Bearer abc.def</html>", "unexpected response: 403 Forbidden: <html>Bearer [REDACTED]</html>"},
+	} {
+		resp := &http.Response{Status: "403 Forbidden", Body: io.NopCloser(strings.NewReader(tt.body))}
+		if err := kubeError(resp); err == nil || err.Error() != tt.want {
+			t.Errorf("kubeError(%q) = %v, want %q", tt.body, err, tt.want)
+		}
+	}
+}
+
+func TestFilterPods(t *testing.T) {
+	running, pending := "Running", "Pending"
+	pods := []api.Pod{
+		{Name: "web-abc-1", Phase: &running, ContainerStatuses: &[]api.ContainerStatus{{Ready: true, RestartCount: 2}, {Ready: false, RestartCount: 1}}},
+		{Name: "web-abc-2", Phase: &pending},
+		{Name: "db-0", Phase: &running},
+	}
+
+	names := func(pods []api.Pod) []string {
+		out := []string{}
+		for _, p := range pods {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+	for _, tt := range []struct {
+		name, status string
+		want         []string
+	}{
+		{"", "", []string{"web-abc-1", "web-abc-2", "db-0"}},
+		{"web-*", "", []string{"web-abc-1", "web-abc-2"}},
+		{"", "running", []string{"web-abc-1", "db-0"}},
+		{"web-*", "Running", []string{"web-abc-1"}},
+		{"api-*", "", []string{}},
+	} {
+		if got := names(filterPods(pods, tt.name, tt.status)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("filterPods(%q, %q) = %v, want %v", tt.name, tt.status, got, tt.want)
+		}
+	}
+
+	if got := podReady(pods[0]); got != "1/2" {
+		t.Errorf("podReady = %q, want 1/2", got)
+	}
+	if got := podRestarts(pods[0]); got != "3" {
+		t.Errorf("podRestarts = %q, want 3", got)
+	}
+	// float32 timestamps are only precise to ~2 minutes, so age is
+	// tested on exact durations.
+	for d, want := range map[time.Duration]string{
+		-time.Second:              "0s",
+		90 * time.Second:          "90s",
+		90 * time.Minute:          "90m",
+		3 * time.Hour:             "3h",
+		3*time.Hour - time.Second: "2h",
+		72 * time.Hour:            "3d",
+	} {
+		if got := age(d); got != want {
+			t.Errorf("age(%v) = %q, want %q", d, got, want)
+		}
+	}
+	if got := formatAge(nil); got != "-" {
+		t.Errorf("formatAge(nil) = %q, want -", got)
+	}
+
+	if err := cmdtest.Run(newCmdPods(), "inst_1", "--name", "web-["); err == nil || !strings.Contains(err.Error(), "invalid --name pattern") {
+		t.Errorf("bad glob: err = %v", err)
+	}
+}
+
+func TestPodsWatch(t *testing.T) {
+	podsWatchInterval = time.Millisecond
+	t.Cleanup(func() { podsWatchInterval = 2 * time.Second })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	requests := 0
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 3 {
+			cancel()
+		}
+		phase := "Pending"
+		if requests > 1 {
+			phase = "Running"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []api.Pod{{Name: "web-0", Phase: &phase}}})
+	}))
+
+	cmd := newCmdPods()
+	cmd.SetArgs([]string{"inst_1", "--watch"})
+	cmd.SetOut(io.Discard)
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		t.Fatalf("pods --watch: %v", err)
+	}
+	if requests < 3 {
+		t.Errorf("requests = %d, want at least 3 refreshes", requests)
+	}
+}
+
+func TestCreateName(t *testing.T) {
+	cmdutil.Workspace = "ws_1"
+	t.Cleanup(func() { cmdutil.Workspace = "" })
+
+	var body map[string]any
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	if err := cmdtest.Run(newCmdCreate(), "--product", "prod_1", "--region", "reg_1", "--name", " web "); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if body["name"] != "web" || body["product_id"] != "prod_1" {
+		t.Errorf("body = %v, want name web", body)
+	}
+
+	if err := cmdtest.Run(newCmdCreate(), "--product", "prod_1", "--region", "reg_1"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, ok := body["name"]; ok {
+		t.Errorf("body = %v, want no name without --name", body)
+	}
+
+	err := cmdtest.Run(newCmdCreate(), "--product", "prod_1", "--region", "reg_1", "--name", "")
+	if err == nil || !strings.Contains(err.Error(), "must not be empty") {
+		t.Errorf("empty --name: err = %v", err)
+	}
+}
+
+func TestRename(t *testing.T) {
+	var patched map[string]any
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		name := "old"
+		if r.Method == http.MethodPatch {
+			_ = json.NewDecoder(r.Body).Decode(&patched)
+			name = patched["name"].(string)
+		}
+		_ = json.NewEncoder(w).Encode(api.Install{Id: "inst_1", Name: &name})
+	}))
+
+	if err := cmdtest.Run(newCmdRename(), "inst_1", "new-name"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if patched["name"] != "new-name" {
+		t.Errorf("PATCH body = %v, want name new-name", patched)
+	}
+
+	err := cmdtest.Run(newCmdRename(), "inst_1", "  ")
+	if err == nil || !strings.Contains(err.Error(), "must not be empty") {
+		t.Errorf("blank name: err = %v", err)
+	}
+	err = cmdtest.Run(newCmdRename(), "new-name")
+	if err == nil || !strings.Contains(err.Error(), "<install-id> argument required") {
+		t.Errorf("missing id: err = %v", err)
+	}
+}
+
+func TestExecPiped(t *testing.T) {
+	prelude := execPipedPrelude("/bin/sh")
+	var mu sync.Mutex
+	var got strings.Builder
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		ctx := r.Context()
+		write := func(msg wsterm.Message) {
+			data, _ := json.Marshal(msg)
+			_ = conn.Write(ctx, websocket.MessageText, data)
+		}
+		for {
+			_, data, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+			var msg wsterm.Message
+			_ = json.Unmarshal(data, &msg)
+			mu.Lock()
+			got.WriteString(msg.Data)
+			input := got.String()
+			mu.Unlock()
+			switch {
+			case msg.Data == prelude:
+				// The TTY echoes the prelude before stty -echo runs.
+				write(wsterm.Message{Type: "output", Data: "$ " + strings.TrimSuffix(prelude, "\n") + "\r\n" + execPipedMarker + "\r\n"})
+			case strings.HasSuffix(input, "\x04"):
+				write(wsterm.Message{Type: "output", Data: "root 1\r\nroot 7\r"})
+				write(wsterm.Message{Type: "output", Data: "\n"})
+				code := 3
+				write(wsterm.Message{Type: "exit", Code: &code})
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	// Control bytes and lines longer than the TTY's line buffer.
+	script := "ps aux\x03\n" + strings.Repeat("x", 10000)
+
+	var stdout, stderr bytes.Buffer
+	cfg := &config.Config{AuthURL: srv.URL}
+	u, err := execURL(cfg, "inst_1", "web-0", "app", "/bin/sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = runExecPiped(context.Background(), cfg, u, "/bin/sh", strings.NewReader(script), &stdout, &stderr)
+
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Errorf("err = %v, want exit status 3", err)
+	}
+	if want := "root 1\nroot 7\n"; stdout.String() != want {
+		t.Errorf("stdout = %q, want %q", stdout.String(), want)
+	}
+
+	sent, ok := strings.CutPrefix(got.String(), prelude)
+	if !ok {
+		t.Fatalf("sent %q, want the prelude first", got.String())
+	}
+	encoded, ok := strings.CutSuffix(sent, "\n\x04")
+	if !ok {
+		t.Fatalf("sent %q, want it to end with EOF on its own line", sent)
+	}
+	for _, line := range strings.Split(encoded, "\n") {
+		if len(line) > 76 {
+			t.Fatalf("sent a %d byte line, want at most 76", len(line))
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(encoded, "\n", ""))
+	if err != nil || string(decoded) != script {
+		t.Errorf("sent script decodes to %q (%v), want %q", decoded, err, script)
+	}
+}
+
+func TestExecPipedStartTimeout(t *testing.T) {
+	defer func(d time.Duration) { execPipedStartTimeout = d }(execPipedStartTimeout)
+	execPipedStartTimeout = 50 * time.Millisecond
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		// A shell that never runs the prelude: only its echo comes back.
+		for {
+			if _, _, err := conn.Read(r.Context()); err != nil {
+				return
+			}
+			data, _ := json.Marshal(wsterm.Message{Type: "output", Data: strings.Repeat("sh: stty: not found\r\n", 100)})
+			_ = conn.Write(r.Context(), websocket.MessageText, data)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{AuthURL: srv.URL}
+	u, err := execURL(cfg, "inst_1", "web-0", "app", "/bin/sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	err = runExecPiped(context.Background(), cfg, u, "/bin/sh", strings.NewReader("true"), &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "didn't start the script") {
+		t.Errorf("err = %v, want start timeout", err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("stdout = %q, want nothing before the marker", stdout.String())
+	}
+}
+
+func TestPickInstallRequiresWorkspace(t *testing.T) {
+	_, err := pickInstall(context.Background(), nil, &config.Config{})
+	if err == nil || !strings.Contains(err.Error(), "no active workspace") {
+		t.Errorf("got error %v, want no active workspace", err)
+	}
+}
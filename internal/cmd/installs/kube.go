@@ -0,0 +1,257 @@
+package installs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/debug"
+	"github.com/cnap-tech/cli/internal/useragent"
+	"gopkg.in/yaml.v3"
+)
+
+// kubeClient talks to a cluster's Kubernetes API directly, using its
+// admin kubeconfig. The CNAP API has no endpoints for metrics or scaling,
+// so top and scale go through it; it only works for KaaS-managed clusters.
+type kubeClient struct {
+	server string
+	token  string
+	http   *http.Client
+}
+
+// errNoKubeconfig means the cluster's admin kubeconfig can't be fetched,
+// e.g. because the cluster isn't KaaS-managed.
+var errNoKubeconfig = errors.New("cluster kubeconfig unavailable")
+
+// newKubeClient fetches the cluster's admin kubeconfig (KaaS clusters
+// only) and builds a client from its current context.
+func newKubeClient(ctx context.Context, client *api.ClientWithResponses, clusterID string) (*kubeClient, error) {
+	resp, err := client.GetV1ClustersIdKubeconfig(ctx, clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching kubeconfig: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading kubeconfig: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr api.Error
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, fmt.Errorf("%w: %s", errNoKubeconfig, apiErr.Error.Message)
+		}
+		return nil, fmt.Errorf("%w (%s)", errNoKubeconfig, resp.Status)
+	}
+	return parseKubeconfig(body)
+}
+
+// kubeconfig holds the parts of a kubeconfig file kubeClient uses.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string `yaml:"token"`
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKeyData         string `yaml:"client-key-data"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+func parseKubeconfig(data []byte) (*kubeClient, error) {
+	var kc kubeconfig
+	if err := yaml.Unmarshal(data, &kc); err != nil {
+		return nil, fmt.Errorf("parsing kubeconfig: %w", err)
+	}
+
+	clusterName, userName := "", ""
+	for _, c := range kc.Contexts {
+		if c.Name == kc.CurrentContext || kc.CurrentContext == "" {
+			clusterName, userName = c.Context.Cluster, c.Context.User
+			break
+		}
+	}
+
+	out := &kubeClient{}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	for _, c := range kc.Clusters {
+		if c.Name != clusterName && clusterName != "" {
+			continue
+		}
+		out.server = strings.TrimSuffix(c.Cluster.Server, "/")
+		tlsConfig.InsecureSkipVerify = c.Cluster.InsecureSkipTLSVerify
+		if c.Cluster.CertificateAuthorityData != "" {
+			pem, err := base64.StdEncoding.DecodeString(c.Cluster.CertificateAuthorityData)
+			if err != nil {
+				return nil, fmt.Errorf("parsing kubeconfig: certificate-authority-data: %w", err)
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			tlsConfig.RootCAs.AppendCertsFromPEM(pem)
+		}
+		break
+	}
+	if out.server == "" {
+		return nil, fmt.Errorf("parsing kubeconfig: no cluster server for context %q", kc.CurrentContext)
+	}
+
+	for _, u := range kc.Users {
+		if u.Name != userName && userName != "" {
+			continue
+		}
+		out.token = u.User.Token
+		if u.User.ClientCertificateData != "" {
+			certPEM, err := base64.StdEncoding.DecodeString(u.User.ClientCertificateData)
+			if err != nil {
+				return nil, fmt.Errorf("parsing kubeconfig: client-certificate-data: %w", err)
+			}
+			keyPEM, err := base64.StdEncoding.DecodeString(u.User.ClientKeyData)
+			if err != nil {
+				return nil, fmt.Errorf("parsing kubeconfig: client-key-data: %w", err)
+			}
+			cert, err := tls.X509KeyPair(certPEM, keyPEM)
+			if err != nil {
+				return nil, fmt.Errorf("parsing kubeconfig: client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		break
+	}
+
+	out.http = debug.TLSClient(tlsConfig)
+	return out, nil
+}
+
+// do sends a request to the Kubernetes API. A non-nil body is sent as a
+// JSON merge patch, the only kind of write kubeClient makes.
+func (c *kubeClient) do(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.server+path, r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", useragent.String())
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+	}
+	return c.http.Do(req)
+}
+
+// kubeError turns a failed Kubernetes API response into an error, using
+// the message of its Status body when there is one and otherwise
+// cmdutil.APIError's truncated, redacted copy of the body.
+func kubeError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var status struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &status) == nil && status.Message != "" {
+		return fmt.Errorf("%s (%s)", status.Message, resp.Status)
+	}
+	return cmdutil.APIError(resp.Status, body)
+}
+
+// podNamespaces returns the namespaces holding a pod with the given name.
+func (c *kubeClient) podNamespaces(ctx context.Context, name string) ([]string, error) {
+	q := url.Values{"fieldSelector": {"metadata.name=" + name}}
+	resp, err := c.do(ctx, http.MethodGet, "/api/v1/pods?"+q.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("finding pod %s: %w", name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("finding pod %s: %w", name, kubeError(resp))
+	}
+
+	var list struct {
+		Items []struct {
+			Metadata struct {
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("decoding pods: %w", err)
+	}
+	namespaces := make([]string, len(list.Items))
+	for i, item := range list.Items {
+		namespaces[i] = item.Metadata.Namespace
+	}
+	return namespaces, nil
+}
+
+// installNamespace returns the namespace the install runs in. Older APIs
+// don't report it, so it's then the one namespace holding all of the
+// install's pods by name; other tenants' workloads share the cluster, so
+// a name match in more than one namespace is refused rather than guessed.
+func installNamespace(ctx context.Context, client *api.ClientWithResponses, kube *kubeClient, install *api.Install) (string, error) {
+	if install.Namespace != nil && *install.Namespace != "" {
+		return *install.Namespace, nil
+	}
+
+	resp, err := client.GetV1InstallsIdPodsWithResponse(ctx, install.Id)
+	if err != nil {
+		return "", fmt.Errorf("fetching pods: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return "", cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+	}
+	if len(resp.JSON200.Data) == 0 {
+		return "", fmt.Errorf("can't find the namespace of install %s: it has no pods to locate it by", install.Id)
+	}
+
+	var candidates []string
+	for i, p := range resp.JSON200.Data {
+		namespaces, err := kube.podNamespaces(ctx, p.Name)
+		if err != nil {
+			return "", err
+		}
+		if i == 0 {
+			candidates = namespaces
+		} else {
+			candidates = slices.DeleteFunc(candidates, func(ns string) bool { return !slices.Contains(namespaces, ns) })
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("can't find the namespace of install %s: its pods aren't on the cluster", install.Id)
+	case 1:
+		return candidates[0], nil
+	default:
+		slices.Sort(candidates)
+		return "", fmt.Errorf("can't tell which namespace install %s runs in: its pod names match in %s", install.Id, strings.Join(candidates, ", "))
+	}
+}
@@ -0,0 +1,93 @@
+package installs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cnap-tech/cli/internal/output"
+)
+
+// Well-known keys for structured log fields, in order of preference.
+var (
+	logLevelKeys = []string{"level", "lvl", "severity"}
+	logTimeKeys  = []string{"ts", "time", "timestamp", "@timestamp"}
+	logMsgKeys   = []string{"msg", "message"}
+)
+
+// formatJSONLog reformats a JSON log line as "LEVEL ts msg key=val ...",
+// coloring the level. Lines that aren't a JSON object are returned as is.
+func formatJSONLog(line string) string {
+	// Numbers are kept as written: IDs and nanosecond timestamps don't
+	// survive a round trip through float64.
+	var fields map[string]any
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil || fields == nil || dec.More() {
+		return line
+	}
+
+	level := takeField(fields, logLevelKeys)
+	ts := takeField(fields, logTimeKeys)
+	msg := takeField(fields, logMsgKeys)
+
+	var parts []string
+	if level != "" {
+		lvl := fmt.Sprintf("%-5s", strings.ToUpper(level))
+		if output.ColorEnabled() {
+			lvl = output.LogLevelStyle(level).Render(lvl)
+		}
+		parts = append(parts, lvl)
+	}
+	if ts != "" {
+		parts = append(parts, ts)
+	}
+	if msg != "" {
+		parts = append(parts, msg)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, k+"="+fieldString(fields[k]))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// takeField removes and returns the first present key from fields.
+func takeField(fields map[string]any, keys []string) string {
+	for _, k := range keys {
+		if v, ok := fields[k]; ok {
+			delete(fields, k)
+			if s, ok := v.(string); ok {
+				return s
+			}
+			return fieldString(v)
+		}
+	}
+	return ""
+}
+
+func fieldString(v any) string {
+	switch v := v.(type) {
+	case string:
+		if strings.ContainsAny(v, " \t\"=") {
+			return fmt.Sprintf("%q", v)
+		}
+		return v
+	case nil:
+		return "null"
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
@@ -0,0 +1,125 @@
+package installs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// errNoMetrics means the cluster can't report usage (no admin kubeconfig
+// or no metrics-server); the wrapped detail says which.
+var errNoMetrics = errors.New("metrics aren't available for this install")
+
+// containerUsage is one row of `installs top`.
+type containerUsage struct {
+	Pod         string `json:"pod"`
+	Container   string `json:"container"`
+	CPUMillis   int64  `json:"cpu_millicores"`
+	MemoryBytes int64  `json:"memory_bytes"`
+}
+
+// podMetricsList is the metrics.k8s.io/v1beta1 PodMetricsList response.
+type podMetricsList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Containers []struct {
+			Name  string `json:"name"`
+			Usage struct {
+				CPU    string `json:"cpu"`
+				Memory string `json:"memory"`
+			} `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// podUsage returns per-container usage for the named pods in namespace.
+func (c *kubeClient) podUsage(ctx context.Context, namespace string, pods []string) ([]containerUsage, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/apis/metrics.k8s.io/v1beta1/namespaces/"+url.PathEscape(namespace)+"/pods", nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching metrics: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusServiceUnavailable:
+		return nil, fmt.Errorf("%w: metrics-server isn't running in the cluster", errNoMetrics)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("fetching metrics: %w", kubeError(resp))
+	}
+
+	var list podMetricsList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("decoding metrics: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(pods))
+	for _, p := range pods {
+		wanted[p] = true
+	}
+	var usage []containerUsage
+	for _, item := range list.Items {
+		if !wanted[item.Metadata.Name] {
+			continue
+		}
+		for _, c := range item.Containers {
+			cpu, err := parseCPUQuantity(c.Usage.CPU)
+			if err != nil {
+				return nil, err
+			}
+			mem, err := parseMemoryQuantity(c.Usage.Memory)
+			if err != nil {
+				return nil, err
+			}
+			usage = append(usage, containerUsage{Pod: item.Metadata.Name, Container: c.Name, CPUMillis: cpu, MemoryBytes: mem})
+		}
+	}
+	return usage, nil
+}
+
+// parseCPUQuantity converts a Kubernetes CPU quantity ("250m", "12345n",
+// "1") to millicores.
+func parseCPUQuantity(q string) (int64, error) {
+	divisors := []struct {
+		suffix   string
+		perMilli float64
+	}{{"n", 1e6}, {"u", 1e3}, {"m", 1}, {"", 1e-3}}
+	for _, d := range divisors {
+		if num, ok := strings.CutSuffix(q, d.suffix); ok && num != "" {
+			v, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				break
+			}
+			return int64(v / d.perMilli), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid CPU quantity %q", q)
+}
+
+// parseMemoryQuantity converts a Kubernetes memory quantity ("128974848",
+// "123Mi", "1G") to bytes.
+func parseMemoryQuantity(q string) (int64, error) {
+	multipliers := []struct {
+		suffix string
+		factor float64
+	}{
+		{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40},
+		{"k", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12}, {"", 1},
+	}
+	for _, m := range multipliers {
+		if num, ok := strings.CutSuffix(q, m.suffix); ok && num != "" {
+			v, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				break
+			}
+			return int64(v * m.factor), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid memory quantity %q", q)
+}
@@ -0,0 +1,84 @@
+package installs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+func newCmdRename() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename [install-id] <new-name>",
+		Short: "Rename an install",
+		Long: `Sets the name shown for an install in list and get output.
+
+When run interactively with only the new name, shows a picker to select
+the install.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 && !prompt.IsInteractive() {
+				return fmt.Errorf("<install-id> argument required when not running interactively")
+			}
+			name := strings.TrimSpace(args[len(args)-1])
+			if name == "" {
+				return fmt.Errorf("new name must not be empty")
+			}
+
+			client, cfg, err := cmdutil.NewClient()
+			if err != nil {
+				return err
+			}
+
+			installID := ""
+			if len(args) == 2 {
+				installID = args[0]
+			} else {
+				installID, err = pickInstall(cmd.Context(), client, cfg)
+				if err != nil {
+					return err
+				}
+			}
+
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+
+			getResp, err := client.GetV1InstallsIdWithResponse(cmd.Context(), installID)
+			if err != nil {
+				return fmt.Errorf("fetching install: %w", err)
+			}
+			if getResp.JSON200 == nil {
+				return cmdutil.APIError(getResp.Status(), getResp.Body, getResp.JSON401, getResp.JSON404)
+			}
+			oldName := getResp.JSON200.Name
+
+			resp, err := client.PatchV1InstallsIdWithResponse(cmd.Context(), installID, api.PatchV1InstallsIdJSONRequestBody{Name: name})
+			if err != nil {
+				return fmt.Errorf("renaming install: %w", err)
+			}
+			if resp.JSON200 == nil {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403, resp.JSON404, resp.JSON422)
+			}
+			cacheInstallNames(*resp.JSON200)
+
+			if format.IsJSON() {
+				return output.PrintData(format, resp.JSON200)
+			}
+			from := "(unnamed)"
+			if oldName != nil {
+				from = strconv.Quote(*oldName)
+			}
+			fmt.Printf("Renamed install %s: %s → %q\n", installID, from, name)
+			return nil
+		},
+	}
+
+	return cmd
+}
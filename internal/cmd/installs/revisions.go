@@ -0,0 +1,357 @@
+package installs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/config"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+// The API keeps no values history, so the CLI snapshots an install's helm
+// source values before each update-values (and rollback) and stores them
+// under ~/.cnap/revisions/<scope>/<install-id>.json. Rollback re-applies a
+// snapshot.
+// Only changes made through this CLI, on this machine, are recorded.
+
+// maxRevisions is how many snapshots are kept per install.
+const maxRevisions = 20
+
+// valuesRevision is the install's values as they were before an update.
+type valuesRevision struct {
+	Revision  int            `json:"revision"`
+	CreatedAt int64          `json:"created_at"`
+	Command   string         `json:"command"`
+	Sources   []sourceValues `json:"sources"`
+}
+
+// revisionsPath returns the file holding the install's snapshots. They're
+// kept per API URL and the install's own workspace, so an install ID on
+// another CNAP instance never sees these values, while --workspace or the
+// active workspace don't matter. Unlike config.Config.CacheScope the token
+// isn't part of the scope: history survives logging in again.
+func revisionsPath(cfg *config.Config, install *api.Install) (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(cfg.BaseURL() + "\x00" + install.WorkspaceId))
+	scope := hex.EncodeToString(sum[:8])
+	return filepath.Join(dir, "revisions", scope, filepath.Base(install.Id)+".json"), nil
+}
+
+func loadRevisions(cfg *config.Config, install *api.Install) ([]valuesRevision, error) {
+	p, err := revisionsPath(cfg, install)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading revisions: %w", err)
+	}
+	var revs []valuesRevision
+	if err := json.Unmarshal(data, &revs); err != nil {
+		return nil, fmt.Errorf("reading revisions: %s: %w", p, err)
+	}
+	return revs, nil
+}
+
+// fetchInstall returns the install with the given ID.
+func fetchInstall(ctx context.Context, client *api.ClientWithResponses, installID string) (*api.Install, error) {
+	resp, err := client.GetV1InstallsIdWithResponse(ctx, installID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching install: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+	}
+	return resp.JSON200, nil
+}
+
+// recordRevision snapshots the install's current helm source values,
+// labelled with the command about to change them.
+func recordRevision(ctx context.Context, client *api.ClientWithResponses, cfg *config.Config, install *api.Install, command string) error {
+	if install.TemplateId == nil {
+		return fmt.Errorf("install %s has no template", install.Id)
+	}
+	helmSources, err := fetchHelmSources(ctx, client, *install.TemplateId)
+	if err != nil {
+		return err
+	}
+
+	sources := make([]sourceValues, len(helmSources))
+	for i, s := range helmSources {
+		sources[i] = sourceValues{TemplateHelmSourceId: s.Id, Values: map[string]*interface{}{}}
+		if s.Values != nil {
+			sources[i].Values = *s.Values
+		}
+	}
+
+	revs, err := loadRevisions(cfg, install)
+	if err != nil {
+		return err
+	}
+	next := 1
+	if len(revs) > 0 {
+		next = revs[len(revs)-1].Revision + 1
+	}
+	revs = append(revs, valuesRevision{Revision: next, CreatedAt: time.Now().Unix(), Command: command, Sources: sources})
+	if len(revs) > maxRevisions {
+		revs = revs[len(revs)-maxRevisions:]
+	}
+
+	p, err := revisionsPath(cfg, install)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(revs, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return fmt.Errorf("saving revision: %w", err)
+	}
+	if err := config.WriteFileAtomic(p, data, 0o600); err != nil {
+		return fmt.Errorf("saving revision: %w", err)
+	}
+	return nil
+}
+
+func newCmdRevisions() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revisions [install-id]",
+		Short: "List values snapshots available to rollback",
+		Long: `Lists the values snapshots taken before each update-values and rollback
+run from this machine, newest first. Pass a revision to rollback --to.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !prompt.IsInteractive() {
+				return fmt.Errorf("<install-id> argument required when not running interactively")
+			}
+
+			client, cfg, err := cmdutil.NewClient()
+			if err != nil {
+				return err
+			}
+
+			installID := ""
+			if len(args) > 0 {
+				installID = args[0]
+			} else {
+				installID, err = pickInstall(cmd.Context(), client, cfg)
+				if err != nil {
+					return err
+				}
+			}
+
+			install, err := fetchInstall(cmd.Context(), client, installID)
+			if err != nil {
+				return err
+			}
+			revs, err := loadRevisions(cfg, install)
+			if err != nil {
+				return err
+			}
+
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+			if format.IsJSON() {
+				if revs == nil {
+					revs = []valuesRevision{}
+				}
+				return output.PrintData(format, revs)
+			}
+
+			if len(revs) == 0 {
+				fmt.Println("No revisions recorded for this install. Snapshots are taken by update-values.")
+				return nil
+			}
+
+			rows := make([][]string, 0, len(revs))
+			for i := len(revs) - 1; i >= 0; i-- {
+				r := revs[i]
+				ids := make([]string, len(r.Sources))
+				for j, s := range r.Sources {
+					ids[j] = s.TemplateHelmSourceId
+				}
+				rows = append(rows, []string{
+					strconv.Itoa(r.Revision),
+					time.Unix(r.CreatedAt, 0).Local().Format("2006-01-02 15:04:05"),
+					r.Command,
+					strings.Join(ids, ", "),
+				})
+			}
+			output.PrintTable([]string{"REVISION", "TAKEN", "BEFORE", "SOURCES"}, rows)
+			return nil
+		},
+	}
+}
+
+func newCmdRollback() *cobra.Command {
+	var to int
+	var yes, wait bool
+
+	cmd := &cobra.Command{
+		Use:   "rollback [install-id]",
+		Short: "Restore values from an earlier snapshot",
+		Long: `Re-applies the helm source values recorded before an earlier
+update-values (see installs revisions). Defaults to the latest snapshot,
+i.e. the values before the most recent update. The current values are
+snapshotted first, so a rollback can itself be rolled back.
+
+With --wait, follows the install's workflow until it finishes.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !prompt.IsInteractive() {
+				return fmt.Errorf("<install-id> argument required when not running interactively")
+			}
+
+			client, cfg, err := cmdutil.NewClient()
+			if err != nil {
+				return err
+			}
+
+			installID := ""
+			if len(args) > 0 {
+				installID = args[0]
+			} else {
+				installID, err = pickInstall(cmd.Context(), client, cfg)
+				if err != nil {
+					return err
+				}
+			}
+
+			install, err := fetchInstall(cmd.Context(), client, installID)
+			if err != nil {
+				return err
+			}
+			revs, err := loadRevisions(cfg, install)
+			if err != nil {
+				return err
+			}
+			if len(revs) == 0 {
+				return fmt.Errorf("no revisions recorded for install %s; snapshots are taken by update-values", installID)
+			}
+			target := revs[len(revs)-1]
+			if to != 0 {
+				found := false
+				for _, r := range revs {
+					if r.Revision == to {
+						target, found = r, true
+					}
+				}
+				if !found {
+					return fmt.Errorf("revision %d not found; run: cnap installs revisions %s", to, installID)
+				}
+			}
+
+			if !yes {
+				if !prompt.IsInteractive() {
+					return fmt.Errorf("use --yes to confirm rollback in non-interactive mode")
+				}
+				confirmed, err := prompt.Confirm(fmt.Sprintf("Roll back %s to revision %d (before %s, %s)?",
+					displayName(installID), target.Revision, target.Command,
+					time.Unix(target.CreatedAt, 0).Local().Format("2006-01-02 15:04")))
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Println("Cancelled.")
+					return nil
+				}
+			}
+
+			if err := recordRevision(cmd.Context(), client, cfg, install, fmt.Sprintf("rollback to %d", target.Revision)); err != nil {
+				return fmt.Errorf("snapshotting current values: %w", err)
+			}
+
+			body := api.PatchV1InstallsIdValuesJSONRequestBody{Updates: target.Sources}
+			resp, err := client.PatchV1InstallsIdValuesWithResponse(cmd.Context(), installID, body)
+			if err != nil {
+				return fmt.Errorf("rolling back install values: %w", err)
+			}
+			job, ok := cmdutil.Succeeded(resp.StatusCode(), resp.Body, cmdutil.AsyncStatuses...)
+			if !ok {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404, resp.JSON422)
+			}
+
+			fmt.Printf("Rollback to revision %d started%s.\n", target.Revision, cmdutil.QueuedAs(job))
+			if !wait {
+				return nil
+			}
+			return waitForWorkflow(cmd.Context(), client, installID)
+		},
+	}
+
+	cmd.Flags().IntVar(&to, "to", 0, "Revision to restore (default: the latest)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation")
+	cmd.Flags().BoolVar(&wait, "wait", false, "Wait for the install workflow to finish")
+
+	return cmd
+}
+
+// waitPoll is how often --wait checks the install status: every 3s at
+// first, backing off to 15s for long-running workflows.
+var waitPoll = cmdutil.PollStrategy{Interval: 3 * time.Second, MaxInterval: 15 * time.Second, Factor: 1.5, Jitter: 0.1}
+
+// waitStartGrace is how long --wait accepts a finished workflow without
+// having seen a new one start: right after an update the status can still
+// describe the previous run.
+var waitStartGrace = 30 * time.Second
+
+// waitForWorkflow polls the install status until its workflow finishes,
+// returning an error if it failed.
+func waitForWorkflow(ctx context.Context, client *api.ClientWithResponses, installID string) error {
+	start := time.Now()
+	started := false
+	last := ""
+
+	poll := waitPoll
+	return cmdutil.PollUntil(ctx, &poll, func(ctx context.Context) (bool, error) {
+		resp, err := client.GetV1InstallsIdStatusWithResponse(ctx, installID)
+		if err != nil {
+			return false, fmt.Errorf("fetching install status: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return false, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+		}
+		status := resp.JSON200
+		if line := formatInstallStatus(status); line != last {
+			_, _ = fmt.Fprintln(os.Stderr, line)
+			last = line
+		}
+
+		switch strings.ToLower(status.Phase) {
+		case "succeeded":
+			if started || time.Since(start) > waitStartGrace {
+				fmt.Println("Install workflow succeeded.")
+				return true, nil
+			}
+		case "failed", "error":
+			if started || time.Since(start) > waitStartGrace {
+				return false, fmt.Errorf("install workflow %s: %s", strings.ToLower(status.Phase), deref(status.Message))
+			}
+		default:
+			started = true
+		}
+		return false, nil
+	})
+}
@@ -0,0 +1,279 @@
+package installs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+// workload is a scalable controller of an install's pods.
+type workload struct {
+	Kind      string `json:"kind"` // "deployment" or "statefulset"
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Replicas  int    `json:"replicas"`
+}
+
+func (w workload) ref() string {
+	return w.Kind + "/" + w.Name
+}
+
+// scaleResult is the JSON output of scale.
+type scaleResult struct {
+	workload
+	Previous int `json:"previous_replicas"`
+}
+
+func newCmdScale() *cobra.Command {
+	var workloadName string
+	var replicas int
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "scale [install-id] --replicas N",
+		Short: "Set the replica count of an install's workload",
+		Long: `Scales a Deployment or StatefulSet of the install, like kubectl scale.
+It goes through the cluster's admin kubeconfig, so it's available for
+KaaS-managed clusters. Only workloads in the install's namespace are
+considered.
+
+--workload takes the workload's name, or kind/name (e.g. statefulset/db)
+when a Deployment and a StatefulSet share it. When omitted, it's picked
+interactively. Scaling to 0 asks for confirmation unless --yes is passed.
+
+The replica count is changed on the cluster, not in the install's values:
+the next update-values or redeploy resets it to the chart's setting.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if replicas < 0 {
+				return fmt.Errorf("--replicas must be 0 or more, got %d", replicas)
+			}
+			if len(args) == 0 && !prompt.IsInteractive() {
+				return fmt.Errorf("<install-id> argument required when not running interactively")
+			}
+
+			client, cfg, err := cmdutil.NewClient()
+			if err != nil {
+				return err
+			}
+
+			installID := ""
+			if len(args) > 0 {
+				installID = args[0]
+			} else {
+				installID, err = pickInstall(cmd.Context(), client, cfg)
+				if err != nil {
+					return err
+				}
+			}
+
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+
+			resp, err := client.GetV1InstallsIdWithResponse(cmd.Context(), installID)
+			if err != nil {
+				return fmt.Errorf("fetching install: %w", err)
+			}
+			if resp.JSON200 == nil {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+			}
+			kube, err := newKubeClient(cmd.Context(), client, resp.JSON200.ClusterId)
+			if err != nil {
+				return err
+			}
+			workloads, err := installWorkloads(cmd.Context(), client, kube, resp.JSON200)
+			if err != nil {
+				return err
+			}
+
+			w, err := selectWorkload(workloads, workloadName)
+			if err != nil {
+				return err
+			}
+
+			if w.Replicas == replicas {
+				if format.IsJSON() {
+					return output.PrintData(format, scaleResult{workload: w, Previous: w.Replicas})
+				}
+				fmt.Printf("%s already has %d replicas.\n", w.ref(), replicas)
+				return nil
+			}
+
+			if replicas == 0 && !yes {
+				if !prompt.IsInteractive() {
+					return fmt.Errorf("use --yes to confirm scaling to 0 in non-interactive mode")
+				}
+				confirmed, err := prompt.Confirm(fmt.Sprintf("Scale %s of %s to 0, stopping all its pods?", w.ref(), displayName(installID)))
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Println("Cancelled.")
+					return nil
+				}
+			}
+
+			scaled, err := kube.scale(cmd.Context(), w, replicas)
+			if err != nil {
+				return err
+			}
+
+			if format.IsJSON() {
+				return output.PrintData(format, scaleResult{workload: scaled, Previous: w.Replicas})
+			}
+			fmt.Printf("Scaled %s from %d to %d replicas.\n", w.ref(), w.Replicas, scaled.Replicas)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&workloadName, "workload", "", "Deployment or StatefulSet name (kind/name if ambiguous)")
+	cmd.Flags().IntVar(&replicas, "replicas", 0, "Desired number of replicas (required)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation when scaling to 0")
+	_ = cmd.MarkFlagRequired("replicas")
+
+	return cmd
+}
+
+// selectWorkload finds the workload named by name ("web" or
+// "deployment/web"), or lets the user pick one when name is empty.
+func selectWorkload(workloads []workload, name string) (workload, error) {
+	refs := make([]string, len(workloads))
+	for i, w := range workloads {
+		refs[i] = w.ref()
+	}
+
+	if name == "" {
+		if !prompt.IsInteractive() {
+			return workload{}, fmt.Errorf("--workload required when not running interactively (workloads: %s)", strings.Join(refs, ", "))
+		}
+		options := make([]prompt.SelectOption, len(workloads))
+		for i, w := range workloads {
+			options[i] = prompt.SelectOption{Label: fmt.Sprintf("%s (%d replicas)", w.ref(), w.Replicas), Value: strconv.Itoa(i)}
+		}
+		choice, err := prompt.Select("Select a workload", options)
+		if err != nil {
+			return workload{}, err
+		}
+		i, _ := strconv.Atoi(choice)
+		return workloads[i], nil
+	}
+
+	var matches []workload
+	for _, w := range workloads {
+		if w.Name == name || w.ref() == strings.ToLower(name) {
+			matches = append(matches, w)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return workload{}, fmt.Errorf("workload %q not found (workloads: %s)", name, strings.Join(refs, ", "))
+	case 1:
+		return matches[0], nil
+	default:
+		return workload{}, fmt.Errorf("%q is ambiguous: use %s or %s", name, matches[0].ref(), matches[1].ref())
+	}
+}
+
+// workloadList is the part of an apps/v1 DeploymentList or StatefulSetList
+// that scale uses.
+type workloadList struct {
+	Items []struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Spec struct {
+			Replicas *int `json:"replicas"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+// installWorkloads returns the Deployments and StatefulSets in the
+// install's namespace. The whole namespace is included so workloads
+// scaled to 0 can be found.
+func installWorkloads(ctx context.Context, client *api.ClientWithResponses, kube *kubeClient, install *api.Install) ([]workload, error) {
+	namespace, err := installNamespace(ctx, client, kube, install)
+	if err != nil {
+		return nil, err
+	}
+
+	var workloads []workload
+	for _, kind := range []string{"deployment", "statefulset"} {
+		list, err := kube.listWorkloads(ctx, namespace, kind)
+		if err != nil {
+			return nil, err
+		}
+		workloads = append(workloads, list...)
+	}
+	if len(workloads) == 0 {
+		return nil, fmt.Errorf("no Deployments or StatefulSets found for install %s in namespace %s", install.Id, namespace)
+	}
+	sort.SliceStable(workloads, func(i, j int) bool { return workloads[i].Name < workloads[j].Name })
+	return workloads, nil
+}
+
+func (c *kubeClient) listWorkloads(ctx context.Context, namespace, kind string) ([]workload, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/apis/apps/v1/namespaces/"+url.PathEscape(namespace)+"/"+kind+"s", nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing %ss: %w", kind, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("listing %ss: %w", kind, kubeError(resp))
+	}
+
+	var list workloadList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("decoding %ss: %w", kind, err)
+	}
+	workloads := make([]workload, len(list.Items))
+	for i, item := range list.Items {
+		workloads[i] = workload{Kind: kind, Namespace: item.Metadata.Namespace, Name: item.Metadata.Name, Replicas: 1}
+		if item.Spec.Replicas != nil {
+			workloads[i].Replicas = *item.Spec.Replicas
+		}
+	}
+	return workloads, nil
+}
+
+// scale sets w's replicas through its scale subresource and returns w
+// with the replica count the cluster reports back.
+func (c *kubeClient) scale(ctx context.Context, w workload, replicas int) (workload, error) {
+	body, err := json.Marshal(map[string]any{"spec": map[string]any{"replicas": replicas}})
+	if err != nil {
+		return w, err
+	}
+	path := fmt.Sprintf("/apis/apps/v1/namespaces/%s/%ss/%s/scale", url.PathEscape(w.Namespace), w.Kind, url.PathEscape(w.Name))
+	resp, err := c.do(ctx, http.MethodPatch, path, body)
+	if err != nil {
+		return w, fmt.Errorf("scaling %s: %w", w.ref(), err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return w, fmt.Errorf("scaling %s: %w", w.ref(), kubeError(resp))
+	}
+
+	var scale struct {
+		Spec struct {
+			Replicas int `json:"replicas"`
+		} `json:"spec"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&scale); err != nil {
+		return w, fmt.Errorf("decoding scale: %w", err)
+	}
+	w.Replicas = scale.Spec.Replicas
+	return w, nil
+}
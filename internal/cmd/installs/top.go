@@ -0,0 +1,162 @@
+package installs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+// topWatchInterval is how often `top --watch` refreshes. metrics-server
+// scrapes every 15s by default, so polling faster shows nothing new.
+const topWatchInterval = 15 * time.Second
+
+func newCmdTop() *cobra.Command {
+	var watch bool
+
+	cmd := &cobra.Command{
+		Use:   "top [install-id]",
+		Short: "Show CPU and memory usage of an install's pods",
+		Long: `Shows current CPU and memory usage per pod container, highest CPU first.
+
+Usage comes from the cluster's metrics-server, queried in the install's
+namespace with the cluster's admin kubeconfig, so it's available for KaaS-managed clusters that run
+metrics-server. With --watch, refreshes every 15 seconds.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !prompt.IsInteractive() {
+				return fmt.Errorf("<install-id> argument required when not running interactively")
+			}
+
+			client, cfg, err := cmdutil.NewClient()
+			if err != nil {
+				return err
+			}
+
+			installID := ""
+			if len(args) > 0 {
+				installID = args[0]
+			} else {
+				installID, err = pickInstall(cmd.Context(), client, cfg)
+				if err != nil {
+					return err
+				}
+			}
+
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+
+			resp, err := client.GetV1InstallsIdWithResponse(cmd.Context(), installID)
+			if err != nil {
+				return fmt.Errorf("fetching install: %w", err)
+			}
+			if resp.JSON200 == nil {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+			}
+			metrics, err := newKubeClient(cmd.Context(), client, resp.JSON200.ClusterId)
+			if err != nil {
+				return fmt.Errorf("%w: %w", errNoMetrics, err)
+			}
+			namespace, err := installNamespace(cmd.Context(), client, metrics, resp.JSON200)
+			if err != nil {
+				return err
+			}
+
+			if !watch {
+				usage, err := fetchUsage(cmd.Context(), client, metrics, installID, namespace)
+				if err != nil {
+					return err
+				}
+				return printUsage(usage, format)
+			}
+			return watchUsage(cmd.Context(), client, metrics, installID, namespace, format)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "Refresh usage periodically")
+
+	return cmd
+}
+
+// fetchUsage lists the install's pods and returns their usage from the
+// install's namespace, sorted by CPU then memory, descending.
+func fetchUsage(ctx context.Context, client *api.ClientWithResponses, metrics *kubeClient, installID, namespace string) ([]containerUsage, error) {
+	resp, err := client.GetV1InstallsIdPodsWithResponse(ctx, installID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching pods: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+	}
+	pods := make([]string, len(resp.JSON200.Data))
+	for i, p := range resp.JSON200.Data {
+		pods[i] = p.Name
+	}
+
+	usage, err := metrics.podUsage(ctx, namespace, pods)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(usage, func(i, j int) bool {
+		if usage[i].CPUMillis != usage[j].CPUMillis {
+			return usage[i].CPUMillis > usage[j].CPUMillis
+		}
+		return usage[i].MemoryBytes > usage[j].MemoryBytes
+	})
+	return usage, nil
+}
+
+func printUsage(usage []containerUsage, format output.Format) error {
+	if format.IsJSON() {
+		if usage == nil {
+			usage = []containerUsage{}
+		}
+		return output.PrintData(format, usage)
+	}
+	if len(usage) == 0 {
+		fmt.Println("No metrics reported for this install's pods yet. metrics-server may still be collecting; try again in a minute.")
+		return nil
+	}
+
+	rows := make([][]string, len(usage))
+	for i, u := range usage {
+		rows[i] = []string{u.Pod, u.Container, fmt.Sprintf("%dm", u.CPUMillis), formatMemory(u.MemoryBytes)}
+	}
+	output.PrintTable([]string{"POD", "CONTAINER", "CPU", "MEMORY"}, rows)
+	return nil
+}
+
+// watchUsage reprints usage every topWatchInterval until the context is
+// cancelled.
+func watchUsage(ctx context.Context, client *api.ClientWithResponses, metrics *kubeClient, installID, namespace string, format output.Format) error {
+	fetch := func() ([]containerUsage, error) {
+		usage, err := fetchUsage(ctx, client, metrics, installID, namespace)
+		if usage == nil {
+			usage = []containerUsage{}
+		}
+		return usage, err
+	}
+	return watch(ctx, topWatchInterval, format, fetch, func(usage []containerUsage) {
+		_ = printUsage(usage, format)
+	})
+}
+
+// formatMemory renders bytes in the binary units kubectl top uses.
+func formatMemory(b int64) string {
+	switch {
+	case b >= 1<<30:
+		return fmt.Sprintf("%.1fGi", float64(b)/(1<<30))
+	case b >= 1<<20:
+		return fmt.Sprintf("%dMi", b>>20)
+	default:
+		return fmt.Sprintf("%dKi", b>>10)
+	}
+}
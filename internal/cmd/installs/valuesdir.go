@@ -0,0 +1,120 @@
+package installs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/output"
+)
+
+// sourceValues is the per-source element of the multi-source values request
+// bodies (create overrides, update-values, update-overrides).
+type sourceValues = struct {
+	TemplateHelmSourceId string                  `json:"template_helm_source_id"`
+	Values               map[string]*interface{} `json:"values"`
+}
+
+// valuesDirFile is one values file from a --from-values-dir directory.
+type valuesDirFile struct {
+	SourceID string
+	Path     string
+	Values   map[string]*interface{}
+}
+
+// readValuesDir reads every *.yaml / *.yml file in dir, using the file name
+// without its extension as the helm source ID. Other files are skipped.
+func readValuesDir(dir string) ([]valuesDirFile, error) {
+	dir, err := cmdutil.ExpandPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading values directory: %w", err)
+	}
+
+	var files []valuesDirFile
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		values, err := readValuesFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		files = append(files, valuesDirFile{
+			SourceID: strings.TrimSuffix(e.Name(), ext),
+			Path:     path,
+			Values:   values,
+		})
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .yaml files found in %s", dir)
+	}
+	return files, nil
+}
+
+// fetchHelmSources returns the helm sources of a template.
+func fetchHelmSources(ctx context.Context, client *api.ClientWithResponses, templateID string) ([]api.HelmSource, error) {
+	resp, err := client.GetV1TemplatesIdWithResponse(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching template: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+	}
+	return resp.JSON200.HelmSources, nil
+}
+
+// matchValuesDir checks every file maps to one of the template's helm
+// sources and builds the request updates.
+func matchValuesDir(files []valuesDirFile, sources []api.HelmSource) ([]sourceValues, error) {
+	known := make([]string, len(sources))
+	for i, s := range sources {
+		known[i] = s.Id
+	}
+
+	updates := make([]sourceValues, 0, len(files))
+	for _, f := range files {
+		if !slices.Contains(known, f.SourceID) {
+			return nil, fmt.Errorf("%s: unknown helm source %q (template sources: %s)",
+				filepath.Base(f.Path), f.SourceID, strings.Join(known, ", "))
+		}
+		updates = append(updates, sourceValues{TemplateHelmSourceId: f.SourceID, Values: f.Values})
+	}
+	return updates, nil
+}
+
+// printValuesDirPlan shows which file goes to which helm source (--dry-run).
+func printValuesDirPlan(files []valuesDirFile, sources []api.HelmSource) {
+	charts := make(map[string]string, len(sources))
+	for _, s := range sources {
+		charts[s.Id] = chartLabel(s.Chart)
+	}
+
+	header := []string{"SOURCE", "CHART", "FILE", "KEYS"}
+	var rows [][]string
+	for _, f := range files {
+		rows = append(rows, []string{f.SourceID, charts[f.SourceID], f.Path, fmt.Sprintf("%d", len(f.Values))})
+	}
+	output.PrintTable(header, rows)
+}
+
+func chartLabel(c api.HelmSourceChart) string {
+	name := c.RepoUrl
+	switch {
+	case c.Chart != nil && *c.Chart != "":
+		name = *c.Chart
+	case c.Path != nil && *c.Path != "":
+		name = *c.Path
+	}
+	return name + "@" + c.TargetRevision
+}
@@ -0,0 +1,118 @@
+package installs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+func newCmdValuesSchema() *cobra.Command {
+	var sourceID string
+	var defaults bool
+
+	cmd := &cobra.Command{
+		Use:   "values-schema [install-id]",
+		Short: "Show the values a helm source's chart accepts",
+		Long: `Shows the values.schema.json of the chart behind one of the install's
+helm sources, as YAML, so you know which keys update-values, diff and
+--from-values-dir files can set. --source can be left out when the
+template has a single helm source.
+
+Charts without a schema show their default values.yaml instead; pass
+--defaults to always show it. With -o json both are printed, with the
+schema null if the chart has none.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !prompt.IsInteractive() {
+				return fmt.Errorf("<install-id> argument required when not running interactively")
+			}
+
+			client, cfg, err := cmdutil.NewClient()
+			if err != nil {
+				return err
+			}
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+
+			installID := ""
+			if len(args) > 0 {
+				installID = args[0]
+			} else {
+				installID, err = pickInstall(cmd.Context(), client, cfg)
+				if err != nil {
+					return err
+				}
+			}
+
+			resp, err := client.GetV1InstallsIdWithResponse(cmd.Context(), installID)
+			if err != nil {
+				return fmt.Errorf("fetching install: %w", err)
+			}
+			if resp.JSON200 == nil {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
+			}
+			if resp.JSON200.TemplateId == nil {
+				return fmt.Errorf("install %s has no template", installID)
+			}
+			templateID := *resp.JSON200.TemplateId
+			sources, err := fetchHelmSources(cmd.Context(), client, templateID)
+			if err != nil {
+				return err
+			}
+			source, err := findSource(sources, sourceID)
+			if err != nil {
+				return err
+			}
+
+			chartResp, err := client.GetV1TemplatesIdHelmSourcesSourceIdChartWithResponse(cmd.Context(), templateID, source.Id)
+			if err != nil {
+				return fmt.Errorf("fetching chart: %w", err)
+			}
+			if chartResp.JSON200 == nil {
+				return cmdutil.APIError(chartResp.Status(), chartResp.Body, chartResp.JSON401, chartResp.JSON404, chartResp.JSON502)
+			}
+			chart := chartResp.JSON200
+
+			if format.IsJSON() {
+				return output.PrintData(format, chart)
+			}
+			if !defaults && chart.ValuesSchema == nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Chart %s has no values.schema.json; showing its default values.\n", chartName(chart.Chart.Chart, chart.Chart.Path))
+				defaults = true
+			}
+			if defaults {
+				fmt.Print(chart.DefaultValues)
+				return nil
+			}
+			schema, err := valuesYAML(chart.ValuesSchema)
+			if err != nil {
+				return err
+			}
+			fmt.Print(schema)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&sourceID, "source", "", "Helm source ID")
+	cmd.Flags().BoolVar(&defaults, "defaults", false, "Show the chart's default values.yaml instead of its schema")
+
+	return cmd
+}
+
+// chartName names a helm source's chart for messages: the chart in its
+// repository, else its path.
+func chartName(chart, path *string) string {
+	switch {
+	case chart != nil && *chart != "":
+		return *chart
+	case path != nil && *path != "":
+		return *path
+	}
+	return "(unnamed)"
+}
@@ -0,0 +1,46 @@
+package installs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/output"
+	"golang.org/x/term"
+)
+
+// watch fetches and prints a value now and then every interval until the
+// context is cancelled (Ctrl-C), which ends the watch without an error.
+// On a terminal the screen is cleared before each refresh, under a header
+// with the interval and time; otherwise refreshes are separated by a blank
+// line. JSON output is one compact value per line.
+func watch[T any](ctx context.Context, interval time.Duration, format output.Format, fetch func() (T, error), print func(T)) error {
+	clearScreen := term.IsTerminal(int(os.Stdout.Fd())) && !format.IsJSON()
+	interval = max(interval, cmdutil.Current().Poll.Interval)
+
+	err := cmdutil.PollUntil(ctx, &cmdutil.PollStrategy{Interval: interval, Fixed: true}, func(context.Context) (bool, error) {
+		v, err := fetch()
+		if err != nil {
+			return false, err
+		}
+
+		switch {
+		case format.IsJSON():
+			_ = output.EncodeData(os.Stdout, format, v)
+		case clearScreen:
+			fmt.Print("\033[H\033[2J")
+			fmt.Printf("Every %s, updated %s\n\n", interval, time.Now().Format("15:04:05"))
+			print(v)
+		default:
+			print(v)
+			fmt.Println()
+		}
+		return false, nil
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -3,10 +3,10 @@ package products
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/cnap-tech/cli/internal/api"
 	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/config"
 	"github.com/cnap-tech/cli/internal/output"
 	"github.com/cnap-tech/cli/internal/prompt"
 	"github.com/spf13/cobra"
@@ -29,6 +29,7 @@ func NewCmdProducts() *cobra.Command {
 func newCmdList() *cobra.Command {
 	var limit int
 	var cursor string
+	var all bool
 
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -40,49 +41,55 @@ func newCmdList() *cobra.Command {
 				return err
 			}
 
-			if cfg.ActiveWorkspace == "" {
-				return fmt.Errorf("no active workspace. Run: cnap workspaces switch <id>")
+			if err := cmdutil.RequireWorkspace(cfg); err != nil {
+				return err
 			}
 
-			params := &api.GetV1ProductsParams{Limit: &limit}
-			if cursor != "" {
-				params.Cursor = &cursor
+			items, page, listErr := cmdutil.ListPages(cursor, all, func(cursor *string) ([]api.Product, *api.Pagination, error) {
+				resp, err := client.GetV1ProductsWithResponse(cmd.Context(), &api.GetV1ProductsParams{Limit: &limit, Cursor: cursor})
+				if err != nil {
+					return nil, nil, fmt.Errorf("fetching products: %w", err)
+				}
+				if resp.JSON200 == nil {
+					return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
+				}
+				return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+			})
+			if listErr != nil && !cmdutil.IsPartial(listErr) {
+				return listErr
 			}
 
-			resp, err := client.GetV1ProductsWithResponse(cmd.Context(), params)
+			format, err := cmdutil.GetOutputFormat(cfg)
 			if err != nil {
-				return fmt.Errorf("fetching products: %w", err)
-			}
-			if resp.JSON200 == nil {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON403)
+				return err
 			}
-
-			format := cmdutil.GetOutputFormat(cfg)
-			if format == output.FormatJSON {
-				return output.PrintJSON(resp.JSON200)
+			if format.IsJSON() {
+				if err := output.PrintData(format, cmdutil.NewListOutput(items, page)); err != nil {
+					return err
+				}
+				return listErr
 			}
 
-			if len(resp.JSON200.Data) == 0 {
+			if len(items) == 0 {
 				fmt.Println("No products found in this workspace.")
-				return nil
+				return listErr
 			}
 
 			header := []string{"ID", "NAME", "TEMPLATE", "CREATED"}
 			var rows [][]string
-			for _, p := range resp.JSON200.Data {
+			for _, p := range items {
 				rows = append(rows, []string{p.Id, p.Name, p.TemplateId, formatTime(p.CreatedAt)})
 			}
 
 			output.PrintTable(header, rows)
-			if resp.JSON200.Pagination.HasMore {
-				fmt.Printf("\nMore results available. Use --cursor %s to see next page.\n", *resp.JSON200.Pagination.Cursor)
-			}
-			return nil
+			cmdutil.PrintPageHint(format, page)
+			return listErr
 		},
 	}
 
 	cmd.Flags().IntVar(&limit, "limit", 50, "Items per page (1-100)")
 	cmd.Flags().StringVar(&cursor, "cursor", "", "Pagination cursor from previous response")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch every page (--limit sets the page size)")
 
 	return cmd
 }
@@ -106,7 +113,7 @@ func newCmdGet() *cobra.Command {
 			if len(args) > 0 {
 				productID = args[0]
 			} else {
-				productID, err = pickProduct(cmd.Context(), client)
+				productID, err = pickProduct(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
@@ -117,12 +124,15 @@ func newCmdGet() *cobra.Command {
 				return fmt.Errorf("fetching product: %w", err)
 			}
 			if resp.JSON200 == nil {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON404)
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
 			}
 
-			format := cmdutil.GetOutputFormat(cfg)
-			if format == output.FormatJSON {
-				return output.PrintJSON(resp.JSON200)
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+			if format.IsJSON() {
+				return output.PrintData(format, resp.JSON200)
 			}
 
 			p := resp.JSON200
@@ -154,7 +164,7 @@ func newCmdDelete() *cobra.Command {
 				return fmt.Errorf("<product-id> argument required when not running interactively")
 			}
 
-			client, _, err := cmdutil.NewClient()
+			client, cfg, err := cmdutil.NewClient()
 			if err != nil {
 				return err
 			}
@@ -163,7 +173,7 @@ func newCmdDelete() *cobra.Command {
 			if len(args) > 0 {
 				productID = args[0]
 			} else {
-				productID, err = pickProduct(cmd.Context(), client)
+				productID, err = pickProduct(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
@@ -187,11 +197,12 @@ func newCmdDelete() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("deleting product: %w", err)
 			}
-			if resp.HTTPResponse.StatusCode != 204 {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON404, resp.JSON409)
+			job, ok := cmdutil.Succeeded(resp.StatusCode(), resp.Body, cmdutil.DeleteStatuses...)
+			if !ok {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404, resp.JSON409)
 			}
 
-			fmt.Printf("Product %s deleted.\n", productID)
+			cmdutil.PrintDeleted("Product "+productID, job)
 			return nil
 		},
 	}
@@ -202,14 +213,18 @@ func newCmdDelete() *cobra.Command {
 }
 
 // pickProduct shows an interactive product picker. Returns the selected product ID.
-func pickProduct(ctx context.Context, client *api.ClientWithResponses) (string, error) {
+// It lists the active workspace, so it requires one.
+func pickProduct(ctx context.Context, client *api.ClientWithResponses, cfg *config.Config) (string, error) {
+	if err := cmdutil.RequireWorkspace(cfg); err != nil {
+		return "", err
+	}
 	limit := 100
 	listResp, err := client.GetV1ProductsWithResponse(ctx, &api.GetV1ProductsParams{Limit: &limit})
 	if err != nil {
 		return "", fmt.Errorf("fetching products: %w", err)
 	}
 	if listResp.JSON200 == nil {
-		return "", apiError(listResp.Status(), listResp.JSON401, listResp.JSON403)
+		return "", cmdutil.APIError(listResp.Status(), listResp.Body, listResp.JSON401, listResp.JSON403)
 	}
 	if len(listResp.JSON200.Data) == 0 {
 		return "", fmt.Errorf("no products found in this workspace")
@@ -218,20 +233,7 @@ func pickProduct(ctx context.Context, client *api.ClientWithResponses) (string,
 	for i, p := range listResp.JSON200.Data {
 		options[i] = prompt.SelectOption{Label: p.Name + " (" + p.Id + ")", Value: p.Id}
 	}
-	return prompt.Select("Select a product", options)
-}
-
-func apiError(status string, errs ...*api.Error) error {
-	for _, e := range errs {
-		if e != nil {
-			parts := []string{e.Error.Message}
-			if e.Error.Suggestion != nil {
-				parts = append(parts, *e.Error.Suggestion)
-			}
-			return fmt.Errorf("%s", strings.Join(parts, ". "))
-		}
-	}
-	return fmt.Errorf("unexpected response: %s", status)
+	return prompt.Select("Select a product", options, prompt.WithCancel())
 }
 
 func formatTime(ts float32) string {
@@ -2,7 +2,6 @@ package regions
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/cnap-tech/cli/internal/api"
 	"github.com/cnap-tech/cli/internal/cmdutil"
@@ -26,6 +25,7 @@ func NewCmdRegions() *cobra.Command {
 func newCmdList() *cobra.Command {
 	var limit int
 	var cursor string
+	var all bool
 
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -37,36 +37,43 @@ func newCmdList() *cobra.Command {
 				return err
 			}
 
-			if cfg.ActiveWorkspace == "" {
-				return fmt.Errorf("no active workspace. Run: cnap workspaces switch <id>")
+			if err := cmdutil.RequireWorkspace(cfg); err != nil {
+				return err
 			}
 
-			params := &api.GetV1RegionsParams{Limit: &limit}
-			if cursor != "" {
-				params.Cursor = &cursor
+			items, page, listErr := cmdutil.ListPages(cursor, all, func(cursor *string) ([]api.Region, *api.Pagination, error) {
+				resp, err := client.GetV1RegionsWithResponse(cmd.Context(), &api.GetV1RegionsParams{Limit: &limit, Cursor: cursor})
+				if err != nil {
+					return nil, nil, fmt.Errorf("fetching regions: %w", err)
+				}
+				if resp.JSON200 == nil {
+					return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
+				}
+				return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+			})
+			if listErr != nil && !cmdutil.IsPartial(listErr) {
+				return listErr
 			}
 
-			resp, err := client.GetV1RegionsWithResponse(cmd.Context(), params)
+			format, err := cmdutil.GetOutputFormat(cfg)
 			if err != nil {
-				return fmt.Errorf("fetching regions: %w", err)
-			}
-			if resp.JSON200 == nil {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON403)
+				return err
 			}
-
-			format := cmdutil.GetOutputFormat(cfg)
-			if format == output.FormatJSON {
-				return output.PrintJSON(resp.JSON200)
+			if format.IsJSON() {
+				if err := output.PrintData(format, cmdutil.NewListOutput(items, page)); err != nil {
+					return err
+				}
+				return listErr
 			}
 
-			if len(resp.JSON200.Data) == 0 {
+			if len(items) == 0 {
 				fmt.Println("No regions found in this workspace.")
-				return nil
+				return listErr
 			}
 
 			header := []string{"ID", "NAME", "ICON"}
 			var rows [][]string
-			for _, r := range resp.JSON200.Data {
+			for _, r := range items {
 				icon := "-"
 				if r.Icon != nil {
 					icon = *r.Icon
@@ -75,15 +82,14 @@ func newCmdList() *cobra.Command {
 			}
 
 			output.PrintTable(header, rows)
-			if resp.JSON200.Pagination.HasMore {
-				fmt.Printf("\nMore results available. Use --cursor %s to see next page.\n", *resp.JSON200.Pagination.Cursor)
-			}
-			return nil
+			cmdutil.PrintPageHint(format, page)
+			return listErr
 		},
 	}
 
 	cmd.Flags().IntVar(&limit, "limit", 50, "Items per page (1-100)")
 	cmd.Flags().StringVar(&cursor, "cursor", "", "Pagination cursor from previous response")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch every page (--limit sets the page size)")
 
 	return cmd
 }
@@ -104,8 +110,8 @@ func newCmdCreate() *cobra.Command {
 				return err
 			}
 
-			if cfg.ActiveWorkspace == "" {
-				return fmt.Errorf("no active workspace. Run: cnap workspaces switch <id>")
+			if err := cmdutil.RequireWorkspace(cfg); err != nil {
+				return err
 			}
 
 			body := api.PostV1RegionsJSONRequestBody{
@@ -120,12 +126,15 @@ func newCmdCreate() *cobra.Command {
 				return fmt.Errorf("creating region: %w", err)
 			}
 			if resp.JSON201 == nil {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON403, resp.JSON422)
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403, resp.JSON422)
 			}
 
-			format := cmdutil.GetOutputFormat(cfg)
-			if format == output.FormatJSON {
-				return output.PrintJSON(resp.JSON201)
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+			if format.IsJSON() {
+				return output.PrintData(format, resp.JSON201)
 			}
 
 			fmt.Printf("Region %s created (%s).\n", resp.JSON201.Name, resp.JSON201.Id)
@@ -139,16 +148,3 @@ func newCmdCreate() *cobra.Command {
 
 	return cmd
 }
-
-func apiError(status string, errs ...*api.Error) error {
-	for _, e := range errs {
-		if e != nil {
-			parts := []string{e.Error.Message}
-			if e.Error.Suggestion != nil {
-				parts = append(parts, *e.Error.Suggestion)
-			}
-			return fmt.Errorf("%s", strings.Join(parts, ". "))
-		}
-	}
-	return fmt.Errorf("unexpected response: %s", status)
-}
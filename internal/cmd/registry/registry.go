@@ -3,10 +3,10 @@ package registry
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/cnap-tech/cli/internal/api"
 	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/config"
 	"github.com/cnap-tech/cli/internal/output"
 	"github.com/cnap-tech/cli/internal/prompt"
 	"github.com/spf13/cobra"
@@ -28,6 +28,7 @@ func NewCmdRegistry() *cobra.Command {
 func newCmdList() *cobra.Command {
 	var limit int
 	var cursor string
+	var all bool
 
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -39,36 +40,43 @@ func newCmdList() *cobra.Command {
 				return err
 			}
 
-			if cfg.ActiveWorkspace == "" {
-				return fmt.Errorf("no active workspace. Run: cnap workspaces switch <id>")
+			if err := cmdutil.RequireWorkspace(cfg); err != nil {
+				return err
 			}
 
-			params := &api.GetV1RegistryCredentialsParams{Limit: &limit}
-			if cursor != "" {
-				params.Cursor = &cursor
+			items, page, listErr := cmdutil.ListPages(cursor, all, func(cursor *string) ([]api.RegistryCredential, *api.Pagination, error) {
+				resp, err := client.GetV1RegistryCredentialsWithResponse(cmd.Context(), &api.GetV1RegistryCredentialsParams{Limit: &limit, Cursor: cursor})
+				if err != nil {
+					return nil, nil, fmt.Errorf("fetching registry credentials: %w", err)
+				}
+				if resp.JSON200 == nil {
+					return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
+				}
+				return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+			})
+			if listErr != nil && !cmdutil.IsPartial(listErr) {
+				return listErr
 			}
 
-			resp, err := client.GetV1RegistryCredentialsWithResponse(cmd.Context(), params)
+			format, err := cmdutil.GetOutputFormat(cfg)
 			if err != nil {
-				return fmt.Errorf("fetching registry credentials: %w", err)
-			}
-			if resp.JSON200 == nil {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON403)
+				return err
 			}
-
-			format := cmdutil.GetOutputFormat(cfg)
-			if format == output.FormatJSON {
-				return output.PrintJSON(resp.JSON200)
+			if format.IsJSON() {
+				if err := output.PrintData(format, cmdutil.NewListOutput(items, page)); err != nil {
+					return err
+				}
+				return listErr
 			}
 
-			if len(resp.JSON200.Data) == 0 {
+			if len(items) == 0 {
 				fmt.Println("No registry credentials found in this workspace.")
-				return nil
+				return listErr
 			}
 
 			header := []string{"ID", "NAME", "REGISTRY", "TYPE", "ACTIVE"}
 			var rows [][]string
-			for _, c := range resp.JSON200.Data {
+			for _, c := range items {
 				active := "yes"
 				if !c.IsActive {
 					active = "no"
@@ -77,15 +85,14 @@ func newCmdList() *cobra.Command {
 			}
 
 			output.PrintTable(header, rows)
-			if resp.JSON200.Pagination.HasMore {
-				fmt.Printf("\nMore results available. Use --cursor %s to see next page.\n", *resp.JSON200.Pagination.Cursor)
-			}
-			return nil
+			cmdutil.PrintPageHint(format, page)
+			return listErr
 		},
 	}
 
 	cmd.Flags().IntVar(&limit, "limit", 50, "Items per page (1-100)")
 	cmd.Flags().StringVar(&cursor, "cursor", "", "Pagination cursor from previous response")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch every page (--limit sets the page size)")
 
 	return cmd
 }
@@ -102,7 +109,7 @@ func newCmdDelete() *cobra.Command {
 				return fmt.Errorf("<credential-id> argument required when not running interactively")
 			}
 
-			client, _, err := cmdutil.NewClient()
+			client, cfg, err := cmdutil.NewClient()
 			if err != nil {
 				return err
 			}
@@ -111,7 +118,7 @@ func newCmdDelete() *cobra.Command {
 			if len(args) > 0 {
 				credentialID = args[0]
 			} else {
-				credentialID, err = pickCredential(cmd.Context(), client)
+				credentialID, err = pickCredential(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
@@ -135,11 +142,12 @@ func newCmdDelete() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("deleting credential: %w", err)
 			}
-			if resp.HTTPResponse.StatusCode != 204 {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON404)
+			job, ok := cmdutil.Succeeded(resp.StatusCode(), resp.Body, cmdutil.DeleteStatuses...)
+			if !ok {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
 			}
 
-			fmt.Printf("Registry credential %s deleted.\n", credentialID)
+			cmdutil.PrintDeleted("Registry credential "+credentialID, job)
 			return nil
 		},
 	}
@@ -150,14 +158,18 @@ func newCmdDelete() *cobra.Command {
 }
 
 // pickCredential shows an interactive registry credential picker. Returns the selected credential ID.
-func pickCredential(ctx context.Context, client *api.ClientWithResponses) (string, error) {
+// It lists the active workspace, so it requires one.
+func pickCredential(ctx context.Context, client *api.ClientWithResponses, cfg *config.Config) (string, error) {
+	if err := cmdutil.RequireWorkspace(cfg); err != nil {
+		return "", err
+	}
 	limit := 100
 	listResp, err := client.GetV1RegistryCredentialsWithResponse(ctx, &api.GetV1RegistryCredentialsParams{Limit: &limit})
 	if err != nil {
 		return "", fmt.Errorf("fetching registry credentials: %w", err)
 	}
 	if listResp.JSON200 == nil {
-		return "", apiError(listResp.Status(), listResp.JSON401, listResp.JSON403)
+		return "", cmdutil.APIError(listResp.Status(), listResp.Body, listResp.JSON401, listResp.JSON403)
 	}
 	if len(listResp.JSON200.Data) == 0 {
 		return "", fmt.Errorf("no registry credentials found in this workspace")
@@ -166,18 +178,5 @@ func pickCredential(ctx context.Context, client *api.ClientWithResponses) (strin
 	for i, c := range listResp.JSON200.Data {
 		options[i] = prompt.SelectOption{Label: c.Name + " (" + c.RegistryUrl + ")", Value: c.Id}
 	}
-	return prompt.Select("Select a credential", options)
-}
-
-func apiError(status string, errs ...*api.Error) error {
-	for _, e := range errs {
-		if e != nil {
-			parts := []string{e.Error.Message}
-			if e.Error.Suggestion != nil {
-				parts = append(parts, *e.Error.Suggestion)
-			}
-			return fmt.Errorf("%s", strings.Join(parts, ". "))
-		}
-	}
-	return fmt.Errorf("unexpected response: %s", status)
+	return prompt.Select("Select a credential", options, prompt.WithCancel())
 }
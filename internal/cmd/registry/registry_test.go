@@ -0,0 +1,33 @@
+package registry
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cnap-tech/cli/internal/cmdtest"
+	"github.com/spf13/cobra"
+)
+
+func TestNotFound(t *testing.T) {
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.WriteError(w, http.StatusNotFound, "Registry credential not found")
+	}))
+
+	tests := []struct {
+		name string
+		cmd  func() *cobra.Command
+		args []string
+	}{
+		{"delete", newCmdDelete, []string{"missing", "--yes"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cmdtest.Run(tt.cmd(), tt.args...)
+			if err == nil || !strings.Contains(err.Error(), "Registry credential not found") {
+				t.Errorf("got error %v, want %q", err, "Registry credential not found")
+			}
+		})
+	}
+}
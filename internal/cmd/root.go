@@ -2,13 +2,17 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
+	applycmd "github.com/cnap-tech/cli/internal/cmd/apply"
 	authcmd "github.com/cnap-tech/cli/internal/cmd/auth"
 	clusterscmd "github.com/cnap-tech/cli/internal/cmd/clusters"
+	"github.com/cnap-tech/cli/internal/cmd/configcmd"
+	getcmd "github.com/cnap-tech/cli/internal/cmd/get"
 	installscmd "github.com/cnap-tech/cli/internal/cmd/installs"
 	productscmd "github.com/cnap-tech/cli/internal/cmd/products"
 	regionscmd "github.com/cnap-tech/cli/internal/cmd/regions"
@@ -16,7 +20,12 @@ import (
 	templatescmd "github.com/cnap-tech/cli/internal/cmd/templates"
 	workspacescmd "github.com/cnap-tech/cli/internal/cmd/workspaces"
 	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/config"
 	"github.com/cnap-tech/cli/internal/debug"
+	"github.com/cnap-tech/cli/internal/env"
+	"github.com/cnap-tech/cli/internal/namecache"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/prompt"
 	"github.com/cnap-tech/cli/internal/update"
 	"github.com/cnap-tech/cli/internal/useragent"
 	"github.com/spf13/cobra"
@@ -28,31 +37,49 @@ var (
 )
 
 func Execute(ctx context.Context) error {
-	root := rootCmd()
-
-	// Background update check (gh CLI pattern)
-	updateCh := make(chan *update.ReleaseInfo)
-	go func() {
-		if version == "dev" || !update.ShouldCheckForUpdate() {
-			updateCh <- nil
-			return
-		}
-		checkCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		defer cancel()
-		rel, _ := update.CheckForUpdate(checkCtx, version)
-		updateCh <- rel
-	}()
+	// Started from PersistentPreRunE, once flags like --offline are parsed.
+	var updateCh chan *update.ReleaseInfo
+
+	root := rootCmd(func() {
+		// Background update check (gh CLI pattern)
+		updateCh = make(chan *update.ReleaseInfo, 1)
+		go func() {
+			if version == "dev" || !update.ShouldCheckForUpdate() || machineReadable() {
+				updateCh <- nil
+				return
+			}
+			checkCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+			rel, _ := update.CheckForUpdate(checkCtx, version)
+			updateCh <- rel
+		}()
+	})
 
 	err := root.ExecuteContext(ctx)
+	cmdutil.Teardown()
+	debug.Flush()
+	if t := cmdutil.Current().Timeout; t > 0 && errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s (--timeout): %w", t, err)
+	}
 
-	// Print update notice after command output
+	// Print update notice after command output, unless the command ran a
+	// raw-mode session or was interrupted: the terminal may not be back to
+	// normal, and a user who pressed Ctrl-C wants their prompt back.
+	if updateCh == nil || cmdutil.UsedRawTerminal() || cmdutil.Interrupted(ctx, err) {
+		return err
+	}
 	if newRelease := <-updateCh; newRelease != nil {
 		isHomebrew := update.IsUnderHomebrew()
 		if !isHomebrew || !update.IsRecentRelease(newRelease.PublishedAt) {
-			fmt.Fprintf(os.Stderr, "\nA new release of cnap is available: %s → %s\n",
+			kind := "release"
+			if newRelease.Prerelease {
+				kind = "prerelease"
+			}
+			fmt.Fprintf(os.Stderr, "\nA new %s of cnap is available: %s → %s\n",
+				kind,
 				strings.TrimPrefix(version, "v"),
 				strings.TrimPrefix(newRelease.Version, "v"))
-			if isHomebrew {
+			if isHomebrew && !newRelease.Prerelease {
 				fmt.Fprintf(os.Stderr, "To upgrade, run: brew upgrade cnap\n")
 			}
 			fmt.Fprintf(os.Stderr, "%s\n", newRelease.URL)
@@ -62,10 +89,25 @@ func Execute(ctx context.Context) error {
 	return err
 }
 
-func rootCmd() *cobra.Command {
+// machineReadable reports whether the command prints output for scripts
+// (json, jsonpath or quiet), where the update notice on stderr would only
+// get in the way of whatever captures it.
+func machineReadable() bool {
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	format, err := cmdutil.GetOutputFormat(cfg)
+	return err == nil && (format.IsJSON() || format == output.FormatQuiet)
+}
+
+// rootCmd builds the command tree. startUpdateCheck is called before any
+// command runs.
+func rootCmd(startUpdateCheck func()) *cobra.Command {
 	useragent.SetVersion(version)
 
-	var debugFlag bool
+	var debugFlag, noColorFlag bool
+	var logLevelFlag string
 
 	root := &cobra.Command{
 		Use:   "cnap",
@@ -77,26 +119,56 @@ Authenticate with a Personal Access Token or via browser login.`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		Version:       fmt.Sprintf("%s (%s)", version, commit),
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			debug.Init(debugFlag)
-			if debug.Enabled {
-				debug.Install()
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := cmdutil.Setup(cmd.Context(), noColorFlag)
+			if err != nil {
+				return err
+			}
+			cmd.SetContext(ctx)
+			prompt.SetContext(ctx)
+			if err := debug.Init(debugFlag, logLevelFlag); err != nil {
+				return err
+			}
+			if cfg, err := config.Load(); err == nil {
+				useragent.SetHideHostname(cfg.HideHostname)
 			}
+			startUpdateCheck()
+			return nil
 		},
 	}
 
-	root.PersistentFlags().BoolVar(&debugFlag, "debug", false, "Enable debug logging (or set CNAP_DEBUG=1)")
-	root.PersistentFlags().StringVarP(&cmdutil.OutputFormat, "output", "o", "", "Output format: table, json, quiet")
-	root.PersistentFlags().StringVar(&cmdutil.APIURL, "api-url", "", "API base URL (overrides config)")
+	root.PersistentFlags().BoolVar(&debugFlag, "debug", false, "Enable debug logging; shortcut for --log-level debug (or set CNAP_DEBUG=1)")
+	root.PersistentFlags().StringVar(&logLevelFlag, "log-level", "", "Log level on stderr: error, warn, info, debug (or set CNAP_LOG_LEVEL)")
+	root.PersistentFlags().StringVarP(&cmdutil.OutputFormat, "output", "o", "", "Output format: table, json, quiet, jsonpath=TEMPLATE (or set CNAP_OUTPUT_FORMAT)")
+	root.PersistentFlags().StringVar(&cmdutil.APIURL, "api-url", "", "API base URL (overrides CNAP_API_URL and config)")
+	root.PersistentFlags().StringVar(&cmdutil.AuthURL, "auth-url", "", "Auth/dashboard base URL (overrides CNAP_AUTH_URL and config; default derived from the API URL)")
+	root.PersistentFlags().StringVar(&cmdutil.Workspace, "workspace", "", "Workspace ID to use for this command (overrides the active workspace)")
+	root.PersistentFlags().BoolVar(&cmdutil.FromKubeContext, "from-kube-context", false, "Use the workspace of the CNAP cluster named by the current kubectl context")
+	root.PersistentFlags().StringVar(&cmdutil.Proxy, "proxy", "", "Proxy URL for all connections, e.g. http://proxy:3128 or socks5://proxy:1080 (default HTTPS_PROXY)")
+	root.PersistentFlags().DurationVar(&cmdutil.Timeout, "timeout", 0, "Time limit for the whole command, e.g. 30s (default no limit)")
+	root.PersistentFlags().DurationVar(&cmdutil.PollInterval, "poll-interval", 0, "Least time between status checks for --wait, --watch and login, e.g. 10s (or set CNAP_POLL_INTERVAL)")
+	root.PersistentFlags().DurationVar(&cmdutil.PollMaxInterval, "poll-max-interval", 0, "Most time between status checks as they back off, e.g. 1m (or set CNAP_POLL_MAX_INTERVAL)")
+	root.PersistentFlags().Float64Var(&cmdutil.PollBackoff, "poll-backoff", 0, "Factor the time between status checks grows by, at least 1 (or set CNAP_POLL_BACKOFF)")
+	root.PersistentFlags().Float64Var(&cmdutil.PollJitter, "poll-jitter", 0, "Random spread of the time between status checks, 0-1, e.g. 0.2 for ±20% (or set CNAP_POLL_JITTER)")
+	root.PersistentFlags().DurationVar(&cmdutil.PollTimeout, "poll-timeout", 0, "Give up waiting for a status after this long, e.g. 15m (or set CNAP_POLL_TIMEOUT)")
+	root.PersistentFlags().BoolVar(&noColorFlag, "no-color", false, "Disable colored output (or set NO_COLOR=1)")
+	root.PersistentFlags().BoolVar(&namecache.Disabled, "no-cache", false, "Don't use cached resource names")
+	root.PersistentFlags().BoolVar(&env.NoInput, "no-input", false, "Never prompt; require arguments as when not running in a terminal (or set CNAP_NO_INPUT=1)")
+	root.PersistentFlags().BoolVar(&env.Offline, "offline", false, "Skip update checks and browser launch (or set CNAP_OFFLINE=1)")
 
 	root.AddCommand(authcmd.NewCmdAuth())
 	root.AddCommand(workspacescmd.NewCmdWorkspaces())
 	root.AddCommand(clusterscmd.NewCmdClusters())
+	root.AddCommand(clusterscmd.NewCmdKube())
 	root.AddCommand(templatescmd.NewCmdTemplates())
 	root.AddCommand(productscmd.NewCmdProducts())
 	root.AddCommand(installscmd.NewCmdInstalls())
 	root.AddCommand(regionscmd.NewCmdRegions())
 	root.AddCommand(registrycmd.NewCmdRegistry())
+	root.AddCommand(applycmd.NewCmdApply())
+	root.AddCommand(getcmd.NewCmdGet())
+	root.AddCommand(configcmd.NewCmdConfig())
+	root.AddCommand(newCmdStatus())
 
 	return root
 }
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cnap-tech/cli/internal/cmdtest"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+)
+
+func TestCompletionHonorsWorkspaceFlag(t *testing.T) {
+	t.Cleanup(func() { cmdutil.Workspace = "" })
+
+	var gotWorkspace string
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/installs" {
+			cmdtest.WriteError(w, http.StatusNotFound, "not found")
+			return
+		}
+		gotWorkspace = r.Header.Get("X-Workspace-Id")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{
+				{"id": "inst_a", "name": "web"},
+				{"id": "inst_b", "name": nil},
+			},
+			"pagination": map[string]any{"has_more": false},
+		})
+	}))
+
+	var out bytes.Buffer
+	root := rootCmd(func() {})
+	root.SetArgs([]string{"__complete", "--workspace", "ws_x", "installs", "get", ""})
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	if err := root.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("__complete: %v", err)
+	}
+
+	if gotWorkspace != "ws_x" {
+		t.Errorf("X-Workspace-Id = %q, want ws_x", gotWorkspace)
+	}
+	for _, want := range []string{"inst_a\tweb\n", "inst_b\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("completions %q missing %q", out.String(), want)
+		}
+	}
+}
+
+func TestStatusWorkspaceDegrades(t *testing.T) {
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/workspaces/ws_1":
+			_, _ = w.Write([]byte(`{"id":"ws_1","name":"Acme","icon":null,"created_at":0}`))
+		case "/v1/installs":
+			_, _ = w.Write([]byte(`{"data":[{"id":"inst_a"},{"id":"inst_b"}],"pagination":{"has_more":false,"cursor":null}}`))
+		default:
+			cmdtest.WriteError(w, http.StatusInternalServerError, "boom")
+		}
+	}))
+
+	client, _, err := cmdutil.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws := statusWorkspace(context.Background(), client, "ws_1")
+	if ws.Name != "Acme" {
+		t.Errorf("name = %q, want Acme", ws.Name)
+	}
+	if ws.Clusters != nil {
+		t.Errorf("clusters = %d, want unavailable", *ws.Clusters)
+	}
+	if ws.Installs == nil || *ws.Installs != 2 {
+		t.Errorf("installs = %v, want 2", ws.Installs)
+	}
+	if !strings.Contains(ws.Error, "boom") {
+		t.Errorf("error = %q, want the clusters failure", ws.Error)
+	}
+}
+
+func TestMachineReadable(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CNAP_OUTPUT_FORMAT", "")
+	t.Cleanup(func() { cmdutil.OutputFormat = "" })
+
+	// As in the root command, the flag is resolved by Setup first.
+	setup := func(format string) {
+		t.Helper()
+		cmdutil.OutputFormat = format
+		if _, err := cmdutil.Setup(context.Background(), true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	setup("")
+	if machineReadable() {
+		t.Error("default table output: want false")
+	}
+	for _, f := range []string{"json", "quiet", "jsonpath={.id}"} {
+		setup(f)
+		if !machineReadable() {
+			t.Errorf("-o %s: want true", f)
+		}
+	}
+
+	setup("")
+	if err := os.MkdirAll(filepath.Join(home, ".cnap"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".cnap", "config.yaml"), []byte("output:\n  format: json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if !machineReadable() {
+		t.Error("output.format json in config: want true")
+	}
+}
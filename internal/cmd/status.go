@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/api"
+	authcmd "github.com/cnap-tech/cli/internal/cmd/auth"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/env"
+	"github.com/cnap-tech/cli/internal/namecache"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/update"
+	"github.com/spf13/cobra"
+)
+
+// statusSummary is what `cnap status` reports. Each part that needs a
+// request carries its own error, so one failure doesn't hide the rest.
+type statusSummary struct {
+	Version       string           `json:"version"`
+	Update        *updateStatus    `json:"update,omitempty"`
+	Authenticated bool             `json:"authenticated"`
+	TokenType     string           `json:"token_type,omitempty"`
+	User          *userStatus      `json:"user,omitempty"`
+	APIURL        string           `json:"api_url"`
+	AuthURL       string           `json:"auth_url"`
+	Workspace     *workspaceStatus `json:"workspace,omitempty"`
+}
+
+type updateStatus struct {
+	Available bool   `json:"available"`
+	Latest    string `json:"latest,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type userStatus struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type workspaceStatus struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Clusters *int   `json:"clusters,omitempty"`
+	Installs *int   `json:"installs,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// statusUpdateTimeout bounds the release lookup in cnap status.
+const statusUpdateTimeout = 3 * time.Second
+
+func newCmdStatus() *cobra.Command {
+	return &cobra.Command{
+		Use:     "status",
+		Aliases: []string{"whoami"},
+		Short:   "Show whether the CLI is set up: user, workspace, and version",
+		Long: `Shows a one-screen overview: the signed-in user and token type, the
+API and auth URLs, the active workspace with its cluster and install
+counts, and the CLI version with whether an update is available.
+
+Parts that can't be fetched are reported as unavailable rather than
+failing the command. For token details see cnap auth status.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := cmdutil.LoadConfig()
+			if err != nil {
+				return err
+			}
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+
+			s := &statusSummary{
+				Version: version,
+				APIURL:  cfg.BaseURL(),
+				AuthURL: cfg.AuthBaseURL(),
+			}
+			if token := cfg.Token(); token != "" {
+				s.Authenticated = true
+				s.TokenType = authcmd.TokenType(token)
+				client, clientCfg, err := cmdutil.NewClient()
+				if err != nil {
+					s.User = &userStatus{Error: err.Error()}
+				} else {
+					s.User = statusUser(cmd.Context(), client)
+					if clientCfg.ActiveWorkspace != "" {
+						s.Workspace = statusWorkspace(cmd.Context(), client, clientCfg.ActiveWorkspace)
+					}
+				}
+			}
+			s.Update = statusUpdate(cmd.Context())
+
+			if format.IsJSON() {
+				return output.PrintData(format, s)
+			}
+			printStatusSummary(s)
+			return nil
+		},
+	}
+}
+
+func statusUser(ctx context.Context, client *api.ClientWithResponses) *userStatus {
+	resp, err := client.GetV1UserWithResponse(ctx)
+	if err != nil {
+		return &userStatus{Error: err.Error()}
+	}
+	if resp.JSON200 == nil {
+		return &userStatus{Error: cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401).Error()}
+	}
+	return &userStatus{Name: resp.JSON200.Name, Email: resp.JSON200.Email}
+}
+
+// statusWorkspace looks up the workspace's name and counts its clusters and
+// installs, keeping whatever succeeds and the first error.
+func statusWorkspace(ctx context.Context, client *api.ClientWithResponses, id string) *workspaceStatus {
+	ws := &workspaceStatus{ID: id}
+	fail := func(err error) {
+		if ws.Error == "" {
+			ws.Error = err.Error()
+		}
+	}
+
+	if resp, err := client.GetV1WorkspacesIdWithResponse(ctx, id); err != nil {
+		fail(fmt.Errorf("fetching workspace: %w", err))
+	} else if resp.JSON200 == nil {
+		fail(cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404))
+	} else {
+		ws.Name = resp.JSON200.Name
+		namecache.Store(namecache.Workspaces, map[string]string{id: ws.Name})
+	}
+
+	limit := 100
+	clusters, err := cmdutil.ListAll(func(cursor *string) ([]api.Cluster, *api.Pagination, error) {
+		resp, err := client.GetV1ClustersWithResponse(ctx, &api.GetV1ClustersParams{Limit: &limit, Cursor: cursor})
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching clusters: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
+		}
+		return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+	})
+	if err != nil {
+		fail(err)
+	} else {
+		n := len(clusters)
+		ws.Clusters = &n
+	}
+
+	installs, err := cmdutil.ListAll(func(cursor *string) ([]api.Install, *api.Pagination, error) {
+		resp, err := client.GetV1InstallsWithResponse(ctx, &api.GetV1InstallsParams{Limit: &limit, Cursor: cursor})
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching installs: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
+		}
+		return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+	})
+	if err != nil {
+		fail(err)
+	} else {
+		n := len(installs)
+		ws.Installs = &n
+	}
+	return ws
+}
+
+// statusUpdate reports whether a newer release is out. Development builds
+// and --offline skip the check.
+func statusUpdate(ctx context.Context) *updateStatus {
+	if version == "dev" || env.IsOffline() {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, statusUpdateTimeout)
+	defer cancel()
+	rel, err := update.LatestRelease(ctx)
+	if err != nil {
+		return &updateStatus{Error: err.Error()}
+	}
+	return &updateStatus{Available: update.IsNewer(rel.Version, version), Latest: rel.Version, URL: rel.URL}
+}
+
+func printStatusSummary(s *statusSummary) {
+	v := strings.TrimPrefix(s.Version, "v")
+	switch u := s.Update; {
+	case u == nil:
+		fmt.Printf("CLI version:  %s\n", v)
+	case u.Error != "":
+		fmt.Printf("CLI version:  %s (update check failed: %s)\n", v, u.Error)
+	case u.Available:
+		fmt.Printf("CLI version:  %s (update available: %s, %s)\n", v, strings.TrimPrefix(u.Latest, "v"), u.URL)
+	default:
+		fmt.Printf("CLI version:  %s (latest)\n", v)
+	}
+	fmt.Printf("API URL:      %s\n", s.APIURL)
+	fmt.Printf("Auth URL:     %s\n", s.AuthURL)
+
+	if !s.Authenticated {
+		fmt.Println("Not authenticated. Run: cnap auth login")
+		return
+	}
+	if u := s.User; u.Error != "" {
+		fmt.Printf("User:         unavailable (%s)\n", u.Error)
+	} else {
+		fmt.Printf("User:         %s <%s>\n", u.Name, u.Email)
+	}
+	fmt.Printf("Token type:   %s\n", s.TokenType)
+
+	ws := s.Workspace
+	if ws == nil {
+		fmt.Println("No active workspace. Run: cnap workspaces switch <id>")
+		return
+	}
+	if ws.Name != "" {
+		fmt.Printf("Workspace:    %s (%s)\n", ws.Name, ws.ID)
+	} else {
+		fmt.Printf("Workspace:    %s\n", ws.ID)
+	}
+	count := func(n *int) string {
+		if n == nil {
+			return "unavailable"
+		}
+		return fmt.Sprint(*n)
+	}
+	fmt.Printf("Clusters:     %s\n", count(ws.Clusters))
+	fmt.Printf("Installs:     %s\n", count(ws.Installs))
+	if ws.Error != "" {
+		fmt.Printf("Warning: %s\n", ws.Error)
+	}
+}
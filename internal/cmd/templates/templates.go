@@ -3,10 +3,10 @@ package templates
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/cnap-tech/cli/internal/api"
 	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/config"
 	"github.com/cnap-tech/cli/internal/output"
 	"github.com/cnap-tech/cli/internal/prompt"
 	"github.com/spf13/cobra"
@@ -29,6 +29,7 @@ func NewCmdTemplates() *cobra.Command {
 func newCmdList() *cobra.Command {
 	var limit int
 	var cursor string
+	var all bool
 
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -40,36 +41,43 @@ func newCmdList() *cobra.Command {
 				return err
 			}
 
-			if cfg.ActiveWorkspace == "" {
-				return fmt.Errorf("no active workspace. Run: cnap workspaces switch <id>")
+			if err := cmdutil.RequireWorkspace(cfg); err != nil {
+				return err
 			}
 
-			params := &api.GetV1TemplatesParams{Limit: &limit}
-			if cursor != "" {
-				params.Cursor = &cursor
+			items, page, listErr := cmdutil.ListPages(cursor, all, func(cursor *string) ([]api.Template, *api.Pagination, error) {
+				resp, err := client.GetV1TemplatesWithResponse(cmd.Context(), &api.GetV1TemplatesParams{Limit: &limit, Cursor: cursor})
+				if err != nil {
+					return nil, nil, fmt.Errorf("fetching templates: %w", err)
+				}
+				if resp.JSON200 == nil {
+					return nil, nil, cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
+				}
+				return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+			})
+			if listErr != nil && !cmdutil.IsPartial(listErr) {
+				return listErr
 			}
 
-			resp, err := client.GetV1TemplatesWithResponse(cmd.Context(), params)
+			format, err := cmdutil.GetOutputFormat(cfg)
 			if err != nil {
-				return fmt.Errorf("fetching templates: %w", err)
-			}
-			if resp.JSON200 == nil {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON403)
+				return err
 			}
-
-			format := cmdutil.GetOutputFormat(cfg)
-			if format == output.FormatJSON {
-				return output.PrintJSON(resp.JSON200)
+			if format.IsJSON() {
+				if err := output.PrintData(format, cmdutil.NewListOutput(items, page)); err != nil {
+					return err
+				}
+				return listErr
 			}
 
-			if len(resp.JSON200.Data) == 0 {
+			if len(items) == 0 {
 				fmt.Println("No templates found in this workspace.")
-				return nil
+				return listErr
 			}
 
 			header := []string{"ID", "NAME", "PROXY MODE", "CREATED"}
 			var rows [][]string
-			for _, t := range resp.JSON200.Data {
+			for _, t := range items {
 				proxyMode := "-"
 				if t.RegistryProxyMode != nil {
 					proxyMode = string(*t.RegistryProxyMode)
@@ -78,15 +86,14 @@ func newCmdList() *cobra.Command {
 			}
 
 			output.PrintTable(header, rows)
-			if resp.JSON200.Pagination.HasMore {
-				fmt.Printf("\nMore results available. Use --cursor %s to see next page.\n", *resp.JSON200.Pagination.Cursor)
-			}
-			return nil
+			cmdutil.PrintPageHint(format, page)
+			return listErr
 		},
 	}
 
 	cmd.Flags().IntVar(&limit, "limit", 50, "Items per page (1-100)")
 	cmd.Flags().StringVar(&cursor, "cursor", "", "Pagination cursor from previous response")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch every page (--limit sets the page size)")
 
 	return cmd
 }
@@ -110,7 +117,7 @@ func newCmdGet() *cobra.Command {
 			if len(args) > 0 {
 				templateID = args[0]
 			} else {
-				templateID, err = pickTemplate(cmd.Context(), client)
+				templateID, err = pickTemplate(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
@@ -121,12 +128,15 @@ func newCmdGet() *cobra.Command {
 				return fmt.Errorf("fetching template: %w", err)
 			}
 			if resp.JSON200 == nil {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON404)
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404)
 			}
 
-			format := cmdutil.GetOutputFormat(cfg)
-			if format == output.FormatJSON {
-				return output.PrintJSON(resp.JSON200)
+			format, err := cmdutil.GetOutputFormat(cfg)
+			if err != nil {
+				return err
+			}
+			if format.IsJSON() {
+				return output.PrintData(format, resp.JSON200)
 			}
 
 			t := resp.JSON200
@@ -177,7 +187,7 @@ func newCmdDelete() *cobra.Command {
 				return fmt.Errorf("<template-id> argument required when not running interactively")
 			}
 
-			client, _, err := cmdutil.NewClient()
+			client, cfg, err := cmdutil.NewClient()
 			if err != nil {
 				return err
 			}
@@ -186,7 +196,7 @@ func newCmdDelete() *cobra.Command {
 			if len(args) > 0 {
 				templateID = args[0]
 			} else {
-				templateID, err = pickTemplate(cmd.Context(), client)
+				templateID, err = pickTemplate(cmd.Context(), client, cfg)
 				if err != nil {
 					return err
 				}
@@ -210,11 +220,12 @@ func newCmdDelete() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("deleting template: %w", err)
 			}
-			if resp.HTTPResponse.StatusCode != 204 {
-				return apiError(resp.Status(), resp.JSON401, resp.JSON404)
+			job, ok := cmdutil.Succeeded(resp.StatusCode(), resp.Body, cmdutil.DeleteStatuses...)
+			if !ok {
+				return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON404, resp.JSON409)
 			}
 
-			fmt.Printf("Template %s deleted.\n", templateID)
+			cmdutil.PrintDeleted("Template "+templateID, job)
 			return nil
 		},
 	}
@@ -225,14 +236,18 @@ func newCmdDelete() *cobra.Command {
 }
 
 // pickTemplate shows an interactive template picker. Returns the selected template ID.
-func pickTemplate(ctx context.Context, client *api.ClientWithResponses) (string, error) {
+// It lists the active workspace, so it requires one.
+func pickTemplate(ctx context.Context, client *api.ClientWithResponses, cfg *config.Config) (string, error) {
+	if err := cmdutil.RequireWorkspace(cfg); err != nil {
+		return "", err
+	}
 	limit := 100
 	listResp, err := client.GetV1TemplatesWithResponse(ctx, &api.GetV1TemplatesParams{Limit: &limit})
 	if err != nil {
 		return "", fmt.Errorf("fetching templates: %w", err)
 	}
 	if listResp.JSON200 == nil {
-		return "", apiError(listResp.Status(), listResp.JSON401, listResp.JSON403)
+		return "", cmdutil.APIError(listResp.Status(), listResp.Body, listResp.JSON401, listResp.JSON403)
 	}
 	if len(listResp.JSON200.Data) == 0 {
 		return "", fmt.Errorf("no templates found in this workspace")
@@ -241,20 +256,7 @@ func pickTemplate(ctx context.Context, client *api.ClientWithResponses) (string,
 	for i, t := range listResp.JSON200.Data {
 		options[i] = prompt.SelectOption{Label: t.Name + " (" + t.Id + ")", Value: t.Id}
 	}
-	return prompt.Select("Select a template", options)
-}
-
-func apiError(status string, errs ...*api.Error) error {
-	for _, e := range errs {
-		if e != nil {
-			parts := []string{e.Error.Message}
-			if e.Error.Suggestion != nil {
-				parts = append(parts, *e.Error.Suggestion)
-			}
-			return fmt.Errorf("%s", strings.Join(parts, ". "))
-		}
-	}
-	return fmt.Errorf("unexpected response: %s", status)
+	return prompt.Select("Select a template", options, prompt.WithCancel())
 }
 
 func deref(s *string) string {
@@ -2,9 +2,11 @@ package workspaces
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/cnap-tech/cli/internal/api"
 	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/namecache"
 	"github.com/cnap-tech/cli/internal/output"
 	"github.com/cnap-tech/cli/internal/prompt"
 	"github.com/spf13/cobra"
@@ -26,6 +28,7 @@ func NewCmdWorkspaces() *cobra.Command {
 func newCmdList() *cobra.Command {
 	var limit int
 	var cursor string
+	var all bool
 
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -37,27 +40,35 @@ func newCmdList() *cobra.Command {
 				return err
 			}
 
-			params := &api.GetV1WorkspacesParams{Limit: &limit}
-			if cursor != "" {
-				params.Cursor = &cursor
+			items, page, listErr := cmdutil.ListPages(cursor, all, func(cursor *string) ([]api.Workspace, *api.Pagination, error) {
+				resp, err := client.GetV1WorkspacesWithResponse(cmd.Context(), &api.GetV1WorkspacesParams{Limit: &limit, Cursor: cursor})
+				if err != nil {
+					return nil, nil, fmt.Errorf("fetching workspaces: %w", err)
+				}
+				if resp.JSON200 == nil {
+					return nil, nil, cmdutil.APIError(resp.Status(), resp.Body)
+				}
+				return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+			})
+			if listErr != nil && !cmdutil.IsPartial(listErr) {
+				return listErr
 			}
+			cacheWorkspaceNames(items)
 
-			resp, err := client.GetV1WorkspacesWithResponse(cmd.Context(), params)
+			format, err := cmdutil.GetOutputFormat(cfg)
 			if err != nil {
-				return fmt.Errorf("fetching workspaces: %w", err)
-			}
-			if resp.JSON200 == nil {
-				return fmt.Errorf("unexpected response: %s", resp.Status())
+				return err
 			}
-
-			format := cmdutil.GetOutputFormat(cfg)
-			if format == output.FormatJSON {
-				return output.PrintJSON(resp.JSON200)
+			if format.IsJSON() {
+				if err := output.PrintData(format, cmdutil.NewListOutput(items, page)); err != nil {
+					return err
+				}
+				return listErr
 			}
 
 			header := []string{"ID", "NAME"}
 			var rows [][]string
-			for _, w := range resp.JSON200.Data {
+			for _, w := range items {
 				active := ""
 				if w.Id == cfg.ActiveWorkspace {
 					active = " (active)"
@@ -65,15 +76,14 @@ func newCmdList() *cobra.Command {
 				rows = append(rows, []string{w.Id, w.Name + active})
 			}
 			output.PrintTable(header, rows)
-			if resp.JSON200.Pagination.HasMore {
-				fmt.Printf("\nMore results available. Use --cursor %s to see next page.\n", *resp.JSON200.Pagination.Cursor)
-			}
-			return nil
+			cmdutil.PrintPageHint(format, page)
+			return listErr
 		},
 	}
 
 	cmd.Flags().IntVar(&limit, "limit", 50, "Items per page (1-100)")
 	cmd.Flags().StringVar(&cursor, "cursor", "", "Pagination cursor from previous response")
+	cmd.Flags().BoolVar(&all, "all", false, "Fetch every page (--limit sets the page size)")
 
 	return cmd
 }
@@ -101,15 +111,20 @@ In non-interactive environments (CI, pipes), the workspace ID argument is requir
 			var workspaceID string
 
 			if len(args) > 0 {
-				// Validate the workspace ID by fetching it
 				workspaceID = args[0]
+				// Always validate the ID with the server: a cached name
+				// doesn't prove this login can still access it.
 				resp, err := client.GetV1WorkspacesIdWithResponse(cmd.Context(), workspaceID)
 				if err != nil {
 					return fmt.Errorf("validating workspace: %w", err)
 				}
 				if resp.JSON200 == nil {
-					return fmt.Errorf("workspace %q not found", workspaceID)
+					if resp.StatusCode() == http.StatusNotFound {
+						return fmt.Errorf("workspace %q not found", workspaceID)
+					}
+					return cmdutil.APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
 				}
+				namecache.Store(namecache.Workspaces, map[string]string{workspaceID: resp.JSON200.Name})
 				fmt.Printf("Workspace: %s\n", resp.JSON200.Name)
 			} else {
 				// Fetch workspaces for interactive selection
@@ -120,12 +135,13 @@ In non-interactive environments (CI, pipes), the workspace ID argument is requir
 					return fmt.Errorf("fetching workspaces: %w", err)
 				}
 				if resp.JSON200 == nil {
-					return fmt.Errorf("unexpected response: %s", resp.Status())
+					return cmdutil.APIError(resp.Status(), resp.Body)
 				}
 
 				if len(resp.JSON200.Data) == 0 {
 					return fmt.Errorf("no workspaces found")
 				}
+				cacheWorkspaceNames(resp.JSON200.Data)
 
 				options := make([]prompt.SelectOption, len(resp.JSON200.Data))
 				for i, w := range resp.JSON200.Data {
@@ -136,7 +152,7 @@ In non-interactive environments (CI, pipes), the workspace ID argument is requir
 					options[i] = prompt.SelectOption{Label: label, Value: w.Id}
 				}
 
-				workspaceID, err = prompt.Select("Select a workspace", options)
+				workspaceID, err = prompt.SelectWithDefault("Select a workspace", options, cfg.ActiveWorkspace, prompt.WithCancel())
 				if err != nil {
 					return err
 				}
@@ -152,3 +168,12 @@ In non-interactive environments (CI, pipes), the workspace ID argument is requir
 		},
 	}
 }
+
+// cacheWorkspaceNames records workspace names for later display without a lookup.
+func cacheWorkspaceNames(workspaces []api.Workspace) {
+	names := make(map[string]string, len(workspaces))
+	for _, w := range workspaces {
+		names[w.Id] = w.Name
+	}
+	namecache.Store(namecache.Workspaces, names)
+}
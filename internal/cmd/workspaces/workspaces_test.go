@@ -0,0 +1,37 @@
+package workspaces
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cnap-tech/cli/internal/cmdtest"
+	"github.com/cnap-tech/cli/internal/cmdutil"
+	"github.com/cnap-tech/cli/internal/namecache"
+)
+
+func TestSwitchNotFound(t *testing.T) {
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.WriteError(w, http.StatusNotFound, "Workspace not found")
+	}))
+	// A cached name doesn't skip asking the server.
+	if _, err := cmdutil.LoadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	namecache.Store(namecache.Workspaces, map[string]string{"missing": "Gone"})
+
+	err := cmdtest.Run(newCmdSwitch(), "missing")
+	if err == nil || err.Error() != `workspace "missing" not found` {
+		t.Errorf("got error %v, want workspace not found", err)
+	}
+}
+
+func TestSwitchForbidden(t *testing.T) {
+	cmdtest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cmdtest.WriteError(w, http.StatusForbidden, "Not a member of this workspace")
+	}))
+
+	err := cmdtest.Run(newCmdSwitch(), "other")
+	if err == nil || err.Error() != "Not a member of this workspace" {
+		t.Errorf("got error %v, want forbidden message", err)
+	}
+}
@@ -0,0 +1,46 @@
+// Package cmdtest provides helpers for running commands against a fake API server.
+package cmdtest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// NewServer starts a fake API server and points the CLI at it.
+// HOME is set to a temp dir so no real config is read or written,
+// and a dummy PAT is provided via CNAP_API_TOKEN.
+func NewServer(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CNAP_API_URL", srv.URL)
+	t.Setenv("CNAP_API_TOKEN", "cnap_pat_test")
+
+	return srv
+}
+
+// WriteError writes an API error response in the standard error envelope.
+func WriteError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{"code": http.StatusText(status), "message": message},
+	})
+}
+
+// Run executes cmd with the given args, discarding its output.
+func Run(cmd *cobra.Command, args ...string) error {
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	return cmd.ExecuteContext(context.Background())
+}
@@ -0,0 +1,73 @@
+package cmdutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cnap-tech/cli/internal/api"
+)
+
+// maxErrorBody is how much of an unparseable error body is echoed.
+const maxErrorBody = 300
+
+// APIError turns a failed API response into an error. The first non-nil
+// typed error (e.g. resp.JSON404) wins; otherwise the body is parsed as an
+// api.Error, and failing that a truncated, redacted copy of it is included
+// after the status line, since it usually holds the actual cause.
+func APIError(status string, body []byte, errs ...*api.Error) error {
+	for _, e := range errs {
+		if e != nil {
+			return formatAPIError(e)
+		}
+	}
+
+	var e api.Error
+	if json.Unmarshal(body, &e) == nil && e.Error.Message != "" {
+		return formatAPIError(&e)
+	}
+
+	text := strings.Join(strings.Fields(string(body)), " ")
+	if text == "" {
+		return fmt.Errorf("unexpected response: %s", status)
+	}
+	text = redactSecrets(text)
+	if len(text) > maxErrorBody {
+		cut := maxErrorBody
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut] + "…"
+	}
+	return fmt.Errorf("unexpected response: %s: %s", status, text)
+}
+
+func formatAPIError(e *api.Error) error {
+	parts := []string{e.Error.Message}
+	if e.Error.Suggestion != nil {
+		parts = append(parts, *e.Error.Suggestion)
+	}
+	return fmt.Errorf("%s", strings.Join(parts, ". "))
+}
+
+// secretPatterns match things in an echoed body that look like
+// credentials. Group 1, where present, is kept as context.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`),
+	regexp.MustCompile(`cnap_pat_[A-Za-z0-9_]+`),
+	regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	regexp.MustCompile(`(?i)("?(?:token|secret|password|api[_-]?key|authorization)"?\s*[:=]\s*"?)[^"\s,&}]+`),
+}
+
+func redactSecrets(s string) string {
+	for _, re := range secretPatterns {
+		if re.NumSubexp() > 0 {
+			s = re.ReplaceAllString(s, "${1}[REDACTED]")
+		} else {
+			s = re.ReplaceAllString(s, "[REDACTED]")
+		}
+	}
+	return s
+}
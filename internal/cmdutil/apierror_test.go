@@ -0,0 +1,60 @@
+package cmdutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cnap-tech/cli/internal/api"
+)
+
+func TestAPIError(t *testing.T) {
+	suggestion := "Run: cnap auth login"
+	typed := &api.Error{}
+	typed.Error.Message = "Unauthorized"
+	typed.Error.Suggestion = &suggestion
+
+	tests := []struct {
+		name string
+		body string
+		errs []*api.Error
+		want string
+	}{
+		{"typed", `ignored`, []*api.Error{nil, typed}, "Unauthorized. Run: cnap auth login"},
+		{"parsed body", `{"error":{"code":"conflict","message":"Already exists"}}`, nil, "Already exists"},
+		{"empty body", ``, nil, "unexpected response: 502 Bad Gateway"},
+		{"raw body", "<html>\n  upstream timed out\n</html>", nil, "unexpected response: 502 Bad Gateway: <html> upstream timed out </html>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := APIError("502 Bad Gateway", []byte(tt.body), tt.errs...)
+			if err.Error() != tt.want {
+				t.Errorf("got %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIErrorTruncates(t *testing.T) {
+	err := APIError("500 Internal Server Error", []byte(strings.Repeat("é", 400)))
+	msg := strings.TrimPrefix(err.Error(), "unexpected response: 500 Internal Server Error: ")
+	if !strings.HasSuffix(msg, "…") || len(msg) > maxErrorBody+len("…") {
+		t.Errorf("body not truncated: %d bytes", len(msg))
+	}
+	if !strings.HasPrefix(msg, "éé") || strings.ContainsRune(msg, '�') {
+		t.Errorf("truncation split a rune: %q", msg[len(msg)-8:])
+	}
+}
+
+func TestAPIErrorRedacts(t *testing.T) {
+	body := `bad request: Authorization: Bearer abc.def token=cnap_pat_s3cret {"password":"hunter2"} ` +
+		`jwt eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJ1In0.sig`
+	msg := APIError("400 Bad Request", []byte(body)).Error()
+	for _, secret := range []string{"abc.def", "cnap_pat_s3cret", "hunter2", "eyJhbGci"} {
+		if strings.Contains(msg, secret) {
+			t.Errorf("%q not redacted: %s", secret, msg)
+		}
+	}
+	if !strings.Contains(msg, "bad request") {
+		t.Errorf("context lost: %s", msg)
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"os"
 
 	"github.com/cnap-tech/cli/internal/api"
 	"github.com/cnap-tech/cli/internal/config"
 	"github.com/cnap-tech/cli/internal/debug"
+	"github.com/cnap-tech/cli/internal/namecache"
 	"github.com/cnap-tech/cli/internal/output"
 	"github.com/cnap-tech/cli/internal/useragent"
 )
@@ -20,24 +22,55 @@ var OutputFormat string
 // APIURL holds the CLI-level --api-url flag value.
 var APIURL string
 
+// AuthURL holds the CLI-level --auth-url flag value.
+var AuthURL string
+
+// Workspace holds the CLI-level --workspace flag value. When set it
+// replaces the active workspace from config for this invocation only.
+var Workspace string
+
+// LoadConfig loads the config file with --api-url and --auth-url applied.
+func LoadConfig() (*config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	cfg.APIURLFlag, cfg.AuthURLFlag = APIURL, AuthURL
+	namecache.SetScope(cfg.CacheScope())
+	if path, err := config.Path(); err == nil {
+		slog.Info("loaded config", "path", path, "exists", config.Exists())
+	}
+	return cfg, nil
+}
+
 // NewClient creates an authenticated API client from config.
 func NewClient() (*api.ClientWithResponses, *config.Config, error) {
-	cfg, err := config.Load()
+	cfg, err := LoadConfig()
 	if err != nil {
-		return nil, nil, fmt.Errorf("loading config: %w", err)
+		return nil, nil, err
 	}
 
-	if APIURL != "" {
-		cfg.APIURL = APIURL
+	if Workspace != "" && FromKubeContext {
+		return nil, nil, fmt.Errorf("--workspace and --from-kube-context can't be used together")
+	}
+	if Workspace != "" {
+		cfg.ActiveWorkspace = Workspace
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
 	}
 
 	token := cfg.Token()
 	if token == "" {
-		return nil, nil, fmt.Errorf("not authenticated. Run: cnap auth login")
+		return nil, nil, notAuthenticatedError()
+	}
+	if err := cfg.CheckToken(); err != nil {
+		return nil, nil, err
 	}
 
 	baseURL := cfg.BaseURL()
-	slog.Debug("creating API client", "base_url", baseURL, "workspace", cfg.ActiveWorkspace, "user_agent", useragent.String())
+	slog.Info("creating API client", "base_url", baseURL, "workspace", cfg.ActiveWorkspace, "token_from_env", os.Getenv("CNAP_API_TOKEN") != "")
+	slog.Debug("user agent", "user_agent", useragent.String())
 
 	client, err := api.NewClientWithResponses(baseURL, api.WithHTTPClient(debug.Client()), api.WithRequestEditorFn(
 		func(_ context.Context, req *http.Request) error {
@@ -53,16 +86,80 @@ func NewClient() (*api.ClientWithResponses, *config.Config, error) {
 		return nil, nil, fmt.Errorf("creating API client: %w", err)
 	}
 
+	if FromKubeContext {
+		// Looked up once per invocation, however many clients a command
+		// creates, and bounded by --timeout and Ctrl-C.
+		if kubeWorkspace == "" {
+			ws, err := kubeContextWorkspace(commandCtx, client)
+			if err != nil {
+				return nil, nil, err
+			}
+			slog.Info("workspace from kube context", "workspace", ws)
+			kubeWorkspace = ws
+		}
+		cfg.ActiveWorkspace = kubeWorkspace
+	}
+
 	return client, cfg, nil
 }
 
-// GetOutputFormat returns the effective output format.
-func GetOutputFormat(cfg *config.Config) output.Format {
+// RequireWorkspace returns an error if no workspace is active. Only
+// commands that act on the whole workspace (listing, creating, interactive
+// pickers) need one. Commands given a resource ID don't call it: the API
+// scopes those requests by the ID, and X-Workspace-Id is only sent when a
+// workspace is set, so a workspace-scoped CNAP_API_TOKEN works on its own.
+func RequireWorkspace(cfg *config.Config) error {
+	if cfg.ActiveWorkspace == "" {
+		return fmt.Errorf("no active workspace. Run: cnap workspaces switch <id>")
+	}
+	return nil
+}
+
+// notAuthenticatedError tailors the login hint to whether the user has never
+// logged in (no config file) or has logged out (config without a token).
+func notAuthenticatedError() error {
+	if config.Exists() {
+		return fmt.Errorf("not logged in. Run: cnap auth login to sign in again via browser (or cnap auth login --token <token> to use a PAT)")
+	}
+	return fmt.Errorf("not authenticated. Run: cnap auth login to sign in via browser (or cnap auth login --token <token> to use a PAT, e.g. in CI)")
+}
+
+// GetOutputFormat returns the effective output format, or an error naming
+// the setting that holds an unknown one.
+// Precedence: --output flag, then CNAP_OUTPUT_FORMAT, then the config file.
+func GetOutputFormat(cfg *config.Config) (output.Format, error) {
+	format := current.Format
+	if !resolved { // not run via the root command, e.g. in tests
+		var err error
+		if format, err = flagFormat(); err != nil {
+			return "", err
+		}
+	}
+	if format != "" {
+		return format, nil
+	}
+	if format, err := cfg.FileOutputFormat(); err != nil || format != "" {
+		return format, err
+	}
+	return output.FormatTable, nil
+}
+
+// flagFormat returns the format set by --output or CNAP_OUTPUT_FORMAT,
+// or "" if neither is set.
+func flagFormat() (output.Format, error) {
 	if OutputFormat != "" {
-		return output.Format(OutputFormat)
+		return parseFormat("--output", OutputFormat)
+	}
+	if f := os.Getenv("CNAP_OUTPUT_FORMAT"); f != "" {
+		return parseFormat("CNAP_OUTPUT_FORMAT", f)
 	}
-	if cfg.Output.Format != "" {
-		return output.Format(cfg.Output.Format)
+	return "", nil
+}
+
+func parseFormat(source, value string) (output.Format, error) {
+	f, err := output.ParseFormat(value)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", source, err)
 	}
-	return output.FormatTable
+	return f, nil
 }
@@ -0,0 +1,350 @@
+package cmdutil
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cnap-tech/cli/internal/config"
+	"github.com/cnap-tech/cli/internal/debug"
+	"github.com/cnap-tech/cli/internal/output"
+	"github.com/cnap-tech/cli/internal/useragent"
+)
+
+func TestNewClientNotAuthenticated(t *testing.T) {
+	t.Setenv("CNAP_API_TOKEN", "")
+
+	t.Run("never logged in", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+
+		_, _, err := NewClient()
+		if err == nil || !strings.HasPrefix(err.Error(), "not authenticated") {
+			t.Errorf("got error %v, want not authenticated", err)
+		}
+	})
+
+	t.Run("logged out", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		if err := os.MkdirAll(filepath.Join(home, ".cnap"), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(home, ".cnap", "config.yaml"), []byte("api_url: https://api.cnap.tech\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, _, err := NewClient()
+		if err == nil || !strings.HasPrefix(err.Error(), "not logged in") {
+			t.Errorf("got error %v, want not logged in", err)
+		}
+	})
+}
+
+func TestNewClientDebugTransport(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[],"pagination":{"cursor":null,"has_more":false}}`))
+	}))
+	defer srv.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CNAP_API_URL", srv.URL)
+	t.Setenv("CNAP_API_TOKEN", "cnap_pat_test")
+	if err := os.MkdirAll(filepath.Join(home, ".cnap"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".cnap", "config.yaml"), []byte("active_workspace: ws_1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	debug.Enabled = true
+	t.Cleanup(func() {
+		slog.SetDefault(prev)
+		debug.Enabled = false
+	})
+
+	client, _, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetV1ClustersWithResponse(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The request editor still sets headers when the debug transport is in use.
+	if v := got.Get("Authorization"); v != "Bearer cnap_pat_test" {
+		t.Errorf("Authorization = %q", v)
+	}
+	if v := got.Get("X-Workspace-Id"); v != "ws_1" {
+		t.Errorf("X-Workspace-Id = %q", v)
+	}
+	if v := got.Get("User-Agent"); v != useragent.String() {
+		t.Errorf("User-Agent = %q", v)
+	}
+	if !strings.Contains(logs.String(), "HTTP response") || !strings.Contains(logs.String(), "/v1/clusters") {
+		t.Errorf("API call not logged by debug transport:\n%s", logs.String())
+	}
+}
+
+func TestGetOutputFormatPrecedence(t *testing.T) {
+	t.Cleanup(func() { OutputFormat = "" })
+
+	tests := []struct {
+		name        string
+		flag, env   string
+		configValue string
+		want        output.Format
+	}{
+		{"default", "", "", "", output.FormatTable},
+		{"config", "", "", "quiet", output.FormatQuiet},
+		{"env over config", "", "json", "quiet", output.FormatJSON},
+		{"flag over env", "table", "json", "quiet", output.FormatTable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			OutputFormat = tt.flag
+			t.Setenv("CNAP_OUTPUT_FORMAT", tt.env)
+			cfg := &config.Config{Output: config.Output{Format: tt.configValue}}
+			got, err := GetOutputFormat(cfg)
+			if err != nil || got != tt.want {
+				t.Errorf("GetOutputFormat() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetOutputFormatInvalid(t *testing.T) {
+	t.Cleanup(func() { OutputFormat = "" })
+
+	tests := []struct {
+		name        string
+		flag, env   string
+		configValue string
+		wantErr     string
+	}{
+		{"flag", "jsn", "", "", `--output: unknown output format "jsn" (valid: table, json, quiet, jsonpath=TEMPLATE)`},
+		{"env", "", "yaml", "", `CNAP_OUTPUT_FORMAT: unknown output format "yaml"`},
+		{"config", "", "", "wide", `invalid config: output.format is invalid: unknown output format "wide"`},
+		{"valid flag wins over bad config", "json", "", "wide", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			OutputFormat = tt.flag
+			t.Setenv("CNAP_OUTPUT_FORMAT", tt.env)
+			cfg := &config.Config{Output: config.Output{Format: tt.configValue}}
+			_, err := GetOutputFormat(cfg)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.wantErr)):
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewClientIgnoresOverriddenOutputFormat(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CNAP_API_TOKEN", "cnap_pat_test")
+	t.Setenv("CNAP_OUTPUT_FORMAT", "")
+	OutputFormat = "json"
+	t.Cleanup(func() { OutputFormat = "" })
+	if err := os.MkdirAll(filepath.Join(home, ".cnap"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".cnap", "config.yaml"), []byte("output:\n  format: xml\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, cfg, err := NewClient()
+	if err != nil {
+		t.Fatalf("NewClient with a bad output.format overridden by -o: %v", err)
+	}
+	if f, err := GetOutputFormat(cfg); err != nil || f != output.FormatJSON {
+		t.Errorf("GetOutputFormat() = %q, %v, want json", f, err)
+	}
+}
+
+func TestWorkspaceHeaderOptional(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"inst_1","cluster_id":"cls_1","workspace_id":"ws_1","created_at":0}`))
+	}))
+	defer srv.Close()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CNAP_API_URL", srv.URL)
+	t.Setenv("CNAP_API_TOKEN", "cnap_pat_test")
+
+	// A token-only CI setup: no config, so no active workspace.
+	client, cfg, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RequireWorkspace(cfg); err == nil {
+		t.Error("RequireWorkspace without a workspace: want error")
+	}
+	resp, err := client.GetV1InstallsIdWithResponse(context.Background(), "inst_1")
+	if err != nil || resp.JSON200 == nil {
+		t.Fatalf("get by ID without a workspace: %v (%s)", err, resp.Status())
+	}
+	if _, ok := got["X-Workspace-Id"]; ok {
+		t.Errorf("X-Workspace-Id sent without an active workspace: %q", got.Get("X-Workspace-Id"))
+	}
+
+	if err := os.MkdirAll(filepath.Join(home, ".cnap"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".cnap", "config.yaml"), []byte("active_workspace: ws_1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	client, cfg, err = NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RequireWorkspace(cfg); err != nil {
+		t.Errorf("RequireWorkspace: %v", err)
+	}
+	if _, err := client.GetV1InstallsIdWithResponse(context.Background(), "inst_1"); err != nil {
+		t.Fatal(err)
+	}
+	if got.Get("X-Workspace-Id") != "ws_1" {
+		t.Errorf("X-Workspace-Id = %q, want ws_1", got.Get("X-Workspace-Id"))
+	}
+}
+
+func TestFromKubeContext(t *testing.T) {
+	var lookups atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/workspaces" {
+			lookups.Add(1)
+		}
+		switch r.URL.Path {
+		case "/v1/workspaces":
+			_, _ = w.Write([]byte(`{"data":[{"id":"ws_1","name":"a","icon":null,"created_at":0},{"id":"ws_2","name":"b","icon":null,"created_at":0}],"pagination":{"has_more":false,"cursor":null}}`))
+		case "/v1/clusters":
+			ws := r.Header.Get("X-Workspace-Id")
+			name := map[string]string{"ws_1": "staging", "ws_2": "production"}[ws]
+			_, _ = w.Write([]byte(`{"data":[{"id":"cls_` + ws + `","name":"` + name + `","workspace_id":"` + ws + `","region_id":"r","kaas":null,"created_at":0}],"pagination":{"has_more":false,"cursor":null}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CNAP_API_URL", srv.URL)
+	t.Setenv("CNAP_API_TOKEN", "cnap_pat_test")
+	FromKubeContext = true
+	t.Cleanup(func() {
+		FromKubeContext = false
+		current, resolved = Settings{}, false
+		Teardown()
+	})
+
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	t.Setenv("KUBECONFIG", kubeconfig)
+	writeContext := func(cluster string) {
+		t.Helper()
+		data := "current-context: prod\ncontexts:\n- name: prod\n  context:\n    cluster: " + cluster + "\n"
+		if err := os.WriteFile(kubeconfig, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeContext("production")
+	_, cfg, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ActiveWorkspace != "ws_2" {
+		t.Errorf("workspace = %q, want ws_2", cfg.ActiveWorkspace)
+	}
+	// Later clients in the same invocation reuse the lookup.
+	if _, cfg, err = NewClient(); err != nil || cfg.ActiveWorkspace != "ws_2" {
+		t.Errorf("second client: workspace %q, %v; want ws_2", cfg.ActiveWorkspace, err)
+	}
+	if n := lookups.Load(); n != 1 {
+		t.Errorf("workspace lookups = %d, want 1", n)
+	}
+
+	Teardown()
+	writeContext("dev")
+	if _, _, err := NewClient(); err == nil || !strings.Contains(err.Error(), `no CNAP cluster named "dev"`) {
+		t.Errorf("unmatched cluster: got error %v", err)
+	}
+
+	// The lookup runs in the command's context, so Ctrl-C and --timeout
+	// stop it.
+	Teardown()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Setup(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := NewClient(); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled command: got error %v, want context.Canceled", err)
+	}
+}
+
+func TestLoadConfigURLFlags(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CNAP_API_URL", "https://api.env.example.com")
+	t.Setenv("CNAP_AUTH_URL", "")
+	t.Cleanup(func() { APIURL, AuthURL = "", "" })
+
+	APIURL = "https://api.selfhosted.example.com"
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if got := cfg.BaseURL(); got != APIURL {
+		t.Errorf("BaseURL() = %q, want --api-url over CNAP_API_URL", got)
+	}
+	if got, want := cfg.AuthBaseURL(), "https://selfhosted.example.com"; got != want {
+		t.Errorf("AuthBaseURL() = %q, want %q derived from --api-url", got, want)
+	}
+
+	AuthURL = "https://login.example.com"
+	if cfg, err = LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if got := cfg.AuthBaseURL(); got != AuthURL {
+		t.Errorf("AuthBaseURL() = %q, want --auth-url", got)
+	}
+
+	// Commands that save the config (login, workspaces switch) must not
+	// persist the per-invocation flags.
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".cnap", "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "selfhosted") || strings.Contains(string(data), "login.example.com") {
+		t.Errorf("flags were saved to the config:\n%s", data)
+	}
+}
@@ -0,0 +1,48 @@
+package cmdutil
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultConcurrency is a sensible limit for MapConcurrent when fanning out
+// per-item API calls.
+const DefaultConcurrency = 8
+
+// MapConcurrent calls fn for each item with at most limit calls in flight
+// (DefaultConcurrency if limit < 1). Results and errors are returned in
+// input order; errs[i] is the error for items[i]. Once ctx is cancelled,
+// items that haven't started are skipped with ctx.Err().
+func MapConcurrent[T, R any](ctx context.Context, items []T, limit int, fn func(context.Context, T) (R, error)) ([]R, []error) {
+	if limit < 1 {
+		limit = DefaultConcurrency
+	}
+
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	sem := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	for i, item := range items {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			for j := i; j < len(items); j++ {
+				errs[j] = ctx.Err()
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i], errs[i] = fn(ctx, item)
+		}()
+	}
+
+	wg.Wait()
+	return results, errs
+}
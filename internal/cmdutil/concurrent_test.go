@@ -0,0 +1,65 @@
+package cmdutil
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMapConcurrent(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	errOdd := errors.New("odd")
+
+	var inFlight, peak atomic.Int32
+	results, errs := MapConcurrent(context.Background(), items, 3, func(_ context.Context, n int) (int, error) {
+		cur := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if cur <= p || peak.CompareAndSwap(p, cur) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		if n%2 == 1 {
+			return 0, errOdd
+		}
+		return n * n, nil
+	})
+
+	if p := peak.Load(); p > 3 {
+		t.Errorf("peak concurrency = %d, want <= 3", p)
+	}
+	for i, n := range items {
+		if n%2 == 1 {
+			if !errors.Is(errs[i], errOdd) {
+				t.Errorf("errs[%d] = %v, want errOdd", i, errs[i])
+			}
+			continue
+		}
+		if errs[i] != nil || results[i] != n*n {
+			t.Errorf("item %d: got %d, %v; want %d", n, results[i], errs[i], n*n)
+		}
+	}
+}
+
+func TestMapConcurrentCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls atomic.Int32
+	_, errs := MapConcurrent(ctx, make([]int, 20), 1, func(ctx context.Context, _ int) (int, error) {
+		if calls.Add(1) == 2 {
+			cancel()
+		}
+		return 0, nil
+	})
+
+	if n := calls.Load(); n >= 20 {
+		t.Errorf("fn called %d times after cancel, want fewer than 20", n)
+	}
+	if !errors.Is(errs[len(errs)-1], context.Canceled) {
+		t.Errorf("last item error = %v, want context.Canceled", errs[len(errs)-1])
+	}
+}
@@ -0,0 +1,99 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/output"
+)
+
+// eventsWatchInterval is how often WatchEvents polls for new events.
+const eventsWatchInterval = 2 * time.Second
+
+// EventFetcher fetches events newer than since (a Unix timestamp; 0 for
+// the most recent ones).
+type EventFetcher func(ctx context.Context, since float64) ([]api.Event, error)
+
+// WatchEvents prints the initial events, then polls fetch for newer ones
+// until the context is cancelled. JSON output is one event object per line.
+func WatchEvents(ctx context.Context, initial []api.Event, format output.Format, fetch EventFetcher) error {
+	seen := make(map[string]bool)
+	var since float64
+
+	if !format.IsJSON() {
+		fmt.Printf(eventLineFormat, "TIME", "TYPE", "REASON", "OBJECT", "MESSAGE")
+	}
+
+	emit := func(events []api.Event) {
+		var fresh []api.Event
+		for _, e := range events {
+			key := eventKey(e)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			since = max(since, e.Timestamp)
+			fresh = append(fresh, e)
+		}
+		for _, e := range fresh {
+			if format.IsJSON() {
+				_ = output.EncodeData(os.Stdout, format, e)
+				continue
+			}
+			typ := fmt.Sprintf("%-7s", e.Type)
+			if output.ColorEnabled() {
+				typ = output.EventTypeStyle(string(e.Type)).Render(typ)
+			}
+			fmt.Printf(eventLineFormat, FormatEventTime(e.Timestamp), typ, e.Reason, e.Object, e.Message)
+		}
+	}
+
+	first := true
+	err := PollUntil(ctx, &PollStrategy{Interval: eventsWatchInterval, Fixed: true}, func(ctx context.Context) (bool, error) {
+		if first {
+			first = false
+			emit(initial)
+			return false, nil
+		}
+		events, err := fetch(ctx, since)
+		if err != nil {
+			return false, err
+		}
+		emit(events)
+		return false, nil
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// eventKey identifies an event occurrence for de-duplication across polls.
+func eventKey(e api.Event) string {
+	return fmt.Sprintf("%f|%s|%s|%s|%d", e.Timestamp, e.Object, e.Reason, e.Message, e.Count)
+}
+
+// eventLineFormat lays out streamed events in fixed-width columns, since rows
+// arriving during --watch can't be aligned as a whole table.
+const eventLineFormat = "%-19s  %-7s  %-24s  %-32s  %s\n"
+
+// PrintEvents prints events as a table, with the TYPE column styled.
+func PrintEvents(events []api.Event) {
+	rows := make([][]string, len(events))
+	for i, e := range events {
+		rows[i] = []string{FormatEventTime(e.Timestamp), string(e.Type), e.Reason, e.Object, e.Message}
+	}
+	output.PrintStyledTable(
+		[]string{"TIME", "TYPE", "REASON", "OBJECT", "MESSAGE"},
+		rows,
+		map[string]output.StyleFunc{"TYPE": output.EventTypeStyle},
+	)
+}
+
+// FormatEventTime formats an event timestamp in local time.
+func FormatEventTime(ts float64) string {
+	return time.Unix(int64(ts), 0).Local().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,30 @@
+package cmdutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cnap-tech/cli/internal/prompt"
+)
+
+// ExitError reports a non-zero exit status from a remote process (e.g. exec).
+// main exits with Code without printing an error, since the remote process
+// has already written its own output.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// Interrupted reports whether err, from a command run in ctx, comes from
+// Ctrl-C, at a prompt or while the command was running. A --timeout
+// deadline or a cancellation that didn't come from ctx is not an interrupt.
+func Interrupted(ctx context.Context, err error) bool {
+	if errors.Is(err, prompt.ErrAborted) || errors.Is(err, prompt.ErrInterrupted) {
+		return true
+	}
+	return ctx.Err() != nil && errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,37 @@
+package cmdutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/prompt"
+)
+
+func TestInterrupted(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"success", context.Background(), nil, false},
+		{"error", context.Background(), errors.New("boom"), false},
+		{"ctrl-c while running", cancelled, fmt.Errorf("list installs: %w", context.Canceled), true},
+		{"ctrl-c at a prompt", context.Background(), fmt.Errorf("selecting: %w", prompt.ErrAborted), true},
+		{"stray cancel", context.Background(), context.Canceled, false},
+		{"--timeout deadline", expired, fmt.Errorf("timed out after 1s (--timeout): %w", context.DeadlineExceeded), false},
+	}
+	for _, tt := range tests {
+		if got := Interrupted(tt.ctx, tt.err); got != tt.want {
+			t.Errorf("%s: Interrupted = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package cmdutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"gopkg.in/yaml.v3"
+)
+
+// FromKubeContext holds the CLI-level --from-kube-context flag value. When
+// set, the workspace for this invocation is that of the CNAP cluster
+// matching the current kubectl context.
+var FromKubeContext bool
+
+// kubeconfig is the part of a kubeconfig file needed to find the current
+// context's cluster.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Contexts       []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+}
+
+// kubeconfigPaths returns the kubeconfig files kubectl reads: those listed
+// in KUBECONFIG, else ~/.kube/config.
+func kubeconfigPaths() ([]string, error) {
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		var paths []string
+		for _, p := range filepath.SplitList(env) {
+			if p != "" {
+				paths = append(paths, p)
+			}
+		}
+		return paths, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("cannot find home directory: %w", err)
+	}
+	return []string{filepath.Join(home, ".kube", "config")}, nil
+}
+
+// currentKubeCluster returns the current kube context and the name of its
+// cluster. Files are merged like kubectl does: the first current-context
+// set wins, as does the first definition of each context.
+func currentKubeCluster() (contextName, cluster string, err error) {
+	paths, err := kubeconfigPaths()
+	if err != nil {
+		return "", "", err
+	}
+	var files []kubeconfig
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("reading kubeconfig: %w", err)
+		}
+		var kc kubeconfig
+		if err := yaml.Unmarshal(data, &kc); err != nil {
+			return "", "", fmt.Errorf("parsing kubeconfig %s: %w", p, err)
+		}
+		files = append(files, kc)
+		if contextName == "" {
+			contextName = kc.CurrentContext
+		}
+	}
+	if contextName == "" {
+		return "", "", fmt.Errorf("no current kube context (looked in %s)", strings.Join(paths, ", "))
+	}
+	for _, kc := range files {
+		for _, c := range kc.Contexts {
+			if c.Name == contextName {
+				if c.Context.Cluster == "" {
+					return "", "", fmt.Errorf("kube context %q has no cluster", contextName)
+				}
+				return contextName, c.Context.Cluster, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("kube context %q not found in kubeconfig", contextName)
+}
+
+// kubeWorkspace is the workspace NewClient found for --from-kube-context,
+// kept for the rest of the invocation; Teardown clears it.
+var kubeWorkspace string
+
+// kubeContextWorkspace returns the workspace of the CNAP cluster whose name
+// or ID is the current kube context's cluster, searching every workspace
+// the user can see.
+func kubeContextWorkspace(ctx context.Context, client *api.ClientWithResponses) (string, error) {
+	contextName, cluster, err := currentKubeCluster()
+	if err != nil {
+		return "", fmt.Errorf("--from-kube-context: %w", err)
+	}
+
+	limit := 100
+	workspaces, err := ListAll(func(cursor *string) ([]api.Workspace, *api.Pagination, error) {
+		resp, err := client.GetV1WorkspacesWithResponse(ctx, &api.GetV1WorkspacesParams{Limit: &limit, Cursor: cursor})
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching workspaces: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return nil, nil, APIError(resp.Status(), resp.Body, resp.JSON401)
+		}
+		return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	found, errs := MapConcurrent(ctx, workspaces, 0, func(ctx context.Context, ws api.Workspace) ([]api.Cluster, error) {
+		inWorkspace := func(_ context.Context, req *http.Request) error {
+			req.Header.Set("X-Workspace-Id", ws.Id)
+			return nil
+		}
+		return ListAll(func(cursor *string) ([]api.Cluster, *api.Pagination, error) {
+			resp, err := client.GetV1ClustersWithResponse(ctx, &api.GetV1ClustersParams{Limit: &limit, Cursor: cursor}, inWorkspace)
+			if err != nil {
+				return nil, nil, fmt.Errorf("fetching clusters: %w", err)
+			}
+			if resp.JSON200 == nil {
+				return nil, nil, APIError(resp.Status(), resp.Body, resp.JSON401, resp.JSON403)
+			}
+			return resp.JSON200.Data, &resp.JSON200.Pagination, nil
+		})
+	})
+
+	var matches []api.Cluster
+	for i, clusters := range found {
+		if errs[i] != nil {
+			return "", fmt.Errorf("workspace %s: %w", workspaces[i].Id, errs[i])
+		}
+		for _, c := range clusters {
+			if c.Name == cluster || c.Id == cluster {
+				matches = append(matches, c)
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("kube context %q: no CNAP cluster named %q in your workspaces. Use --workspace instead", contextName, cluster)
+	case 1:
+		return matches[0].WorkspaceId, nil
+	}
+	ids := make([]string, len(matches))
+	for i, c := range matches {
+		ids[i] = fmt.Sprintf("%s (workspace %s)", c.Id, c.WorkspaceId)
+	}
+	return "", fmt.Errorf("kube context %q: cluster %q matches several CNAP clusters: %s. Use --workspace instead", contextName, cluster, strings.Join(ids, ", "))
+}
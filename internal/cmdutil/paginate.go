@@ -0,0 +1,111 @@
+package cmdutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/cnap-tech/cli/internal/api"
+	"github.com/cnap-tech/cli/internal/output"
+)
+
+// PageFunc fetches one page of a list endpoint starting at cursor (nil for
+// the first page).
+type PageFunc[T any] func(cursor *string) ([]T, *api.Pagination, error)
+
+// PartialError reports that a multi-page fetch failed after some pages had
+// already been fetched. The items gathered so far are returned alongside it
+// so commands can still print them before exiting non-zero.
+type PartialError struct {
+	Pages int // pages fetched before the failure
+	Items int // items on those pages
+	Err   error
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("results are partial (%d items from %d pages): %v", e.Items, e.Pages, e.Err)
+}
+
+func (e *PartialError) Unwrap() error { return e.Err }
+
+// ListPages fetches the page at cursor and, with all, every page after it.
+// The returned pagination is that of the last page fetched; if a later page
+// fails it points at the failed page, so --cursor can resume from there,
+// and the error is a *PartialError. A failure on the first page is
+// returned as is, with no items.
+func ListPages[T any](cursor string, all bool, fetch PageFunc[T]) ([]T, api.Pagination, error) {
+	var items []T
+	var next *string
+	if cursor != "" {
+		next = &cursor
+	}
+
+	for pages := 0; ; pages++ {
+		data, page, err := fetch(next)
+		if err != nil {
+			if pages == 0 {
+				return nil, api.Pagination{}, err
+			}
+			return items, api.Pagination{HasMore: true, Cursor: next}, &PartialError{Pages: pages, Items: len(items), Err: err}
+		}
+		items = append(items, data...)
+		if !all || !page.HasMore || page.Cursor == nil {
+			return items, *page, nil
+		}
+		next = page.Cursor
+	}
+}
+
+// ListAll fetches every page of a list endpoint. On a late-page failure it
+// returns the items gathered so far with a *PartialError.
+func ListAll[T any](fetch PageFunc[T]) ([]T, error) {
+	items, _, err := ListPages("", true, fetch)
+	return items, err
+}
+
+// IsPartial reports whether err is a *PartialError, i.e. whether results
+// returned with it should still be printed.
+func IsPartial(err error) bool {
+	var partial *PartialError
+	return errors.As(err, &partial)
+}
+
+// PrintPageHint tells the user how to fetch the next page when there is
+// one. It goes to stderr so piped table output stays clean, and is left
+// out for json, jsonpath and quiet output.
+func PrintPageHint(format output.Format, page api.Pagination) {
+	if !page.HasMore || page.Cursor == nil || format.IsJSON() || format == output.FormatQuiet {
+		return
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "\nMore results available. Use --cursor %s to see next page.\n", *page.Cursor)
+}
+
+// ListOutput is the JSON shape of every list command's output. It is
+// defined here rather than reusing the generated *List types so the shape
+// scripts rely on doesn't change when the API spec is regenerated.
+type ListOutput[T any] struct {
+	Data       []T      `json:"data"`
+	Pagination PageInfo `json:"pagination"`
+}
+
+// PageInfo says whether more items follow and the --cursor to fetch them.
+// After --all, HasMore is false and Cursor null, unless a page failed, in
+// which case Cursor resumes from it.
+type PageInfo struct {
+	HasMore bool    `json:"has_more"`
+	Cursor  *string `json:"cursor"`
+}
+
+// NewListOutput builds the list output for items and the pagination
+// returned by ListPages. An empty list is encoded as [] rather than null,
+// and the cursor is null whenever there is no next page.
+func NewListOutput[T any](items []T, page api.Pagination) ListOutput[T] {
+	if items == nil {
+		items = []T{}
+	}
+	info := PageInfo{HasMore: page.HasMore}
+	if page.HasMore {
+		info.Cursor = page.Cursor
+	}
+	return ListOutput[T]{Data: items, Pagination: info}
+}
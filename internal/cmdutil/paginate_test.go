@@ -0,0 +1,87 @@
+package cmdutil
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/cnap-tech/cli/internal/api"
+)
+
+// pages returns a PageFunc over n pages of two items each that fails on
+// page failAt (1-based; 0 never fails).
+func pages(n, failAt int) PageFunc[int] {
+	return func(cursor *string) ([]int, *api.Pagination, error) {
+		p := 1
+		if cursor != nil {
+			p, _ = strconv.Atoi(*cursor)
+		}
+		if p == failAt {
+			return nil, nil, errors.New("connection reset")
+		}
+		next := strconv.Itoa(p + 1)
+		return []int{p*10 + 1, p*10 + 2}, &api.Pagination{HasMore: p < n, Cursor: &next}, nil
+	}
+}
+
+func TestListPages(t *testing.T) {
+	items, page, err := ListPages("", true, pages(3, 0))
+	if err != nil || len(items) != 6 || page.HasMore {
+		t.Errorf("all pages: items = %v, page = %+v, err = %v", items, page, err)
+	}
+
+	items, page, err = ListPages("2", false, pages(3, 0))
+	if err != nil || len(items) != 2 || items[0] != 21 || !page.HasMore || *page.Cursor != "3" {
+		t.Errorf("single page: items = %v, page = %+v, err = %v", items, page, err)
+	}
+
+	items, _, err = ListPages("", true, pages(3, 1))
+	if err == nil || IsPartial(err) || items != nil {
+		t.Errorf("first page failing: items = %v, err = %v; want plain error", items, err)
+	}
+}
+
+func TestListPagesPartial(t *testing.T) {
+	items, page, err := ListPages("", true, pages(5, 3))
+
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("err = %v, want *PartialError", err)
+	}
+	if partial.Pages != 2 || partial.Items != 4 {
+		t.Errorf("partial = %+v, want 2 pages and 4 items", partial)
+	}
+	if len(items) != 4 {
+		t.Errorf("items = %v, want the first two pages", items)
+	}
+	if !page.HasMore || page.Cursor == nil || *page.Cursor != "3" {
+		t.Errorf("page = %+v, want a cursor pointing at the failed page", page)
+	}
+}
+
+func TestNewListOutput(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []int
+		page  api.Pagination
+		want  string
+	}{
+		{"empty", nil, api.Pagination{}, `{"data":[],"pagination":{"has_more":false,"cursor":null}}`},
+		{"more", []int{1}, api.Pagination{HasMore: true, Cursor: ptr("c2")}, `{"data":[1],"pagination":{"has_more":true,"cursor":"c2"}}`},
+		{"last page of --all", []int{1, 2}, api.Pagination{Cursor: ptr("stale")}, `{"data":[1,2],"pagination":{"has_more":false,"cursor":null}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(NewListOutput(tt.items, tt.page))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string { return &s }
@@ -0,0 +1,54 @@
+package cmdutil
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// ExpandPath expands environment variables ($VAR, ${VAR}) in a path given
+// to a flag, then a leading ~ or ~user to that home directory. The shell
+// usually does this, but not in --flag=~/file form or when the path comes
+// from quotes or a script. An unset variable is an error rather than an
+// empty string, which would silently point elsewhere (e.g. $DIR/x to /x).
+// Other absolute and relative paths are returned unchanged; "-" (stdin) too.
+func ExpandPath(path string) (string, error) {
+	var unset []string
+	expanded := os.Expand(path, func(name string) string {
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			unset = append(unset, "$"+name)
+		}
+		return v
+	})
+	if len(unset) > 0 {
+		return "", fmt.Errorf("expanding %s: %s not set", path, strings.Join(unset, ", "))
+	}
+	path = expanded
+	if !strings.HasPrefix(path, "~") {
+		return path, nil
+	}
+
+	name, rest := path[1:], ""
+	if i := strings.IndexAny(name, "/"+string(filepath.Separator)); i >= 0 {
+		name, rest = name[:i], name[i+1:]
+	}
+
+	var home string
+	if name == "" {
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expanding %s: %w", path, err)
+		}
+		home = dir
+	} else {
+		u, err := user.Lookup(name)
+		if err != nil {
+			return "", fmt.Errorf("expanding %s: unknown user %q", path, name)
+		}
+		home = u.HomeDir
+	}
+	return filepath.Join(home, rest), nil
+}
@@ -0,0 +1,59 @@
+package cmdutil
+
+import (
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("VALUES_DIR", "/srv/values")
+
+	tests := []struct {
+		in, want string
+	}{
+		{"~", home},
+		{"~/values.yaml", filepath.Join(home, "values.yaml")},
+		{"$HOME/values.yaml", filepath.Join(home, "values.yaml")},
+		{"${VALUES_DIR}/prod.yaml", "/srv/values/prod.yaml"},
+		{"/etc/values.yaml", "/etc/values.yaml"},
+		{"values.yaml", "values.yaml"},
+		{"./a~b.yaml", "./a~b.yaml"},
+		{"-", "-"},
+	}
+	for _, tt := range tests {
+		got, err := ExpandPath(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ExpandPath(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestExpandPathUnsetVariable(t *testing.T) {
+	t.Setenv("EMPTY_DIR", "")
+	if got, err := ExpandPath("${EMPTY_DIR}x.yaml"); err != nil || got != "x.yaml" {
+		t.Errorf("ExpandPath with an empty variable = %q, %v; want x.yaml", got, err)
+	}
+
+	_, err := ExpandPath("$CNAP_NO_SUCH_VAR/values.yaml")
+	if err == nil || !strings.Contains(err.Error(), "$CNAP_NO_SUCH_VAR not set") {
+		t.Errorf("ExpandPath with an unset variable error = %v, want not set", err)
+	}
+}
+
+func TestExpandPathUser(t *testing.T) {
+	if u, err := user.Current(); err == nil && u.HomeDir != "" {
+		got, err := ExpandPath("~" + u.Username + "/x.yaml")
+		if want := filepath.Join(u.HomeDir, "x.yaml"); err != nil || got != want {
+			t.Errorf("ExpandPath(~%s/x.yaml) = %q, %v; want %q", u.Username, got, err, want)
+		}
+	}
+
+	_, err := ExpandPath("~no-such-user-cnap/x.yaml")
+	if err == nil || !strings.Contains(err.Error(), "unknown user") {
+		t.Errorf("ExpandPath(~no-such-user-cnap) error = %v, want unknown user", err)
+	}
+}
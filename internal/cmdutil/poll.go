@@ -0,0 +1,190 @@
+package cmdutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"time"
+)
+
+// The CLI-level --poll-* flag values. Each falls back to its CNAP_POLL_*
+// variable; see pollSettings.
+var (
+	PollInterval    time.Duration
+	PollMaxInterval time.Duration
+	PollBackoff     float64
+	PollJitter      float64
+	PollTimeout     time.Duration
+)
+
+// PollStrategy says how often PollUntil checks a condition.
+type PollStrategy struct {
+	// Interval is the wait after the first check.
+	Interval time.Duration
+	// MaxInterval caps the wait as it grows by Factor; 0 means no cap.
+	MaxInterval time.Duration
+	// Factor multiplies the wait after each check; values up to 1 keep
+	// it at Interval.
+	Factor float64
+	// Jitter randomizes each wait by up to this fraction either way
+	// (0.1 = ±10%), so many clients don't poll in lockstep.
+	Jitter float64
+	// Timeout bounds the whole poll; 0 means only ctx does.
+	Timeout time.Duration
+	// Fixed polls every Interval until ctx is done, ignoring the
+	// --poll-* settings except the --poll-interval floor. For open-ended
+	// watches, which shouldn't time out or slow down.
+	Fixed bool
+}
+
+// ErrPollTimeout is returned by PollUntil when the strategy's Timeout
+// passes before the condition is met.
+var ErrPollTimeout = errors.New("timed out waiting")
+
+// PollUntil calls check now and then after each wait of s until it
+// reports done, returns an error, or ctx is done (returning ctx.Err()).
+// check may change s between calls, e.g. to slow down when a server asks.
+//
+// The --poll-* flags override s first (see override), so users on slow
+// links can poll less often than the defaults.
+func PollUntil(ctx context.Context, s *PollStrategy, check func(ctx context.Context) (done bool, err error)) error {
+	s.override(Current().Poll)
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, s.Timeout, ErrPollTimeout)
+		defer cancel()
+	}
+
+	timedOut := func() bool { return errors.Is(context.Cause(ctx), ErrPollTimeout) }
+	wait := s.Interval
+	for {
+		done, err := check(ctx)
+		if err != nil && timedOut() {
+			// check failed because the timeout cancelled its request.
+			return fmt.Errorf("%w (%s)", ErrPollTimeout, s.Timeout)
+		}
+		if err != nil || done {
+			return err
+		}
+
+		timer := time.NewTimer(s.next(wait))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if timedOut() {
+				return fmt.Errorf("%w (%s)", ErrPollTimeout, s.Timeout)
+			}
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		wait = max(wait, s.Interval)
+		if s.Factor > 1 {
+			wait = time.Duration(float64(wait) * s.Factor)
+		}
+		if s.MaxInterval > 0 {
+			wait = min(wait, max(s.MaxInterval, s.Interval))
+		}
+	}
+}
+
+// override applies the user's poll settings o to s. o's fields that are
+// set replace s's, except that o.Interval is a floor for every wait (see
+// next), so a command never polls faster than its server allows, and the
+// shorter of the two timeouts wins. A Fixed s keeps its own settings.
+func (s *PollStrategy) override(o PollStrategy) {
+	if s.Fixed {
+		return
+	}
+	if o.MaxInterval > 0 {
+		s.MaxInterval = o.MaxInterval
+	}
+	if o.Factor > 0 {
+		s.Factor = o.Factor
+	}
+	if o.Jitter > 0 {
+		s.Jitter = o.Jitter
+	}
+	if o.Timeout > 0 && (s.Timeout == 0 || o.Timeout < s.Timeout) {
+		s.Timeout = o.Timeout
+	}
+}
+
+// next applies jitter and the --poll-interval floor to wait.
+func (s *PollStrategy) next(wait time.Duration) time.Duration {
+	if s.Jitter > 0 {
+		wait += time.Duration((rand.Float64()*2 - 1) * s.Jitter * float64(wait))
+	}
+	return max(wait, Current().Poll.Interval)
+}
+
+// pollSettings resolves the --poll-* flags, each falling back to its
+// CNAP_POLL_* variable. Unset ones are zero.
+func pollSettings() (PollStrategy, error) {
+	var s PollStrategy
+	var err error
+	if s.Interval, err = durationSetting(PollInterval, "--poll-interval", "CNAP_POLL_INTERVAL"); err != nil {
+		return s, err
+	}
+	if s.MaxInterval, err = durationSetting(PollMaxInterval, "--poll-max-interval", "CNAP_POLL_MAX_INTERVAL"); err != nil {
+		return s, err
+	}
+	if s.Timeout, err = durationSetting(PollTimeout, "--poll-timeout", "CNAP_POLL_TIMEOUT"); err != nil {
+		return s, err
+	}
+
+	var source string
+	if s.Factor, source, err = floatSetting(PollBackoff, "--poll-backoff", "CNAP_POLL_BACKOFF"); err != nil {
+		return s, err
+	}
+	if s.Factor != 0 && s.Factor < 1 {
+		return s, fmt.Errorf("%s: must be at least 1", source)
+	}
+	if s.Jitter, source, err = floatSetting(PollJitter, "--poll-jitter", "CNAP_POLL_JITTER"); err != nil {
+		return s, err
+	}
+	if s.Jitter < 0 || s.Jitter > 1 {
+		return s, fmt.Errorf("%s: must be between 0 and 1", source)
+	}
+	return s, nil
+}
+
+// durationSetting returns the flag value d, or else the env variable's.
+func durationSetting(d time.Duration, flag, env string) (time.Duration, error) {
+	source := flag
+	if d == 0 {
+		v := os.Getenv(env)
+		if v == "" {
+			return 0, nil
+		}
+		var err error
+		if d, err = time.ParseDuration(v); err != nil {
+			return 0, fmt.Errorf("%s: invalid duration %q, e.g. 10s", env, v)
+		}
+		source = env
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s: must not be negative", source)
+	}
+	return d, nil
+}
+
+// floatSetting returns the flag value f, or else the env variable's, and
+// which of the two it came from.
+func floatSetting(f float64, flag, env string) (float64, string, error) {
+	if f != 0 {
+		return f, flag, nil
+	}
+	v := os.Getenv(env)
+	if v == "" {
+		return 0, flag, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, env, fmt.Errorf("%s: invalid number %q", env, v)
+	}
+	return f, env, nil
+}
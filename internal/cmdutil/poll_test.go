@@ -0,0 +1,183 @@
+package cmdutil
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPollUntil(t *testing.T) {
+	var waits []time.Duration
+	last := time.Now()
+	checks := 0
+	s := &PollStrategy{Interval: time.Millisecond, MaxInterval: 4 * time.Millisecond, Factor: 2}
+	err := PollUntil(context.Background(), s, func(context.Context) (bool, error) {
+		now := time.Now()
+		if checks > 0 {
+			waits = append(waits, now.Sub(last))
+		}
+		last = now
+		checks++
+		return checks == 5, nil
+	})
+	if err != nil || checks != 5 {
+		t.Fatalf("PollUntil = %v after %d checks, want nil after 5", err, checks)
+	}
+	// Waits of 1, 2, 4 and 4 (capped) ms; timers may only run late.
+	for i, want := range []time.Duration{1, 2, 4, 4} {
+		if waits[i] < want*time.Millisecond {
+			t.Errorf("wait %d = %s, want at least %dms", i, waits[i], want)
+		}
+	}
+}
+
+func TestPollUntilErrors(t *testing.T) {
+	boom := errors.New("boom")
+	err := PollUntil(context.Background(), &PollStrategy{Interval: time.Millisecond}, func(context.Context) (bool, error) {
+		return false, boom
+	})
+	if err != boom {
+		t.Errorf("check error: got %v, want boom", err)
+	}
+
+	err = PollUntil(context.Background(), &PollStrategy{Interval: time.Millisecond, Timeout: 20 * time.Millisecond}, func(context.Context) (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, ErrPollTimeout) {
+		t.Errorf("timeout: got %v, want ErrPollTimeout", err)
+	}
+
+	// A check whose request the timeout cancels also reports the timeout.
+	err = PollUntil(context.Background(), &PollStrategy{Interval: time.Millisecond, Timeout: 20 * time.Millisecond}, func(ctx context.Context) (bool, error) {
+		<-ctx.Done()
+		return false, ctx.Err()
+	})
+	if !errors.Is(err, ErrPollTimeout) {
+		t.Errorf("cancelled check: got %v, want ErrPollTimeout", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = PollUntil(ctx, &PollStrategy{Interval: time.Hour}, func(context.Context) (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled: got %v, want context.Canceled", err)
+	}
+}
+
+func TestPollStrategyNext(t *testing.T) {
+	t.Cleanup(func() { current = Settings{} })
+
+	s := &PollStrategy{Jitter: 0.2}
+	for range 100 {
+		if d := s.next(time.Second); d < 800*time.Millisecond || d > 1200*time.Millisecond {
+			t.Fatalf("next(1s) with 20%% jitter = %s", d)
+		}
+	}
+
+	current = Settings{Poll: PollStrategy{Interval: 10 * time.Second}}
+	if d := (&PollStrategy{}).next(time.Second); d != 10*time.Second {
+		t.Errorf("next(1s) with a 10s --poll-interval = %s, want 10s", d)
+	}
+}
+
+func TestPollOverride(t *testing.T) {
+	s := &PollStrategy{Interval: 5 * time.Second, MaxInterval: 30 * time.Second, Factor: 1.5, Jitter: 0.1, Timeout: 10 * time.Minute}
+	s.override(PollStrategy{})
+	if want := (PollStrategy{Interval: 5 * time.Second, MaxInterval: 30 * time.Second, Factor: 1.5, Jitter: 0.1, Timeout: 10 * time.Minute}); *s != want {
+		t.Errorf("no overrides: got %+v, want %+v", *s, want)
+	}
+
+	s.override(PollStrategy{Interval: time.Second, MaxInterval: time.Minute, Factor: 3, Jitter: 0.5, Timeout: time.Hour})
+	// The interval floor applies in next; the longer timeout is ignored.
+	if want := (PollStrategy{Interval: 5 * time.Second, MaxInterval: time.Minute, Factor: 3, Jitter: 0.5, Timeout: 10 * time.Minute}); *s != want {
+		t.Errorf("overrides: got %+v, want %+v", *s, want)
+	}
+
+	s = &PollStrategy{Interval: time.Second}
+	s.override(PollStrategy{Timeout: time.Minute})
+	if s.Timeout != time.Minute {
+		t.Errorf("timeout without one: got %s, want 1m", s.Timeout)
+	}
+}
+
+func TestPollOverrideFixed(t *testing.T) {
+	s := &PollStrategy{Interval: 15 * time.Second, Fixed: true}
+	s.override(PollStrategy{Interval: time.Minute, MaxInterval: time.Hour, Factor: 2, Jitter: 0.5, Timeout: 5 * time.Minute})
+	// Only the interval floor, applied in next, affects a fixed poll.
+	if want := (PollStrategy{Interval: 15 * time.Second, Fixed: true}); *s != want {
+		t.Errorf("got %+v, want %+v", *s, want)
+	}
+}
+
+func TestPollUntilFixedIgnoresTimeout(t *testing.T) {
+	t.Cleanup(func() { current = Settings{} })
+	current = Settings{Poll: PollStrategy{Timeout: 5 * time.Millisecond}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := PollUntil(ctx, &PollStrategy{Interval: time.Millisecond, Fixed: true}, func(context.Context) (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want the watch to run until ctx is done", err)
+	}
+}
+
+func TestPollUntilUsesSettings(t *testing.T) {
+	t.Cleanup(func() { current = Settings{} })
+	current = Settings{Poll: PollStrategy{Timeout: 20 * time.Millisecond}}
+
+	err := PollUntil(context.Background(), &PollStrategy{Interval: time.Millisecond}, func(context.Context) (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, ErrPollTimeout) {
+		t.Errorf("got %v, want ErrPollTimeout from --poll-timeout", err)
+	}
+}
+
+func TestPollSettings(t *testing.T) {
+	t.Cleanup(func() {
+		PollInterval, PollMaxInterval, PollBackoff, PollJitter, PollTimeout = 0, 0, 0, 0, 0
+	})
+
+	t.Setenv("CNAP_POLL_INTERVAL", "30s")
+	t.Setenv("CNAP_POLL_MAX_INTERVAL", "2m")
+	t.Setenv("CNAP_POLL_BACKOFF", "2")
+	t.Setenv("CNAP_POLL_JITTER", "0.25")
+	t.Setenv("CNAP_POLL_TIMEOUT", "1h")
+	want := PollStrategy{Interval: 30 * time.Second, MaxInterval: 2 * time.Minute, Factor: 2, Jitter: 0.25, Timeout: time.Hour}
+	if s, err := pollSettings(); err != nil || s != want {
+		t.Errorf("env: got %+v, %v; want %+v", s, err, want)
+	}
+
+	PollInterval, PollMaxInterval, PollBackoff, PollJitter, PollTimeout = 5*time.Second, time.Minute, 1.5, 0.1, 10*time.Minute
+	want = PollStrategy{Interval: 5 * time.Second, MaxInterval: time.Minute, Factor: 1.5, Jitter: 0.1, Timeout: 10 * time.Minute}
+	if s, err := pollSettings(); err != nil || s != want {
+		t.Errorf("flags over env: got %+v, %v; want %+v", s, err, want)
+	}
+	PollInterval, PollMaxInterval, PollBackoff, PollJitter, PollTimeout = 0, 0, 0, 0, 0
+
+	for env, val := range map[string]string{
+		"CNAP_POLL_INTERVAL":     "soon",
+		"CNAP_POLL_MAX_INTERVAL": "-1s",
+		"CNAP_POLL_BACKOFF":      "0.5",
+		"CNAP_POLL_JITTER":       "2",
+		"CNAP_POLL_TIMEOUT":      "x",
+	} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, val)
+			if _, err := pollSettings(); err == nil || !strings.Contains(err.Error(), env) {
+				t.Errorf("%s=%s: got %v, want an error naming it", env, val, err)
+			}
+		})
+	}
+
+	PollBackoff = 0.5
+	if _, err := pollSettings(); err == nil || !strings.Contains(err.Error(), "--poll-backoff") {
+		t.Errorf("--poll-backoff 0.5: got %v, want an error naming the flag", err)
+	}
+}
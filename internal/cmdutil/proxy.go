@@ -0,0 +1,156 @@
+package cmdutil
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/cnap-tech/cli/internal/debug"
+	"github.com/cnap-tech/cli/internal/useragent"
+)
+
+// Proxy holds the CLI-level --proxy flag value. When empty, the
+// HTTPS_PROXY/HTTP_PROXY/NO_PROXY environment variables apply.
+var Proxy string
+
+// parseProxy validates a --proxy URL.
+func parseProxy(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("--proxy: invalid URL %q, e.g. http://proxy.example.com:3128", raw)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return u, nil
+	}
+	return nil, fmt.Errorf("--proxy: unsupported scheme %q (use http, https, socks5 or socks5h)", u.Scheme)
+}
+
+// proxyFor returns the proxy for a connection to target, or nil for none.
+func proxyFor(target *url.URL) (*url.URL, error) {
+	if Proxy != "" {
+		return parseProxy(Proxy)
+	}
+	// ProxyFromEnvironment only knows http and https.
+	u := *target
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	}
+	return http.ProxyFromEnvironment(&http.Request{URL: &u})
+}
+
+// WebSocketClient returns the HTTP client to dial the websocket at target
+// through --proxy or the environment's proxy. Go's transport would forward
+// a ws:// upgrade to an HTTP proxy as a plain request, which most proxies
+// refuse, so both ws and wss are tunneled with CONNECT instead.
+func WebSocketClient(target string) (*http.Client, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WebSocket URL: %w", err)
+	}
+	proxy, err := proxyFor(u)
+	if err != nil {
+		return nil, err
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	switch {
+	case proxy == nil:
+	case proxy.Scheme == "socks5" || proxy.Scheme == "socks5h":
+		t.Proxy = http.ProxyURL(proxy)
+	default:
+		dialer := &net.Dialer{}
+		t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialConnect(ctx, dialer, proxy, addr)
+		}
+	}
+
+	if !debug.Enabled {
+		return &http.Client{Transport: t}, nil
+	}
+	return &http.Client{Transport: &debug.Transport{Inner: t}}, nil
+}
+
+// dialConnect opens a tunnel to addr through an HTTP(S) proxy.
+func dialConnect(ctx context.Context, dialer *net.Dialer, proxy *url.URL, addr string) (net.Conn, error) {
+	proxyAddr := proxy.Host
+	if proxy.Port() == "" {
+		port := "80"
+		if proxy.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(proxy.Hostname(), port)
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to proxy %s: %w", proxy.Host, err)
+	}
+	if proxy.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxy.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("connecting to proxy %s: %w", proxy.Host, err)
+		}
+		conn = tlsConn
+	}
+
+	// Unblock the handshake below if ctx ends first.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{"User-Agent": []string{useragent.String()}},
+	}
+	if proxy.User != nil {
+		password, _ := proxy.User.Password()
+		creds := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+creds)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT to %s: %w", addr, err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT to %s: %w", addr, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT to %s: %s", addr, resp.Status)
+	}
+	if ctx.Err() != nil {
+		conn.Close()
+		return nil, ctx.Err()
+	}
+	if br.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn is a net.Conn whose first reads drain data the proxy sent
+// right after its CONNECT response.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
@@ -0,0 +1,103 @@
+package cmdutil
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+func TestWebSocketClientTunnelsThroughProxy(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		typ, msg, err := conn.Read(r.Context())
+		if err != nil {
+			return
+		}
+		_ = conn.Write(r.Context(), typ, msg)
+	}))
+	defer backend.Close()
+
+	connects := make(chan *http.Request, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+			return
+		}
+		connects <- r
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+		client, buf, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return
+		}
+		defer client.Close()
+		_, _ = client.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		go func() { _, _ = io.Copy(upstream, buf) }()
+		_, _ = io.Copy(client, upstream)
+	}))
+	defer proxy.Close()
+
+	t.Cleanup(func() { Proxy = "" })
+	Proxy = strings.Replace(proxy.URL, "http://", "http://user:secret@", 1)
+
+	target := "ws" + strings.TrimPrefix(backend.URL, "http")
+	httpClient, err := WebSocketClient(target)
+	if err != nil {
+		t.Fatalf("WebSocketClient: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.Dial(ctx, target, &websocket.DialOptions{HTTPClient: httpClient})
+	if err != nil {
+		t.Fatalf("Dial through proxy: %v", err)
+	}
+	defer conn.CloseNow()
+	if err := conn.Write(ctx, websocket.MessageText, []byte("ping")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, msg, err := conn.Read(ctx); err != nil || string(msg) != "ping" {
+		t.Fatalf("Read = %q, %v; want echo", msg, err)
+	}
+
+	req := <-connects
+	if want := strings.TrimPrefix(backend.URL, "http://"); req.Host != want {
+		t.Errorf("CONNECT to %q, want %q", req.Host, want)
+	}
+	if got := req.Header.Get("Proxy-Authorization"); got != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Errorf("Proxy-Authorization = %q", got)
+	}
+}
+
+func TestParseProxy(t *testing.T) {
+	for raw, wantErr := range map[string]string{
+		"http://proxy:3128":    "",
+		"socks5h://proxy:1080": "",
+		"proxy:3128":           "--proxy: invalid URL",
+		"ftp://proxy":          "--proxy: unsupported scheme",
+		"http://":              "--proxy: invalid URL",
+	} {
+		_, err := parseProxy(raw)
+		if wantErr == "" && err != nil {
+			t.Errorf("parseProxy(%q): %v", raw, err)
+		}
+		if wantErr != "" && (err == nil || !strings.HasPrefix(err.Error(), wantErr)) {
+			t.Errorf("parseProxy(%q) = %v, want %q", raw, err, wantErr)
+		}
+	}
+}
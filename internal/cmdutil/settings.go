@@ -0,0 +1,89 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/debug"
+	"github.com/cnap-tech/cli/internal/output"
+)
+
+// Timeout holds the CLI-level --timeout flag value.
+var Timeout time.Duration
+
+// Settings are the cross-cutting options derived from global flags and the
+// environment, resolved once by Setup before any command runs. Color is
+// resolved into the output package instead (see output.ColorEnabled).
+type Settings struct {
+	// Format is the format from --output or CNAP_OUTPUT_FORMAT; empty means
+	// the config file decides (see GetOutputFormat).
+	Format output.Format
+	// Timeout bounds the whole command; 0 means no limit.
+	Timeout time.Duration
+	// Poll holds the --poll-* overrides of every PollUntil strategy; zero
+	// fields leave each command's own. Poll.Interval is the least time
+	// between checks.
+	Poll PollStrategy
+}
+
+var (
+	current       Settings
+	resolved      bool
+	cancelTimeout context.CancelFunc = func() {}
+
+	// commandCtx is the context returned by Setup, for work done on the
+	// command's behalf outside its RunE, such as NewClient's
+	// --from-kube-context lookup.
+	commandCtx = context.Background()
+)
+
+// Setup validates the global flags, resolves Settings, and returns the
+// context commands should run with (bounded by --timeout, if set).
+// Call once from the root command's PersistentPreRunE, and Teardown when
+// the command has finished.
+func Setup(ctx context.Context, noColor bool) (context.Context, error) {
+	format, err := flagFormat()
+	if err != nil {
+		return ctx, err
+	}
+	if Timeout < 0 {
+		return ctx, fmt.Errorf("--timeout: must not be negative")
+	}
+	poll, err := pollSettings()
+	if err != nil {
+		return ctx, err
+	}
+
+	if Proxy != "" {
+		proxy, err := parseProxy(Proxy)
+		if err != nil {
+			return ctx, err
+		}
+		debug.SetProxy(proxy)
+	}
+
+	output.InitColor(noColor)
+	current = Settings{Format: format, Timeout: Timeout, Poll: poll}
+	resolved = true
+
+	if Timeout > 0 {
+		ctx, cancelTimeout = context.WithTimeout(ctx, Timeout)
+	}
+	commandCtx = ctx
+	return ctx, nil
+}
+
+// Current returns the settings resolved by Setup.
+func Current() Settings {
+	return current
+}
+
+// Teardown releases the --timeout timer started by Setup and forgets the
+// invocation's state.
+func Teardown() {
+	cancelTimeout()
+	cancelTimeout = func() {}
+	commandCtx = context.Background()
+	kubeWorkspace = ""
+}
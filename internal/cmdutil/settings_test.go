@@ -0,0 +1,78 @@
+package cmdutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/output"
+)
+
+func TestSetup(t *testing.T) {
+	t.Cleanup(func() {
+		OutputFormat, Timeout = "", 0
+		current, resolved = Settings{}, false
+		Teardown()
+	})
+
+	tests := []struct {
+		name      string
+		flag, env string
+		timeout   time.Duration
+		want      output.Format
+		wantErr   string
+	}{
+		{"defaults", "", "", 0, "", ""},
+		{"flag", "json", "quiet", 0, output.FormatJSON, ""},
+		{"env", "", "quiet", 0, output.FormatQuiet, ""},
+		{"bad flag", "jsn", "", 0, "", `--output: unknown output format "jsn" (valid: table, json, quiet, jsonpath=TEMPLATE)`},
+		{"bad env", "", "yaml", 0, "", `CNAP_OUTPUT_FORMAT: unknown output format "yaml"`},
+		{"negative timeout", "", "", -time.Second, "", "--timeout: must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			OutputFormat, Timeout = tt.flag, tt.timeout
+			t.Setenv("CNAP_OUTPUT_FORMAT", tt.env)
+
+			_, err := Setup(context.Background(), true)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Errorf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Setup: %v", err)
+			}
+			if got := Current(); got.Format != tt.want {
+				t.Errorf("Current() = %+v, want format %q", got, tt.want)
+			}
+			if output.ColorEnabled() {
+				t.Error("color enabled despite noColor")
+			}
+		})
+	}
+}
+
+func TestSetupTimeout(t *testing.T) {
+	t.Cleanup(func() {
+		Timeout = 0
+		current, resolved = Settings{}, false
+	})
+	Timeout = time.Minute
+
+	ctx, err := Setup(context.Background(), true)
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("context has no deadline with --timeout set")
+	}
+
+	Teardown()
+	if ctx.Err() == nil {
+		t.Error("Teardown did not release the timeout context")
+	}
+}
@@ -0,0 +1,87 @@
+package cmdutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Success statuses for mutating requests. The API may run any of these
+// asynchronously and answer 200 or 202 with a job handle instead of the
+// documented status, so callers accept the whole set.
+var (
+	// DeleteStatuses are accepted for deletes, documented as 204.
+	DeleteStatuses = []int{200, 202, 204}
+	// AsyncStatuses are accepted for workflow-starting requests, documented as 202.
+	AsyncStatuses = []int{200, 202}
+)
+
+// Succeeded reports whether status is one of accepted. If it is, and the
+// body names the async job or workflow handling the request, that ID is
+// returned too.
+func Succeeded(status int, body []byte, accepted ...int) (jobID string, ok bool) {
+	if !slices.Contains(accepted, status) {
+		return "", false
+	}
+	return jobIDFromBody(body), true
+}
+
+// QueuedAs returns " as <job>" to append to a "... started" message, or ""
+// when the API didn't name a job.
+func QueuedAs(jobID string) string {
+	if jobID == "" {
+		return ""
+	}
+	return " as " + jobID
+}
+
+// PrintDeleted reports a finished deletion of what ("Cluster abc"), or a
+// queued one if the API handed back a job for it.
+func PrintDeleted(what, jobID string) {
+	if jobID != "" {
+		fmt.Printf("%s deletion queued%s.\n", what, QueuedAs(jobID))
+		return
+	}
+	fmt.Printf("%s deleted.\n", what)
+}
+
+// jobIDKeys are the fields an async response may carry its handle in,
+// either at the top level or under "data".
+var jobIDKeys = []string{"job_id", "jobId", "workflow_id", "workflowId", "workflow_name", "workflowName"}
+
+func jobIDFromBody(body []byte) string {
+	var doc map[string]any
+	if json.Unmarshal(body, &doc) != nil {
+		return ""
+	}
+	if data, ok := doc["data"].(map[string]any); ok {
+		if id := jobIDFromMap(data); id != "" {
+			return id
+		}
+	}
+	return jobIDFromMap(doc)
+}
+
+func jobIDFromMap(m map[string]any) string {
+	for _, k := range jobIDKeys {
+		if s, ok := m[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	// {"job": {"id": "..."}} or {"workflow": {"name": "..."}}
+	for _, k := range []string{"job", "workflow"} {
+		switch v := m[k].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case map[string]any:
+			for _, f := range []string{"id", "name"} {
+				if s, ok := v[f].(string); ok && s != "" {
+					return s
+				}
+			}
+		}
+	}
+	return ""
+}
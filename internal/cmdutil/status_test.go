@@ -0,0 +1,28 @@
+package cmdutil
+
+import "testing"
+
+func TestSucceeded(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantJob string
+		wantOK  bool
+	}{
+		{"no content", 204, ``, "", true},
+		{"job id", 202, `{"job_id":"job-xyz"}`, "job-xyz", true},
+		{"nested workflow", 200, `{"data":{"workflow":{"name":"delete-abc"}}}`, "delete-abc", true},
+		{"deleted object", 200, `{"id":"clu_1","name":"prod"}`, "", true},
+		{"not accepted", 201, `{"job_id":"job-xyz"}`, "", false},
+		{"error", 409, `{"error":{"message":"in use"}}`, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, ok := Succeeded(tt.status, []byte(tt.body), DeleteStatuses...)
+			if job != tt.wantJob || ok != tt.wantOK {
+				t.Errorf("Succeeded(%d, %s) = %q, %v; want %q, %v", tt.status, tt.body, job, ok, tt.wantJob, tt.wantOK)
+			}
+		})
+	}
+}
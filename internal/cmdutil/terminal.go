@@ -0,0 +1,18 @@
+package cmdutil
+
+import "sync/atomic"
+
+var rawTerminal atomic.Bool
+
+// MarkRawTerminal records that the command put the terminal in raw mode,
+// e.g. for an exec session. Output after the command, like the update
+// notice, is then left out: the remote side may have left the screen in
+// any state.
+func MarkRawTerminal() {
+	rawTerminal.Store(true)
+}
+
+// UsedRawTerminal reports whether MarkRawTerminal was called.
+func UsedRawTerminal() bool {
+	return rawTerminal.Load()
+}
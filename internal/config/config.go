@@ -1,10 +1,16 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/cnap-tech/cli/internal/output"
 	"gopkg.in/yaml.v3"
 )
 
@@ -16,11 +22,24 @@ const (
 )
 
 type Config struct {
+	Version         int    `yaml:"version"` // schema version; see CurrentVersion
 	APIURL          string `yaml:"api_url"`
 	AuthURL         string `yaml:"auth_url,omitempty"`
 	ActiveWorkspace string `yaml:"active_workspace,omitempty"`
 	Auth            Auth   `yaml:"auth"`
 	Output          Output `yaml:"output"`
+	HideHostname    bool   `yaml:"hide_hostname,omitempty"` // replace the hostname in the User-Agent with "hidden"
+
+	// APIURLFlag and AuthURLFlag hold --api-url and --auth-url for this
+	// invocation. They take precedence over the environment and the file,
+	// and are never saved.
+	APIURLFlag  string `yaml:"-"`
+	AuthURLFlag string `yaml:"-"`
+
+	// path and data are the file this config was loaded from, so a bad
+	// value can be reported with its line.
+	path string
+	data []byte
 }
 
 type Auth struct {
@@ -33,8 +52,9 @@ type Output struct {
 
 func DefaultConfig() *Config {
 	return &Config{
-		APIURL: DefaultAPIURL,
-		Output: Output{Format: "table"},
+		Version: CurrentVersion,
+		APIURL:  DefaultAPIURL,
+		Output:  Output{Format: "table"},
 	}
 }
 
@@ -46,6 +66,11 @@ func configPath() (string, error) {
 	return filepath.Join(home, configDir, configFile), nil
 }
 
+// Path returns the path of the config file, which may not exist yet.
+func Path() (string, error) {
+	return configPath()
+}
+
 func ConfigDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -54,6 +79,35 @@ func ConfigDir() (string, error) {
 	return filepath.Join(home, configDir), nil
 }
 
+// KubeCredentialDir is where `cnap kube credential` caches cluster
+// credentials. auth logout removes it.
+func KubeCredentialDir() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "kube"), nil
+}
+
+// CacheScope returns a short ID for the API URL and token this config
+// uses. Caches of server data are kept per scope, so another CNAP instance
+// or account never reads entries written for this one.
+func (c *Config) CacheScope() string {
+	sum := sha256.Sum256([]byte(c.BaseURL() + "\x00" + c.Token()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Exists reports whether a config file has been written, i.e. whether the
+// user has logged in (or otherwise saved settings) at least once.
+func Exists() bool {
+	path, err := configPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
 func Load() (*Config, error) {
 	path, err := configPath()
 	if err != nil {
@@ -68,13 +122,146 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("reading config: %w", err)
 	}
 
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Tag == "!!null" {
+		return DefaultConfig(), nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parsing config: expected a mapping of keys, got %s", root.Tag)
+	}
+
+	migrated, err := migrate(root)
+	if err != nil {
+		return nil, fmt.Errorf("parsing config: %w", err)
+	}
+	if migrated {
+		if data, err = yaml.Marshal(&doc); err != nil {
+			return nil, fmt.Errorf("marshaling config: %w", err)
+		}
+		// Write back once so the migration doesn't rerun every command.
+		// A read-only config still works; it's just migrated in memory.
+		if err := WriteFileAtomic(path, data, 0o600); err != nil {
+			slog.Debug("could not save migrated config", "error", err)
+		}
+	}
+
 	cfg := DefaultConfig()
 	if err := yaml.Unmarshal(data, cfg); err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
 	}
+	cfg.path, cfg.data = path, data
 	return cfg, nil
 }
 
+// fieldError is an invalid value in the config file.
+type fieldError struct {
+	path  string
+	line  int // 0 if unknown
+	field string
+	msg   string
+}
+
+func (e *fieldError) Error() string {
+	if e.path == "" {
+		return fmt.Sprintf("invalid config: %s %s", e.field, e.msg)
+	}
+	loc := e.path
+	if e.line > 0 {
+		loc = fmt.Sprintf("%s:%d", e.path, e.line)
+	}
+	return fmt.Sprintf("invalid config %s: %s %s", loc, e.field, e.msg)
+}
+
+// fieldError reports a bad value of a config file key with its line.
+func (c *Config) fieldError(field, format string, args ...any) error {
+	return &fieldError{path: c.path, line: fieldLine(c.data, field), field: field, msg: fmt.Sprintf(format, args...)}
+}
+
+// CheckFile checks every value set in the config file itself, so a bad one
+// is reported with the file, line and key to fix. Unlike Validate it
+// ignores environment variables and flags. Load doesn't call it: commands
+// check only the values they use (see CheckToken and FileOutputFormat),
+// so a bad key never locks out the command that would overwrite it.
+func (c *Config) CheckFile() error {
+	if c.APIURL != "" && validateURL("API URL", c.APIURL) != nil {
+		return c.fieldError("api_url", "%q is not an absolute http(s) URL", c.APIURL)
+	}
+	if c.AuthURL != "" && validateURL("auth URL", c.AuthURL) != nil {
+		return c.fieldError("auth_url", "%q is not an absolute http(s) URL", c.AuthURL)
+	}
+	if _, err := c.FileOutputFormat(); err != nil {
+		return err
+	}
+	return c.checkFileToken()
+}
+
+// CheckToken reports a stored token that is clearly unusable, unless
+// CNAP_API_TOKEN replaces it.
+func (c *Config) CheckToken() error {
+	if os.Getenv("CNAP_API_TOKEN") != "" {
+		return nil
+	}
+	return c.checkFileToken()
+}
+
+func (c *Config) checkFileToken() error {
+	if problem := tokenProblem(c.Auth.Token); problem != "" {
+		return c.fieldError("auth.token", "looks damaged (%s). Run: cnap auth login", problem)
+	}
+	return nil
+}
+
+// FileOutputFormat returns the output.format key, or "" if it isn't set.
+func (c *Config) FileOutputFormat() (output.Format, error) {
+	if c.Output.Format == "" {
+		return "", nil
+	}
+	f, err := output.ParseFormat(c.Output.Format)
+	if err != nil {
+		return "", c.fieldError("output.format", "is invalid: %v", err)
+	}
+	return f, nil
+}
+
+// patPrefix starts every Personal Access Token.
+const patPrefix = "cnap_pat_"
+
+// tokenProblem returns why a stored token is clearly unusable, e.g. cut
+// off or padded when pasted, or "" if it looks fine.
+func tokenProblem(token string) string {
+	switch {
+	case token == "":
+		return ""
+	case strings.ContainsAny(token, " \t\r\n\"'"):
+		return "contains whitespace or quotes"
+	case strings.HasPrefix(patPrefix, token):
+		return "a PAT with nothing after " + patPrefix
+	case strings.HasPrefix(token, "eyJ") && strings.Count(token, ".") != 2:
+		return fmt.Sprintf("a JWT with %d of 3 parts", strings.Count(token, ".")+1)
+	}
+	return ""
+}
+
+// fieldLine returns the line of a dotted key (e.g. "output.format") in a
+// YAML document, or 0 if it isn't there.
+func fieldLine(data []byte, field string) int {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return 0
+	}
+	n := doc.Content[0]
+	for _, key := range strings.Split(field, ".") {
+		if n = mappingValue(n, key); n == nil {
+			return 0
+		}
+	}
+	return n.Line
+}
+
 func (c *Config) Save() error {
 	path, err := configPath()
 	if err != nil {
@@ -85,12 +272,38 @@ func (c *Config) Save() error {
 		return fmt.Errorf("creating config directory: %w", err)
 	}
 
+	if c.Version < CurrentVersion {
+		c.Version = CurrentVersion // the struct is always in the current schema
+	}
 	data, err := yaml.Marshal(c)
 	if err != nil {
 		return fmt.Errorf("marshaling config: %w", err)
 	}
 
-	return os.WriteFile(path, data, 0o600)
+	return WriteFileAtomic(path, data, 0o600)
+}
+
+// WriteFileAtomic writes data to a temp file in the same directory and
+// renames it over path, so an interrupted write never leaves a truncated file.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }() // no-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
 
 // Token returns the API token from env var or config file.
@@ -102,23 +315,71 @@ func (c *Config) Token() string {
 	return c.Auth.Token
 }
 
-// BaseURL returns the API base URL from env var or config file.
-// Env var CNAP_API_URL takes priority.
+// BaseURL returns the API base URL: --api-url, else CNAP_API_URL, else
+// the api_url config key.
 func (c *Config) BaseURL() string {
+	if c.APIURLFlag != "" {
+		return c.APIURLFlag
+	}
 	if u := os.Getenv("CNAP_API_URL"); u != "" {
 		return u
 	}
 	return c.APIURL
 }
 
-// AuthBaseURL returns the auth/dashboard base URL from env var or config file.
-// Env var CNAP_AUTH_URL takes priority. Used for device flow auth endpoints.
+// AuthBaseURL returns the auth/dashboard base URL. Used for device flow
+// auth endpoints and the exec WebSocket handler.
+// --auth-url takes priority, then CNAP_AUTH_URL, then the auth_url config
+// key; otherwise it is derived from the effective API URL, including
+// --api-url and CNAP_API_URL (see DeriveAuthURL).
 func (c *Config) AuthBaseURL() string {
+	if c.AuthURLFlag != "" {
+		return c.AuthURLFlag
+	}
 	if u := os.Getenv("CNAP_AUTH_URL"); u != "" {
 		return u
 	}
 	if c.AuthURL != "" {
 		return c.AuthURL
 	}
-	return DefaultAuthURL
+	return DeriveAuthURL(c.BaseURL())
+}
+
+// TokensURL returns the dashboard page for creating personal access
+// tokens, given the dashboard URL (see AuthBaseURL).
+func TokensURL(authURL string) string {
+	return strings.TrimSuffix(authURL, "/") + "/settings/tokens"
+}
+
+// DeriveAuthURL guesses the dashboard URL for an API URL by dropping a
+// leading "api." host label (https://api.cnap.tech → https://cnap.tech).
+// Self-hosted setups that serve both from one origin get the API URL back.
+func DeriveAuthURL(apiURL string) string {
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Host == "" {
+		return DefaultAuthURL
+	}
+	u.Host = strings.TrimPrefix(u.Host, "api.")
+	u.Path = ""
+	u.RawQuery = ""
+	return u.String()
+}
+
+// Validate checks that the effective API and auth URLs are absolute
+// http(s) URLs, so a typo fails up front instead of as a dial error.
+// output.format is checked only when it is the format used, since --output
+// and CNAP_OUTPUT_FORMAT override it (see FileOutputFormat).
+func (c *Config) Validate() error {
+	if err := validateURL("API URL", c.BaseURL()); err != nil {
+		return err
+	}
+	return validateURL("auth URL", c.AuthBaseURL())
+}
+
+func validateURL(name, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid %s %q: expected an absolute http(s) URL", name, raw)
+	}
+	return nil
 }
@@ -0,0 +1,315 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeriveAuthURL(t *testing.T) {
+	tests := []struct {
+		apiURL string
+		want   string
+	}{
+		{DefaultAPIURL, DefaultAuthURL},
+		{"https://api.example.com", "https://example.com"},
+		{"https://api.example.com/", "https://example.com"},
+		{"http://api.localhost:8080", "http://localhost:8080"},
+		{"https://cnap.internal.corp", "https://cnap.internal.corp"},
+		{"https://example.com/api", "https://example.com"},
+		{"not a url", DefaultAuthURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.apiURL, func(t *testing.T) {
+			if got := DeriveAuthURL(tt.apiURL); got != tt.want {
+				t.Errorf("DeriveAuthURL(%q) = %q, want %q", tt.apiURL, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokensURL(t *testing.T) {
+	for authURL, want := range map[string]string{
+		DefaultAuthURL:              "https://cnap.tech/settings/tokens",
+		"https://dash.example.com/": "https://dash.example.com/settings/tokens",
+		"http://localhost:3000":     "http://localhost:3000/settings/tokens",
+	} {
+		if got := TokensURL(authURL); got != want {
+			t.Errorf("TokensURL(%q) = %q, want %q", authURL, got, want)
+		}
+	}
+}
+
+func TestAuthBaseURLPrecedence(t *testing.T) {
+	cfg := &Config{APIURL: "https://api.example.com"}
+
+	t.Setenv("CNAP_AUTH_URL", "")
+	t.Setenv("CNAP_API_URL", "")
+	if got, want := cfg.AuthBaseURL(), "https://example.com"; got != want {
+		t.Errorf("derived: got %q, want %q", got, want)
+	}
+
+	t.Setenv("CNAP_API_URL", "https://api.staging.example.com")
+	if got, want := cfg.AuthBaseURL(), "https://staging.example.com"; got != want {
+		t.Errorf("derived from env API URL: got %q, want %q", got, want)
+	}
+
+	cfg.APIURLFlag = "https://api.selfhosted.example.com"
+	if got, want := cfg.BaseURL(), cfg.APIURLFlag; got != want {
+		t.Errorf("--api-url over env: got %q, want %q", got, want)
+	}
+	if got, want := cfg.AuthBaseURL(), "https://selfhosted.example.com"; got != want {
+		t.Errorf("derived from --api-url: got %q, want %q", got, want)
+	}
+
+	cfg.AuthURL = "https://dash.example.com"
+	if got := cfg.AuthBaseURL(); got != cfg.AuthURL {
+		t.Errorf("config key: got %q, want %q", got, cfg.AuthURL)
+	}
+
+	t.Setenv("CNAP_AUTH_URL", "https://auth.example.com")
+	if got, want := cfg.AuthBaseURL(), "https://auth.example.com"; got != want {
+		t.Errorf("env override: got %q, want %q", got, want)
+	}
+
+	cfg.AuthURLFlag = "https://login.example.com"
+	if got := cfg.AuthBaseURL(); got != cfg.AuthURLFlag {
+		t.Errorf("--auth-url override: got %q, want %q", got, cfg.AuthURLFlag)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Setenv("CNAP_AUTH_URL", "")
+	t.Setenv("CNAP_API_URL", "")
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"defaults", *DefaultConfig(), false},
+		{"custom api", Config{APIURL: "http://localhost:8080"}, false},
+		{"missing scheme", Config{APIURL: "api.example.com"}, true},
+		{"bad auth url", Config{APIURL: DefaultAPIURL, AuthURL: "ftp://example.com"}, true},
+		{"bad output format is left to GetOutputFormat", Config{APIURL: DefaultAPIURL, Output: Output{Format: "yaml"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.yaml")
+	if err := os.WriteFile(path, []byte("old"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteFileAtomic(path, []byte("new"), 0o600); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Errorf("content = %q, %v; want new", data, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temp file left behind: %d entries", len(entries))
+	}
+}
+
+func TestLoadMigratesV0(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, configDir, configFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	v0 := `# Edited by hand.
+api_url: https://api.cnap.tech
+auth_url: https://cnap.tech
+active_workspace: ws_1 # prod
+auth:
+  token: cnap_pat_abc
+output:
+  format: json
+future_key: kept
+`
+	if err := os.WriteFile(path, []byte(v0), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Version = %d, want %d", cfg.Version, CurrentVersion)
+	}
+	if cfg.AuthURL != "" {
+		t.Errorf("AuthURL = %q, want the redundant default dropped", cfg.AuthURL)
+	}
+	if cfg.ActiveWorkspace != "ws_1" || cfg.Auth.Token != "cnap_pat_abc" || cfg.Output.Format != "json" {
+		t.Errorf("migrated config lost fields: %+v", cfg)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the version is added and auth_url dropped: comments and key
+	// order are kept.
+	want := `# Edited by hand.
+api_url: https://api.cnap.tech
+active_workspace: ws_1 # prod
+auth:
+    token: cnap_pat_abc
+output:
+    format: json
+future_key: kept
+version: 1
+`
+	if string(written) != want {
+		t.Errorf("migrated file:\n%s\nwant:\n%s", written, want)
+	}
+
+	// Already migrated: loading again leaves the file alone.
+	if _, err := Load(); err != nil {
+		t.Fatalf("second Load: %v", err)
+	}
+	again, _ := os.ReadFile(path)
+	if string(again) != string(written) {
+		t.Errorf("second Load rewrote the file:\n%s", again)
+	}
+}
+
+func TestLoadMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string // "" to load the defaults
+	}{
+		{"comment only", "# nothing yet\n", ""},
+		{"bad version", "version: two\napi_url: https://api.cnap.tech\n", "invalid config version two"},
+		{"not a mapping", "- api_url\n", "expected a mapping of keys"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			path := filepath.Join(home, configDir, configFile)
+			if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr == "" {
+				if err != nil || cfg.APIURL != DefaultAPIURL {
+					t.Errorf("Load = %+v, %v; want the defaults", cfg, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadKeepsCustomAuthURL(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, configDir, configFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	v0 := "api_url: https://api.example.com\nauth_url: https://dash.example.com\n"
+	if err := os.WriteFile(path, []byte(v0), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.AuthURL != "https://dash.example.com" {
+		t.Errorf("AuthURL = %q, want the explicit value kept", cfg.AuthURL)
+	}
+}
+
+func TestCheckFileReportsInvalidField(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string // expected error suffix after the path
+	}{
+		{"output format", "version: 1\napi_url: https://api.cnap.tech\noutput:\n  format: xml\n", `:4: output.format is invalid`},
+		{"api url", "version: 1\napi_url: api.cnap.tech\n", `:2: api_url "api.cnap.tech" is not an absolute http(s) URL`},
+		{"truncated pat", "version: 1\napi_url: https://api.cnap.tech\nauth:\n  token: cnap_pa\n", `:4: auth.token looks damaged (a PAT with nothing after cnap_pat_)`},
+		{"pasted with a space", "version: 1\napi_url: https://api.cnap.tech\nauth:\n  token: \"cnap_pat_abc \"\n", `:4: auth.token looks damaged (contains whitespace or quotes)`},
+		{"truncated jwt", "version: 1\napi_url: https://api.cnap.tech\nauth:\n  token: eyJhbGciOi.eyJzdWIi\n", `auth.token looks damaged (a JWT with 2 of 3 parts)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			path := filepath.Join(home, configDir, configFile)
+			if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load() = %v; a bad value must not stop loading", err)
+			}
+			err = cfg.CheckFile()
+			if err == nil || !strings.Contains(err.Error(), path) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("CheckFile() = %v, want error naming %s and %q", err, path, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheScope(t *testing.T) {
+	t.Setenv("CNAP_API_URL", "")
+	t.Setenv("CNAP_API_TOKEN", "")
+
+	base := Config{APIURL: DefaultAPIURL, Auth: Auth{Token: "cnap_pat_a"}}
+	otherURL := Config{APIURL: "https://api.example.com", Auth: Auth{Token: "cnap_pat_a"}}
+	otherToken := Config{APIURL: DefaultAPIURL, Auth: Auth{Token: "cnap_pat_b"}}
+	if base.CacheScope() != base.CacheScope() {
+		t.Error("CacheScope is not stable")
+	}
+	if base.CacheScope() == otherURL.CacheScope() || base.CacheScope() == otherToken.CacheScope() {
+		t.Error("CacheScope is shared across API URLs or tokens")
+	}
+}
+
+func TestCheckToken(t *testing.T) {
+	cfg := &Config{Auth: Auth{Token: "cnap_pa"}}
+
+	t.Setenv("CNAP_API_TOKEN", "")
+	if err := cfg.CheckToken(); err == nil || !strings.Contains(err.Error(), "auth.token looks damaged") {
+		t.Errorf("CheckToken() = %v, want damaged token error", err)
+	}
+
+	t.Setenv("CNAP_API_TOKEN", "cnap_pat_env")
+	if err := cfg.CheckToken(); err != nil {
+		t.Errorf("CheckToken() with CNAP_API_TOKEN = %v, want nil", err)
+	}
+}
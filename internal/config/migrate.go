@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config schema version this build reads and writes.
+// Bump it together with a new entry in migrations.
+const CurrentVersion = 1
+
+// migrations[i] upgrades the config file's top-level mapping from version
+// i to i+1. They edit the YAML node tree so keys can be renamed or
+// restructured while unknown keys, comments and key order survive.
+var migrations = []func(root *yaml.Node){
+	migrateV0,
+}
+
+// migrate upgrades root, the file's top-level mapping, in place to
+// CurrentVersion. It reports whether anything changed, i.e. whether the
+// file should be written back.
+func migrate(root *yaml.Node) (bool, error) {
+	version := 0
+	if v := mappingValue(root, "version"); v != nil {
+		if err := v.Decode(&version); err != nil || version < 0 {
+			return false, fmt.Errorf("invalid config version %s", v.Value)
+		}
+	}
+
+	if version > CurrentVersion {
+		// Written by a newer CLI. Read what we understand; don't rewrite.
+		slog.Debug("config is newer than this CLI", "version", version, "supported", CurrentVersion)
+		return false, nil
+	}
+	if version == CurrentVersion {
+		return false, nil
+	}
+
+	for v := version; v < CurrentVersion; v++ {
+		slog.Debug("migrating config", "from", v, "to", v+1)
+		migrations[v](root)
+	}
+	setMappingValue(root, "version", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(CurrentVersion)})
+	return true, nil
+}
+
+// migrateV0 upgrades configs written before versioning. Those CLIs fell
+// back to the default dashboard URL and some saved it as auth_url; now the
+// auth URL is derived from the API URL, so drop an auth_url that matches
+// the derived one and let it follow api_url from here on.
+func migrateV0(root *yaml.Node) {
+	apiURL := DefaultAPIURL
+	if v := mappingValue(root, "api_url"); v != nil && v.Kind == yaml.ScalarNode && v.Value != "" {
+		apiURL = v.Value
+	}
+	if v := mappingValue(root, "auth_url"); v != nil && v.Kind == yaml.ScalarNode && v.Value == DeriveAuthURL(apiURL) {
+		deleteMappingKey(root, "auth_url")
+	}
+}
+
+// mappingValue returns the value of key in mapping node n, or nil if n
+// isn't a mapping or has no such key.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets key in mapping node n to value, in place if the key
+// exists (keeping its comments), otherwise as the last key so the lines of
+// the others don't move.
+func setMappingValue(n *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			old := n.Content[i+1]
+			value.HeadComment, value.LineComment, value.FootComment = old.HeadComment, old.LineComment, old.FootComment
+			n.Content[i+1] = value
+			return
+		}
+	}
+	n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// deleteMappingKey removes key and its value from mapping node n.
+func deleteMappingKey(n *yaml.Node, key string) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			n.Content = append(n.Content[:i], n.Content[i+2:]...)
+			return
+		}
+	}
+}
@@ -1,31 +1,66 @@
 // Package debug configures structured logging for the CLI.
 //
-// When --debug or CNAP_DEBUG=1 is set, debug-level logs are emitted to stderr.
-// Otherwise, only warn and above are shown (effectively silent).
+// --log-level (or CNAP_LOG_LEVEL) picks the slog level written to stderr:
+// error, warn, info or debug. --debug and CNAP_DEBUG=1 are shortcuts for
+// debug, which adds HTTP traces. With no level set, logging is silent.
 package debug
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"strings"
 )
 
 // Enabled reports whether debug mode is active.
 var Enabled bool
 
-// Init configures the global slog logger.
-// Call once from the root command's PersistentPreRun.
-func Init(flagEnabled bool) {
-	Enabled = flagEnabled || os.Getenv("CNAP_DEBUG") != ""
+// levels maps --log-level values to slog levels.
+var levels = map[string]slog.Level{
+	"error": slog.LevelError,
+	"warn":  slog.LevelWarn,
+	"info":  slog.LevelInfo,
+	"debug": slog.LevelDebug,
+}
+
+// ParseLevel parses a --log-level value.
+func ParseLevel(s string) (slog.Level, error) {
+	level, ok := levels[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return 0, fmt.Errorf("invalid log level %q: must be error, warn, info or debug", s)
+	}
+	return level, nil
+}
+
+// Init configures the global slog logger. Flags take precedence over the
+// environment, and --debug over --log-level.
+// Call once from the root command's PersistentPreRunE.
+func Init(debugFlag bool, levelFlag string) error {
+	name := levelFlag
+	switch {
+	case debugFlag:
+		name = "debug"
+	case name != "":
+	case os.Getenv("CNAP_DEBUG") != "":
+		name = "debug"
+	default:
+		name = os.Getenv("CNAP_LOG_LEVEL")
+	}
 
 	w := io.Discard
 	level := slog.LevelWarn
-	if Enabled {
+	if name != "" {
+		var err error
+		if level, err = ParseLevel(name); err != nil {
+			return err
+		}
 		w = os.Stderr
-		level = slog.LevelDebug
 	}
+	Enabled = level <= slog.LevelDebug
 
 	slog.SetDefault(slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
 		Level: level,
 	})))
+	return nil
 }
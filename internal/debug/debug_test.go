@@ -0,0 +1,58 @@
+package debug
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestInitLevel(t *testing.T) {
+	t.Cleanup(func() { _ = Init(false, "") })
+
+	tests := []struct {
+		name      string
+		debug     bool
+		flag      string
+		env       map[string]string
+		want      slog.Level
+		wantDebug bool
+		wantErr   bool
+	}{
+		{name: "default", want: slog.LevelWarn},
+		{name: "flag", flag: "info", want: slog.LevelInfo},
+		{name: "flag case", flag: "ERROR", want: slog.LevelError},
+		{name: "debug flag", debug: true, want: slog.LevelDebug, wantDebug: true},
+		{name: "debug flag beats level", debug: true, flag: "error", want: slog.LevelDebug, wantDebug: true},
+		{name: "env", env: map[string]string{"CNAP_LOG_LEVEL": "info"}, want: slog.LevelInfo},
+		{name: "flag beats env", flag: "warn", env: map[string]string{"CNAP_DEBUG": "1"}, want: slog.LevelWarn},
+		{name: "CNAP_DEBUG beats CNAP_LOG_LEVEL", env: map[string]string{"CNAP_DEBUG": "1", "CNAP_LOG_LEVEL": "error"}, want: slog.LevelDebug, wantDebug: true},
+		{name: "invalid", flag: "verbose", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CNAP_DEBUG", "")
+			t.Setenv("CNAP_LOG_LEVEL", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			err := Init(tt.debug, tt.flag)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Init: want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Init: %v", err)
+			}
+			h := slog.Default().Handler()
+			if !h.Enabled(context.Background(), tt.want) || (tt.want > slog.LevelDebug && h.Enabled(context.Background(), tt.want-4)) {
+				t.Errorf("logger level is not %v", tt.want)
+			}
+			if Enabled != tt.wantDebug {
+				t.Errorf("Enabled = %v, want %v", Enabled, tt.wantDebug)
+			}
+		})
+	}
+}
@@ -1,15 +1,113 @@
 package debug
 
 import (
+	"crypto/tls"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Transport wraps an http.RoundTripper and logs request/response details
-// when debug mode is enabled.
+// when debug mode is enabled. Requests are also counted in Stats
+// (the shared command-wide stats if nil).
 type Transport struct {
 	Inner http.RoundTripper
+	Stats *Stats
+}
+
+// Stats accumulates request metrics for one command run.
+type Stats struct {
+	mu       sync.Mutex
+	requests int
+	errors   int
+	latency  time.Duration
+	statuses map[int]int
+	bytes    atomic.Int64 // request + response bodies; updated as bodies are read
+}
+
+// commandStats collects metrics for every Transport without its own Stats.
+// It is reset in place rather than replaced: requests from other
+// goroutines (the background update check) may be recording into it.
+var commandStats = &Stats{}
+
+func (s *Stats) record(status int, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	s.latency += latency
+	if err != nil {
+		s.errors++
+		return
+	}
+	if s.statuses == nil {
+		s.statuses = make(map[int]int)
+	}
+	s.statuses[status]++
+}
+
+// reset zeroes s under its lock.
+func (s *Stats) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests, s.errors, s.latency, s.statuses = 0, 0, 0, nil
+	s.bytes.Store(0)
+}
+
+// count returns the number of requests recorded.
+func (s *Stats) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// Summary renders a one-line rollup, e.g.
+// "4 requests, 12.3 KB, 820ms total (205ms avg), status 200×3 404×1".
+func (s *Stats) Summary() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.requests == 0 {
+		return "0 requests"
+	}
+
+	out := fmt.Sprintf("%d requests, %.1f KB, %s total (%s avg)",
+		s.requests,
+		float64(s.bytes.Load())/1024,
+		s.latency.Round(time.Millisecond),
+		(s.latency / time.Duration(s.requests)).Round(time.Millisecond))
+
+	codes := make([]int, 0, len(s.statuses))
+	for code := range s.statuses {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	parts := make([]string, len(codes))
+	for i, code := range codes {
+		parts[i] = fmt.Sprintf("%d×%d", code, s.statuses[code])
+	}
+	if len(parts) > 0 {
+		out += ", status " + strings.Join(parts, " ")
+	}
+	if s.errors > 0 {
+		out += fmt.Sprintf(", %d failed", s.errors)
+	}
+	return out
+}
+
+// Flush prints the command's request summary to stderr when debug mode is
+// on, then resets the stats. Call once after the command finishes.
+func Flush() {
+	if Enabled && commandStats.count() > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "debug: %s\n", commandStats.Summary())
+	}
+	commandStats.reset()
 }
 
 func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -22,14 +120,22 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		"url", req.URL.String(),
 	)
 
+	stats := t.stats()
+	if req.ContentLength > 0 {
+		stats.bytes.Add(req.ContentLength)
+	}
+
 	start := time.Now()
 	resp, err := t.inner().RoundTrip(req)
 	elapsed := time.Since(start)
 
 	if err != nil {
+		stats.record(0, elapsed, err)
 		slog.Debug("HTTP error", "method", req.Method, "url", req.URL.String(), "error", err, "duration", elapsed)
 		return nil, err
 	}
+	stats.record(resp.StatusCode, elapsed, nil)
+	resp.Body = &countingBody{ReadCloser: resp.Body, n: &stats.bytes}
 
 	slog.Debug("HTTP response",
 		"method", req.Method,
@@ -41,6 +147,25 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return resp, nil
 }
 
+func (t *Transport) stats() *Stats {
+	if t.Stats != nil {
+		return t.Stats
+	}
+	return commandStats
+}
+
+// countingBody adds the bytes read from a response body to n.
+type countingBody struct {
+	io.ReadCloser
+	n *atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n.Add(int64(n))
+	return n, err
+}
+
 func (t *Transport) inner() http.RoundTripper {
 	if t.Inner != nil {
 		return t.Inner
@@ -48,17 +173,47 @@ func (t *Transport) inner() http.RoundTripper {
 	return http.DefaultTransport
 }
 
-// Install replaces http.DefaultClient's transport with a debug-logging wrapper.
-// This covers manual http.DefaultClient.Do() calls (e.g. device flow).
-func Install() {
-	http.DefaultClient.Transport = &Transport{Inner: http.DefaultClient.Transport}
+// proxyTransport replaces http.DefaultTransport in Client once SetProxy
+// has been called.
+var proxyTransport http.RoundTripper
+
+// SetProxy sends every Client request through proxy (--proxy) instead of
+// the HTTPS_PROXY/HTTP_PROXY environment variables.
+func SetProxy(proxy *url.URL) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyURL(proxy)
+	proxyTransport = t
 }
 
 // Client returns an *http.Client with the debug transport.
-// Use this when a library creates its own http.Client (e.g. oapi-codegen).
+// Use it for every request the CLI makes (API client, device flow, update
+// check) rather than http.DefaultClient, which is never modified so that
+// concurrent users of it don't race.
 func Client() *http.Client {
+	inner := http.DefaultTransport
+	if proxyTransport != nil {
+		inner = proxyTransport
+	}
+	if !Enabled {
+		if proxyTransport == nil {
+			return http.DefaultClient
+		}
+		return &http.Client{Transport: inner}
+	}
+	return &http.Client{Transport: &Transport{Inner: inner}}
+}
+
+// TLSClient is like Client, for servers that need their own TLS settings,
+// such as a cluster's Kubernetes API with its CA and client certificate.
+func TLSClient(tlsConfig *tls.Config) *http.Client {
+	base := http.DefaultTransport
+	if proxyTransport != nil {
+		base = proxyTransport
+	}
+	t := base.(*http.Transport).Clone()
+	t.TLSClientConfig = tlsConfig
 	if !Enabled {
-		return http.DefaultClient
+		return &http.Client{Transport: t}
 	}
-	return &http.Client{Transport: &Transport{Inner: http.DefaultTransport}}
+	return &http.Client{Transport: &Transport{Inner: t}}
 }
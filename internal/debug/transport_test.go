@@ -0,0 +1,119 @@
+package debug
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestClientLeavesDefaultClientAlone(t *testing.T) {
+	Enabled = true
+	t.Cleanup(func() { Enabled = false })
+
+	c := Client()
+	if _, ok := c.Transport.(*Transport); !ok {
+		t.Errorf("Client().Transport = %T, want *Transport", c.Transport)
+	}
+	if http.DefaultClient.Transport != nil {
+		t.Errorf("http.DefaultClient.Transport = %T, want nil", http.DefaultClient.Transport)
+	}
+}
+
+// Run with -race: debug requests from several goroutines (e.g. a command
+// alongside the background update check) must not share mutable state.
+func TestClientConcurrentRequests(t *testing.T) {
+	if err := Init(true, ""); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Init(false, "") })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := Client().Get(srv.URL)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			_ = resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStatsSummary(t *testing.T) {
+	Enabled = true
+	t.Cleanup(func() { Enabled = false })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(make([]byte, 2048))
+	}))
+	defer srv.Close()
+
+	stats := &Stats{}
+	c := &http.Client{Transport: &Transport{Stats: stats}}
+	for _, path := range []string{"/a", "/b", "/missing"} {
+		resp, err := c.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	got := stats.Summary()
+	for _, want := range []string{"3 requests", "4.0 KB", "status 200×2 404×1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Summary() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+// Run with -race: Flush runs when the command finishes, possibly while the
+// background update check is still making requests.
+func TestFlushConcurrentRequests(t *testing.T) {
+	if err := Init(true, ""); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Init(false, "") })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				resp, err := Client().Get(srv.URL)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				_ = resp.Body.Close()
+			}
+		}()
+	}
+	for range 10 {
+		Flush()
+	}
+	wg.Wait()
+
+	Flush()
+	if n := commandStats.count(); n != 0 {
+		t.Errorf("after Flush, %d requests recorded, want 0", n)
+	}
+}
@@ -0,0 +1,80 @@
+// Package env detects properties of the environment the CLI runs in.
+package env
+
+import "os"
+
+// Offline is set by the global --offline flag. See IsOffline.
+var Offline bool
+
+// NoInput is set by the global --no-input flag. See IsNoInput.
+var NoInput bool
+
+// ciVars are environment variables set by CI systems. Any non-empty value
+// means we're running in CI.
+var ciVars = []string{
+	"CI",               // generic; set by most providers
+	"BUILD_NUMBER",     // Jenkins, TeamCity
+	"RUN_ID",           // TaskCluster, dsari
+	"GITHUB_ACTIONS",   // GitHub Actions
+	"GITLAB_CI",        // GitLab CI
+	"CIRCLECI",         // CircleCI
+	"JENKINS_URL",      // Jenkins
+	"BUILDKITE",        // Buildkite
+	"TEAMCITY_VERSION", // TeamCity
+	"TF_BUILD",         // Azure Pipelines
+}
+
+// IsCI reports whether the CLI is running in a known CI environment.
+func IsCI() bool {
+	for _, v := range ciVars {
+		if os.Getenv(v) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ciProviders maps provider-specific variables to a short provider name,
+// most specific first. See CIName.
+var ciProviders = []struct{ key, name string }{
+	{"GITHUB_ACTIONS", "github-actions"},
+	{"GITLAB_CI", "gitlab"},
+	{"CIRCLECI", "circleci"},
+	{"BUILDKITE", "buildkite"},
+	{"TEAMCITY_VERSION", "teamcity"},
+	{"TF_BUILD", "azure-pipelines"},
+	{"JENKINS_URL", "jenkins"},
+}
+
+// CIName returns a short name for the detected CI provider, "unknown" for
+// a CI environment we can't identify, or "" outside CI.
+func CIName() string {
+	for _, p := range ciProviders {
+		if os.Getenv(p.key) != "" {
+			return p.name
+		}
+	}
+	if IsCI() {
+		return "unknown"
+	}
+	return ""
+}
+
+// IsOffline reports whether offline mode is on (--offline or CNAP_OFFLINE).
+// Offline mode skips opportunistic network activity that isn't needed for
+// the command itself: the update check and opening a browser during login.
+func IsOffline() bool {
+	return Offline || os.Getenv("CNAP_OFFLINE") != ""
+}
+
+// IsNoInput reports whether prompting is disabled (--no-input or
+// CNAP_NO_INPUT), for automation that runs with a terminal attached.
+func IsNoInput() bool {
+	return NoInput || os.Getenv("CNAP_NO_INPUT") != ""
+}
+
+// IsDumbTerminal reports whether TERM declares a terminal without cursor
+// control or ANSI support.
+func IsDumbTerminal() bool {
+	return os.Getenv("TERM") == "dumb"
+}
@@ -0,0 +1,82 @@
+package env
+
+import "testing"
+
+func TestIsCI(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want bool
+	}{
+		{"none", "", false},
+		{"generic", "CI", true},
+		{"build number", "BUILD_NUMBER", true},
+		{"run id", "RUN_ID", true},
+		{"github actions", "GITHUB_ACTIONS", true},
+		{"gitlab", "GITLAB_CI", true},
+		{"circleci", "CIRCLECI", true},
+		{"jenkins", "JENKINS_URL", true},
+		{"buildkite", "BUILDKITE", true},
+		{"teamcity", "TEAMCITY_VERSION", true},
+		{"azure pipelines", "TF_BUILD", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, v := range ciVars {
+				t.Setenv(v, "")
+			}
+			if tt.key != "" {
+				t.Setenv(tt.key, "true")
+			}
+			if got := IsCI(); got != tt.want {
+				t.Errorf("IsCI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCIName(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", ""},
+		{"CI", "unknown"},
+		{"GITHUB_ACTIONS", "github-actions"},
+		{"GITLAB_CI", "gitlab"},
+		{"TF_BUILD", "azure-pipelines"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			for _, v := range ciVars {
+				t.Setenv(v, "")
+			}
+			if tt.key != "" {
+				t.Setenv(tt.key, "true")
+			}
+			if got := CIName(); got != tt.want {
+				t.Errorf("CIName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsOffline(t *testing.T) {
+	t.Setenv("CNAP_OFFLINE", "")
+	if IsOffline() {
+		t.Error("offline by default")
+	}
+
+	Offline = true
+	if !IsOffline() {
+		t.Error("--offline should enable offline mode")
+	}
+	Offline = false
+
+	t.Setenv("CNAP_OFFLINE", "1")
+	if !IsOffline() {
+		t.Error("CNAP_OFFLINE should enable offline mode")
+	}
+}
@@ -0,0 +1,142 @@
+// Package namecache remembers resource names by ID on disk so commands can
+// show a name without an extra API round-trip. Names are kept per API URL
+// and login (see SetScope). It is best-effort: any read or write failure
+// is treated as a cache miss.
+package namecache
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cnap-tech/cli/internal/config"
+)
+
+// Kind is a resource type whose names are cached.
+type Kind string
+
+const (
+	Workspaces Kind = "workspaces"
+	Clusters   Kind = "clusters"
+	Installs   Kind = "installs"
+)
+
+// TTL is how long a cached name is trusted.
+const TTL = 24 * time.Hour
+
+const cacheFile = "names.json"
+
+// Disabled makes Lookup always miss. Set by the global --no-cache flag.
+// Writes still happen so the cache stays fresh for later runs.
+var Disabled bool
+
+// now is replaced in tests.
+var now = time.Now
+
+// scope selects the entries in use; see SetScope.
+var scope string
+
+// SetScope selects the API URL and login whose names are looked up and
+// stored (config.Config.CacheScope), so a name or ID cached for one CNAP
+// instance or account is never used for another. cmdutil.LoadConfig
+// sets it.
+func SetScope(s string) {
+	scope = s
+}
+
+type entry struct {
+	Name      string `json:"name"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+type cache map[Kind]map[string]entry
+
+// file is the cache file: a cache per scope.
+type file map[string]cache
+
+// Lookup returns the cached name for id, if present and not expired.
+func Lookup(kind Kind, id string) (string, bool) {
+	if Disabled {
+		return "", false
+	}
+	e, ok := load()[scope][kind][id]
+	if !ok || now().Sub(time.Unix(e.UpdatedAt, 0)) > TTL {
+		return "", false
+	}
+	return e.Name, true
+}
+
+// Store records names (keyed by ID) fetched from a list or get call.
+func Store(kind Kind, names map[string]string) {
+	if len(names) == 0 {
+		return
+	}
+	f := load()
+	if f[scope] == nil {
+		f[scope] = cache{}
+	}
+	c := f[scope]
+	if c[kind] == nil {
+		c[kind] = make(map[string]entry)
+	}
+	ts := now().Unix()
+	for id, name := range names {
+		c[kind][id] = entry{Name: name, UpdatedAt: ts}
+	}
+	save(f)
+}
+
+// Forget drops a cached name, e.g. after the resource is deleted.
+func Forget(kind Kind, id string) {
+	f := load()
+	if _, ok := f[scope][kind][id]; !ok {
+		return
+	}
+	delete(f[scope][kind], id)
+	save(f)
+}
+
+func path() (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, cacheFile), nil
+}
+
+func load() file {
+	f := file{}
+	p, err := path()
+	if err != nil {
+		return f
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return f
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		slog.Debug("ignoring unreadable name cache", "path", p, "error", err)
+		return file{}
+	}
+	return f
+}
+
+func save(f file) {
+	p, err := path()
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		slog.Debug("writing name cache failed", "error", err)
+		return
+	}
+	if err := config.WriteFileAtomic(p, data, 0o600); err != nil {
+		slog.Debug("writing name cache failed", "error", err)
+	}
+}
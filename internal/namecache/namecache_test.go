@@ -0,0 +1,49 @@
+package namecache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLookup(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	start := time.Unix(1_700_000_000, 0)
+	now = func() time.Time { return start }
+	t.Cleanup(func() { now = time.Now })
+
+	if _, ok := Lookup(Workspaces, "ws_1"); ok {
+		t.Fatal("empty cache should miss")
+	}
+
+	Store(Workspaces, map[string]string{"ws_1": "Acme"})
+	if name, ok := Lookup(Workspaces, "ws_1"); !ok || name != "Acme" {
+		t.Errorf("Lookup = %q, %v; want Acme, true", name, ok)
+	}
+	if _, ok := Lookup(Clusters, "ws_1"); ok {
+		t.Error("kinds should not share entries")
+	}
+
+	Disabled = true
+	if _, ok := Lookup(Workspaces, "ws_1"); ok {
+		t.Error("Disabled should bypass the cache")
+	}
+	Disabled = false
+
+	now = func() time.Time { return start.Add(TTL + time.Second) }
+	if _, ok := Lookup(Workspaces, "ws_1"); ok {
+		t.Error("expired entry should miss")
+	}
+
+	now = func() time.Time { return start }
+	SetScope("other")
+	if _, ok := Lookup(Workspaces, "ws_1"); ok {
+		t.Error("scopes should not share entries")
+	}
+	SetScope("")
+
+	Forget(Workspaces, "ws_1")
+	if _, ok := Lookup(Workspaces, "ws_1"); ok {
+		t.Error("forgotten entry should miss")
+	}
+}
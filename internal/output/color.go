@@ -0,0 +1,82 @@
+package output
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cnap-tech/cli/internal/env"
+	"golang.org/x/term"
+)
+
+// colorEnabled reports whether styled output should be written to stdout.
+var colorEnabled bool
+
+// InitColor decides whether tables are colorized.
+// Color is used only when stdout is a terminal that supports it (not
+// TERM=dumb), we're not in CI, and neither --no-color nor NO_COLOR
+// (https://no-color.org) is set.
+// Called once by cmdutil.Setup, before any command runs.
+func InitColor(noColor bool) {
+	colorEnabled = !noColor &&
+		os.Getenv("NO_COLOR") == "" &&
+		!env.IsDumbTerminal() &&
+		!env.IsCI() &&
+		term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// ColorEnabled reports whether styled output is enabled.
+func ColorEnabled() bool {
+	return colorEnabled
+}
+
+// Status palette, matching the prompt theme.
+var (
+	statusGreen  = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#2A7A45", Dark: "#3DA060"})
+	statusYellow = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#9A6B00", Dark: "#D9A53F"})
+	statusRed    = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#C04040", Dark: "#D85555"})
+)
+
+// StatusStyle returns the style for a resource status value:
+// green for healthy, yellow for in-progress, red for failed.
+// Unknown statuses are left unstyled.
+func StatusStyle(status string) lipgloss.Style {
+	switch strings.ToLower(status) {
+	case "running", "healthy", "ready", "synced", "succeeded", "active":
+		return statusGreen
+	case "pending", "provisioning", "reconciling", "deleting", "progressing", "degraded":
+		return statusYellow
+	case "failed", "error", "crashloopbackoff", "missing":
+		return statusRed
+	default:
+		return lipgloss.NewStyle()
+	}
+}
+
+// HighlightStyle marks a value that changed since the last refresh of a
+// watch.
+func HighlightStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Bold(true).Reverse(true)
+}
+
+// EventTypeStyle returns the style for a Kubernetes event type:
+// yellow for Warning, unstyled otherwise.
+func EventTypeStyle(eventType string) lipgloss.Style {
+	if strings.EqualFold(eventType, "warning") {
+		return statusYellow
+	}
+	return lipgloss.NewStyle()
+}
+
+// LogLevelStyle returns the style for a log level:
+// red for error/fatal, yellow for warnings, unstyled otherwise.
+func LogLevelStyle(level string) lipgloss.Style {
+	switch strings.ToLower(level) {
+	case "error", "err", "fatal", "panic", "critical":
+		return statusRed
+	case "warn", "warning":
+		return statusYellow
+	default:
+		return lipgloss.NewStyle()
+	}
+}
@@ -0,0 +1,146 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// UnifiedDiff returns a unified diff (as produced by diff -u) turning a
+// into b, labelled with the from and to names. It returns "" if the texts
+// are equal. Meant for small documents such as values files: it runs in
+// O(len(a)·len(b)) time and space.
+func UnifiedDiff(a, b, from, to string) string {
+	if a == b {
+		return ""
+	}
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", from, to)
+	for start := 0; start < len(ops); {
+		// Find the next change and the extent of its hunk: changes closer
+		// than 2*diffContext unchanged lines share a hunk.
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		end, equal := first, 0
+		for i := first; i < len(ops) && equal <= 2*diffContext; i++ {
+			if ops[i].kind == ' ' {
+				equal++
+			} else {
+				equal, end = 0, i+1
+			}
+		}
+		lo := max(first-diffContext, start)
+		hi := min(end+diffContext, len(ops))
+
+		aStart, aLen, bStart, bLen := ops[lo].aLine, 0, ops[lo].bLine, 0
+		for _, op := range ops[lo:hi] {
+			if op.kind != '+' {
+				aLen++
+			}
+			if op.kind != '-' {
+				bLen++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+		for _, op := range ops[lo:hi] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.text)
+			sb.WriteByte('\n')
+		}
+		start = hi
+	}
+	return sb.String()
+}
+
+// ColorDiff styles a unified diff for the terminal: additions green,
+// removals red. It returns diff unchanged when color is disabled.
+func ColorDiff(diff string) string {
+	if !colorEnabled {
+		return diff
+	}
+	lines := strings.SplitAfter(diff, "\n")
+	for i, line := range lines {
+		text := strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "@@"):
+			lines[i] = statusYellow.Render(text) + line[len(text):]
+		case strings.HasPrefix(line, "+"):
+			lines[i] = statusGreen.Render(text) + line[len(text):]
+		case strings.HasPrefix(line, "-"):
+			lines[i] = statusRed.Render(text) + line[len(text):]
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+type diffOp struct {
+	kind         byte // ' ', '-' or '+'
+	text         string
+	aLine, bLine int // 1-based line numbers in a and b at this op
+}
+
+// diffLines computes a shortest edit script from a to b via the longest
+// common subsequence.
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i + 1, j + 1})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i], i + 1, j + 1})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j], i + 1, j + 1})
+			j++
+		}
+	}
+	return ops
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// hunkRange formats a hunk's start,length the way diff -u does: an empty
+// range starts at the line before it.
+func hunkRange(start, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d,%d", start, length)
+	}
+}
@@ -0,0 +1,34 @@
+package output
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+	b := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n"
+	// As printed by diff -u.
+	want := `--- old
++++ new
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -11,3 +11,4 @@
+ k
+ l
+ m
++n
+`
+	if got := UnifiedDiff(a, b, "old", "new"); got != want {
+		t.Errorf("UnifiedDiff =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := UnifiedDiff("", "x\n", "old", "new"); got != "--- old\n+++ new\n@@ -0,0 +1 @@\n+x\n" {
+		t.Errorf("UnifiedDiff from empty = %q", got)
+	}
+	if got := UnifiedDiff(a, a, "old", "new"); got != "" {
+		t.Errorf("UnifiedDiff of equal texts = %q, want empty", got)
+	}
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// JSONPath templates for -o jsonpath=TEMPLATE, evaluated by kubectl's own
+// engine (k8s.io/client-go/util/jsonpath) against the JSON that -o json
+// would print:
+//
+//	{.data[*].id}                      ids of a list, space-separated
+//	{range .data[*]}{.id}{"\t"}{.name}{"\n"}{end}
+//	{.data[?(@.status=="running")].id}
+//	{..id}                             every "id" field, at any depth
+//
+// Missing fields produce no output rather than an error, like kubectl.
+
+const jsonPathPrefix = "jsonpath="
+
+// parseJSONPath compiles a jsonpath template. The result runs once: the
+// engine keeps range state while executing.
+func parseJSONPath(tmpl string) (*jsonpath.JSONPath, error) {
+	jp := jsonpath.New("jsonpath").AllowMissingKeys(true)
+	if err := jp.Parse(tmpl); err != nil {
+		return nil, err
+	}
+	return jp, nil
+}
+
+// executeJSONPath renders a parsed template against v, which is converted
+// to its generic JSON form first (see jsonPathValue).
+func executeJSONPath(jp *jsonpath.JSONPath, v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := jp.Execute(&buf, jsonPathValue(doc)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// jsonPathValue shapes a decoded document like the Kubernetes objects
+// kubectl's engine expects. Whole numbers become int64, so they print in
+// full (not as 1e+06) and compare with integer literals in filters; other
+// numbers float64. Null fields are dropped, as they'd be omitted there:
+// they print nothing, and filters skip them instead of failing to compare.
+func jsonPathValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			if e == nil {
+				delete(v, k)
+				continue
+			}
+			v[k] = jsonPathValue(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = jsonPathValue(e)
+		}
+	case json.Number:
+		if n, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+// writeJSONPath writes v rendered by tmpl to w, newline-terminated.
+func writeJSONPath(w io.Writer, tmpl string, v any) error {
+	jp, err := parseJSONPath(tmpl)
+	if err != nil {
+		return fmt.Errorf("invalid jsonpath template: %w", err)
+	}
+	out, err := executeJSONPath(jp, v)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	_, err = io.WriteString(w, out)
+	return err
+}
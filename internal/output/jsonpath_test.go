@@ -0,0 +1,149 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+// jsonPathDoc is a list response shaped like the API's, with nesting for
+// recursive descent and values of every JSON type for filters.
+var jsonPathDoc = map[string]any{
+	"data": []any{
+		map[string]any{"id": "inst_1", "name": "web", "replicas": 3, "ready": true, "labels": map[string]any{"tier": "frontend"},
+			"pods": []any{map[string]any{"id": "web-0", "restarts": 0}, map[string]any{"id": "web-1", "restarts": 5}}},
+		map[string]any{"id": "inst_2", "name": "db", "replicas": 1, "ready": false, "labels": map[string]any{"tier": "backend"},
+			"pods": []any{map[string]any{"id": "db-0", "restarts": 2}}},
+		map[string]any{"id": "inst_3", "name": nil, "replicas": 0, "ready": true, "pods": []any{}},
+		map[string]any{"id": "inst_4", "name": "cache", "replicas": 12, "size": 9007199254740993, "cpu": 0.25, "created_at": 1735689600},
+	},
+	"pagination": map[string]any{"has_more": true, "next_cursor": "c2"},
+}
+
+func runJSONPath(t *testing.T, tmpl string) string {
+	t.Helper()
+	jp, err := parseJSONPath(tmpl)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got, err := executeJSONPath(jp, jsonPathDoc)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	return got
+}
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{`{.data[*].id}`, "inst_1 inst_2 inst_3 inst_4"},
+		{`{.data[0].name}`, "web"},
+		{`{.data[-1].id}`, "inst_4"},
+		{`{.data[1:3].id}`, "inst_2 inst_3"},
+		{`{.data[0]['name']}`, "web"},
+		{`{..tier}`, "frontend backend"},
+		{`{.data[0].pods[*].id}`, "web-0 web-1"},
+		{`{.pagination.has_more}`, "true"},
+		{`{.pagination}`, `{"has_more":true,"next_cursor":"c2"}`},
+		{`ids: {.data[*].id}`, "ids: inst_1 inst_2 inst_3 inst_4"},
+		// Missing and null fields print nothing.
+		{`{.missing.field}`, ""},
+		{`{.data[2].name}`, ""},
+		{`{.data[*].name}`, "web db cache"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			if got := runJSONPath(t, tt.tmpl); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathFilters(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		// inst_3's null name is skipped rather than failing the filter.
+		{`{.data[?(@.name=="db")].id}`, "inst_2"},
+		{`{.data[?(@.name!="db")].id}`, "inst_1 inst_4"},
+		{`{.data[?(@.ready==true)].id}`, "inst_1 inst_3"},
+		{`{.data[?(@.replicas>1)].id}`, "inst_1 inst_4"},
+		{`{.data[?(@.replicas<=1)].id}`, "inst_2 inst_3"},
+		{`{.data[?(@.cpu<0.5)].id}`, "inst_4"},
+		{`{.data[?(@.labels.tier=="backend")].name}`, "db"},
+		{`{.data[?(@.size)].id}`, "inst_4"},
+		{`{.data[*].pods[?(@.restarts>0)].id}`, "web-1 db-0"},
+		{`{.data[?(@.missing)].id}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			if got := runJSONPath(t, tt.tmpl); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathRange(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{`{range .data[*]}{.id}{"\t"}{.replicas}{"\n"}{end}`, "inst_1\t3\ninst_2\t1\ninst_3\t0\ninst_4\t12\n"},
+		{`{range .data[?(@.ready==true)]}{.id} {end}`, "inst_1 inst_3 "},
+		{`{range .data[*]}{.id}:{range .pods[*]}{.id} {end};{end}`, "inst_1:web-0 web-1 ;inst_2:db-0 ;inst_3:;inst_4:;"},
+		{`{range .missing[*]}x{end}done`, "done"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			if got := runJSONPath(t, tt.tmpl); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Numbers print as -o json has them, however large.
+func TestJSONPathNumbers(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{`{.data[3].size}`, "9007199254740993"},
+		{`{.data[3].created_at}`, "1735689600"},
+		{`{.data[3].cpu}`, "0.25"},
+		{`{.data[0].pods[*].restarts}`, "0 5"},
+		{`{.data[3]}`, `{"cpu":0.25,"created_at":1735689600,"id":"inst_4","name":"cache","replicas":12,"size":9007199254740993}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tmpl, func(t *testing.T) {
+			if got := runJSONPath(t, tt.tmpl); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormatJSONPath(t *testing.T) {
+	f, err := ParseFormat("jsonpath={.data[*].id}")
+	if err != nil || !f.IsJSON() {
+		t.Errorf("ParseFormat = %q, %v", f, err)
+	}
+
+	for _, tmpl := range []string{
+		"jsonpath={.data[*].id",
+		"jsonpath={.data[x]}",
+		"jsonpath={.data[0}",
+		"jsonpath={\"unterminated}",
+	} {
+		if _, err := ParseFormat(tmpl); err == nil || !strings.Contains(err.Error(), "invalid jsonpath template") {
+			t.Errorf("ParseFormat(%q) = %v, want invalid jsonpath template", tmpl, err)
+		}
+	}
+	if _, err := ParseFormat("jsonpath"); err == nil || !strings.Contains(err.Error(), "needs a template") {
+		t.Errorf("ParseFormat(jsonpath) = %v, want needs a template", err)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"text/tabwriter"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 // Format controls output mode.
@@ -17,6 +19,60 @@ const (
 	FormatQuiet Format = "quiet"
 )
 
+// Formats lists the valid output formats.
+var Formats = []Format{FormatTable, FormatJSON, FormatQuiet}
+
+// ParseFormat returns s as a Format, or an error listing the valid ones.
+// Besides the fixed formats, "jsonpath=TEMPLATE" is accepted (see
+// jsonpath.go); its template must compile.
+func ParseFormat(s string) (Format, error) {
+	if tmpl, ok := strings.CutPrefix(s, jsonPathPrefix); ok {
+		if _, err := parseJSONPath(tmpl); err != nil {
+			return "", fmt.Errorf("invalid jsonpath template: %w", err)
+		}
+		return Format(s), nil
+	}
+	if s == "jsonpath" {
+		return "", fmt.Errorf("jsonpath needs a template, e.g. -o jsonpath='{.data[*].id}'")
+	}
+	for _, f := range Formats {
+		if Format(s) == f {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown output format %q (valid: %s, jsonpath=TEMPLATE)", s, strings.Join(names, ", "))
+}
+
+// IsJSON reports whether f prints the JSON data (json or jsonpath) rather
+// than a human-readable view.
+func (f Format) IsJSON() bool {
+	return f == FormatJSON || strings.HasPrefix(string(f), jsonPathPrefix)
+}
+
+// PrintData writes v to stdout in format f: indented JSON, or the output
+// of f's jsonpath template (newline-terminated).
+func PrintData(f Format, v any) error {
+	tmpl, ok := strings.CutPrefix(string(f), jsonPathPrefix)
+	if !ok {
+		return PrintJSON(v)
+	}
+	return writeJSONPath(os.Stdout, tmpl, v)
+}
+
+// EncodeData is PrintData for streams of values: JSON is written compactly,
+// one value per line.
+func EncodeData(w io.Writer, f Format, v any) error {
+	tmpl, ok := strings.CutPrefix(string(f), jsonPathPrefix)
+	if !ok {
+		return json.NewEncoder(w).Encode(v)
+	}
+	return writeJSONPath(w, tmpl, v)
+}
+
 // PrintJSON writes v as indented JSON to stdout.
 func PrintJSON(v any) error {
 	enc := json.NewEncoder(os.Stdout)
@@ -24,30 +80,88 @@ func PrintJSON(v any) error {
 	return enc.Encode(v)
 }
 
-// Table creates a tab writer for aligned columns.
-func Table(w io.Writer) *tabwriter.Writer {
-	return tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+// PrintTable prints rows under a header in aligned columns. Every row
+// should have one cell per header column; a short row is padded with "-"
+// and a long one truncated, so a slip can't shift the columns after it.
+func PrintTable(header []string, rows [][]string) {
+	writeTable(os.Stdout, header, rows, nil)
+}
+
+// StyleFunc returns the style for a cell value.
+type StyleFunc func(value string) lipgloss.Style
+
+// PrintStyledTable prints rows like PrintTable, styling cells in the columns
+// named in styles (keyed by header). Falls back to PrintTable when color is
+// disabled, so piped output stays plain text.
+func PrintStyledTable(header []string, rows [][]string, styles map[string]StyleFunc) {
+	if !colorEnabled || len(styles) == 0 {
+		PrintTable(header, rows)
+		return
+	}
+
+	colStyles := make([]StyleFunc, len(header))
+	for i, h := range header {
+		colStyles[i] = styles[h]
+	}
+	writeTable(os.Stdout, header, rows, func(i int, cell string) string {
+		if colStyles[i] == nil {
+			return cell
+		}
+		return colStyles[i](cell).Render(cell)
+	})
 }
 
-// PrintTable prints rows with a header using tabwriter.
-func PrintTable(header []string, rows [][]string) {
-	tw := Table(os.Stdout)
+// writeTable writes header and rows in columns separated by two spaces,
+// passing data cells through style if it's non-nil. Columns are padded by
+// display width rather than bytes, so wide characters
+// such as CJK and emoji, and ANSI styling, don't break the alignment.
+func writeTable(w io.Writer, header []string, rows [][]string, style func(col int, cell string) string) {
+	fitted := make([][]string, len(rows))
+	for i, row := range rows {
+		fitted[i] = fitRow(row, len(header))
+	}
+	widths := make([]int, len(header))
 	for i, h := range header {
-		if i > 0 {
-			_, _ = fmt.Fprint(tw, "\t")
+		widths[i] = lipgloss.Width(h)
+	}
+	for _, row := range fitted {
+		for i, cell := range row {
+			widths[i] = max(widths[i], lipgloss.Width(cell))
 		}
-		_, _ = fmt.Fprint(tw, h)
 	}
-	_, _ = fmt.Fprintln(tw)
 
-	for _, row := range rows {
-		for i, col := range row {
-			if i > 0 {
-				_, _ = fmt.Fprint(tw, "\t")
+	writeRow := func(cells []string, styled bool) {
+		var b strings.Builder
+		for i, cell := range cells {
+			text := cell
+			if styled && style != nil {
+				text = style(i, cell)
+			}
+			b.WriteString(text)
+			if i < len(cells)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-lipgloss.Width(cell)+2))
 			}
-			_, _ = fmt.Fprint(tw, col)
 		}
-		_, _ = fmt.Fprintln(tw)
+		_, _ = fmt.Fprintln(w, b.String())
+	}
+
+	writeRow(header, false)
+	for _, row := range fitted {
+		writeRow(row, true)
+	}
+}
+
+// fitRow returns row with exactly n cells, padding with "-" or truncating.
+func fitRow(row []string, n int) []string {
+	if len(row) == n {
+		return row
+	}
+	fitted := make([]string, n)
+	for i := range fitted {
+		fitted[i] = "-"
+		if i < len(row) {
+			fitted[i] = row[i]
+		}
 	}
-	_ = tw.Flush()
+	return fitted
 }
@@ -0,0 +1,42 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteTableRaggedRows(t *testing.T) {
+	var b strings.Builder
+	writeTable(&b, []string{"ID", "NAME", "STATUS"}, [][]string{
+		{"inst_1", "web", "running"},
+		{"inst_2", "db"},
+		{"inst_3", "cache", "failed", "extra"},
+	}, nil)
+
+	want := "" +
+		"ID      NAME   STATUS\n" +
+		"inst_1  web    running\n" +
+		"inst_2  db     -\n" +
+		"inst_3  cache  failed\n"
+	if got := b.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteTableWideCharacters(t *testing.T) {
+	var b strings.Builder
+	writeTable(&b, []string{"ICON", "NAME", "ID"}, [][]string{
+		{"🇩🇪", "Frankfurt", "reg_1"},
+		{"🗼", "東京", "reg_2"},
+		{"-", "São Paulo", "reg_3"},
+	}, nil)
+
+	want := "" +
+		"ICON  NAME       ID\n" +
+		"🇩🇪    Frankfurt  reg_1\n" +
+		"🗼    東京       reg_2\n" +
+		"-     São Paulo  reg_3\n"
+	if got := b.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -1,74 +1,171 @@
 // Package prompt provides interactive terminal prompts with TTY detection.
 //
 // When stdin is a TTY (interactive terminal), prompts are shown using huh.
-// When stdin is not a TTY (CI, piped input), prompts return an error
-// so the caller can require explicit flags/arguments instead.
+// When stdin is not a TTY (piped input), or the terminal can't render the
+// prompt UI (TERM=dumb, CI), prompts return an error so the caller can
+// require explicit flags/arguments instead.
 package prompt
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/charmbracelet/huh"
+	"github.com/cnap-tech/cli/internal/env"
 	"golang.org/x/term"
 )
 
-// IsInteractive reports whether stdin is a terminal.
+// IsInteractive reports whether prompts can be shown: stdin is a terminal,
+// TERM isn't "dumb", and we're not in CI. Set CNAP_FORCE_INTERACTIVE=1 to
+// skip the TERM and CI checks (stdin must still be a terminal). --no-input
+// (CNAP_NO_INPUT=1) turns prompts off regardless, and takes precedence.
 func IsInteractive() bool {
-	return term.IsTerminal(int(os.Stdin.Fd()))
+	return interactive(term.IsTerminal(int(os.Stdin.Fd())))
+}
+
+func interactive(stdinTTY bool) bool {
+	if !stdinTTY || env.IsNoInput() {
+		return false
+	}
+	if os.Getenv("CNAP_FORCE_INTERACTIVE") != "" {
+		return true
+	}
+	return !env.IsDumbTerminal() && !env.IsCI()
 }
 
 // ErrNonInteractive is returned when a prompt is attempted without a TTY.
 var ErrNonInteractive = fmt.Errorf("required argument missing (not running interactively)")
 
+// ErrAborted is returned when the user cancels a prompt with Ctrl-C.
+// main exits with status 130 without printing it.
+var ErrAborted = errors.New("aborted")
+
+// ErrInterrupted is returned when a prompt is closed because its context
+// was cancelled, e.g. by SIGINT from outside the terminal. main handles it
+// like ErrAborted.
+var ErrInterrupted = errors.New("interrupted")
+
+// runCtx is the context prompts run in; see SetContext.
+var runCtx = context.Background()
+
+// SetContext sets the context prompts run in, so cancelling it (e.g. on
+// SIGINT) closes an open prompt and restores the terminal.
+func SetContext(ctx context.Context) {
+	runCtx = ctx
+}
+
+// run shows a single-field form, mapping Ctrl-C to ErrAborted and
+// cancellation of the run context to ErrInterrupted.
+func run(field huh.Field) error {
+	err := huh.NewForm(huh.NewGroup(field)).
+		WithShowHelp(false).
+		WithTheme(ThemeCNAP()).
+		RunWithContext(runCtx)
+	if ctxErr := runCtx.Err(); ctxErr != nil {
+		if errors.Is(ctxErr, context.Canceled) {
+			return ErrInterrupted
+		}
+		return ctxErr
+	}
+	if errors.Is(err, huh.ErrUserAborted) {
+		return ErrAborted
+	}
+	return err
+}
+
 // SelectOption is a single item in a select prompt.
 type SelectOption struct {
 	Label string
 	Value string
 }
 
+// SelectOpt configures a Select prompt.
+type SelectOpt func(*selectConfig)
+
+type selectConfig struct {
+	cancel bool
+}
+
+// WithCancel adds a trailing "(cancel)" option that returns ErrAborted,
+// like Ctrl-C or Esc, for users who don't know those keys. Meant for
+// pickers that start a command, where backing out is an expected choice.
+func WithCancel() SelectOpt {
+	return func(c *selectConfig) { c.cancel = true }
+}
+
+// cancelValue is the value of the option added by WithCancel. Option
+// values are IDs and names, so it can't clash with one.
+const cancelValue = "\x00cancel"
+
 // Select shows an interactive select list and returns the chosen value.
-// Returns ErrNonInteractive if stdin is not a TTY.
-func Select(title string, options []SelectOption) (string, error) {
+// Returns ErrNonInteractive if stdin is not a TTY, and ErrAborted if the
+// user pressed Ctrl-C or chose the WithCancel option.
+func Select(title string, options []SelectOption, opts ...SelectOpt) (string, error) {
+	return SelectWithDefault(title, options, "", opts...)
+}
+
+// SelectWithDefault is Select with the cursor starting on the option whose
+// value is defaultValue (e.g. the active workspace), or on the first one
+// if none matches.
+func SelectWithDefault(title string, options []SelectOption, defaultValue string, opts ...SelectOpt) (string, error) {
 	if !IsInteractive() {
 		return "", ErrNonInteractive
 	}
 
-	huhOpts := make([]huh.Option[string], len(options))
-	for i, o := range options {
-		huhOpts[i] = huh.NewOption(o.Label, o.Value)
+	selected := defaultValue
+	if err := run(newSelect(title, options, &selected, opts)); err != nil {
+		return "", err
 	}
+	if selected == cancelValue {
+		return "", ErrAborted
+	}
+
+	return selected, nil
+}
 
-	var selected string
-	err := huh.NewSelect[string]().
+// newSelect builds the select field for Select, bound to selected. huh
+// starts the cursor on the option matching selected's initial value.
+func newSelect(title string, options []SelectOption, selected *string, opts []SelectOpt) *huh.Select[string] {
+	return huh.NewSelect[string]().
 		Title(title).
-		Options(huhOpts...).
-		Value(&selected).
-		WithTheme(ThemeCNAP()).
-		Run()
-	if err != nil {
-		return "", err
+		Options(huhOptions(options, opts)...).
+		Value(selected)
+}
+
+// huhOptions converts options for huh, adding the cancel option if asked.
+func huhOptions(options []SelectOption, opts []SelectOpt) []huh.Option[string] {
+	var cfg selectConfig
+	for _, o := range opts {
+		o(&cfg)
 	}
 
-	return selected, nil
+	huhOpts := make([]huh.Option[string], 0, len(options)+1)
+	for _, o := range options {
+		huhOpts = append(huhOpts, huh.NewOption(o.Label, o.Value))
+	}
+	if cfg.cancel {
+		huhOpts = append(huhOpts, huh.NewOption("(cancel)", cancelValue))
+	}
+	return huhOpts
 }
 
 // Confirm shows a yes/no confirmation prompt with the given message.
 // Returns true if the user confirmed, false if they declined.
-// Returns ErrNonInteractive if stdin is not a TTY.
+// Returns ErrNonInteractive if stdin is not a TTY, and ErrAborted if the
+// user pressed Ctrl-C.
 func Confirm(message string) (bool, error) {
 	if !IsInteractive() {
 		return false, ErrNonInteractive
 	}
 
 	var confirmed bool
-	err := huh.NewConfirm().
+	err := run(huh.NewConfirm().
 		Title(message).
 		Affirmative("Yes").
 		Negative("No").
-		Value(&confirmed).
-		WithTheme(ThemeCNAP()).
-		Run()
+		Value(&confirmed))
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,76 @@
+package prompt
+
+import "testing"
+
+func TestInteractive(t *testing.T) {
+	tests := []struct {
+		name     string
+		stdinTTY bool
+		env      map[string]string
+		want     bool
+	}{
+		{"terminal", true, nil, true},
+		{"piped", false, nil, false},
+		{"dumb terminal", true, map[string]string{"TERM": "dumb"}, false},
+		{"ci", true, map[string]string{"CI": "true"}, false},
+		{"forced in ci", true, map[string]string{"CI": "true", "CNAP_FORCE_INTERACTIVE": "1"}, true},
+		{"forced without tty", false, map[string]string{"CNAP_FORCE_INTERACTIVE": "1"}, false},
+		{"no input", true, map[string]string{"CNAP_NO_INPUT": "1"}, false},
+		{"no input beats forced", true, map[string]string{"CNAP_NO_INPUT": "1", "CNAP_FORCE_INTERACTIVE": "1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, k := range []string{
+				"TERM", "CNAP_FORCE_INTERACTIVE", "CNAP_NO_INPUT",
+				"CI", "BUILD_NUMBER", "RUN_ID", "GITHUB_ACTIONS", "GITLAB_CI",
+				"CIRCLECI", "JENKINS_URL", "BUILDKITE", "TEAMCITY_VERSION", "TF_BUILD",
+			} {
+				t.Setenv(k, tt.env[k])
+			}
+			if got := interactive(tt.stdinTTY); got != tt.want {
+				t.Errorf("interactive(%v) = %v, want %v", tt.stdinTTY, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHuhOptionsCancel(t *testing.T) {
+	options := []SelectOption{{Label: "web (inst_1)", Value: "inst_1"}}
+
+	if got := huhOptions(options, nil); len(got) != 1 {
+		t.Errorf("without WithCancel: %d options, want 1", len(got))
+	}
+
+	got := huhOptions(options, []SelectOpt{WithCancel()})
+	if len(got) != 2 {
+		t.Fatalf("with WithCancel: %d options, want 2", len(got))
+	}
+	if last := got[1]; last.Key != "(cancel)" || last.Value != cancelValue {
+		t.Errorf("last option = %q/%q, want the cancel option", last.Key, last.Value)
+	}
+}
+
+func TestSelectDefault(t *testing.T) {
+	options := []SelectOption{
+		{Label: "Acme", Value: "ws_1"},
+		{Label: "Globex (active)", Value: "ws_2"},
+		{Label: "Initech", Value: "ws_3"},
+	}
+
+	tests := []struct {
+		defaultValue string
+		want         string
+	}{
+		{"ws_2", "ws_2"},
+		{"", "ws_1"},
+		{"ws_gone", "ws_1"},
+	}
+	for _, tt := range tests {
+		selected := tt.defaultValue
+		field := newSelect("Select a workspace", options, &selected, nil)
+		if got, _ := field.Hovered(); got != tt.want {
+			t.Errorf("default %q: cursor on %q, want %q", tt.defaultValue, got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package update
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -17,6 +18,8 @@ import (
 	"time"
 
 	"github.com/cnap-tech/cli/internal/config"
+	"github.com/cnap-tech/cli/internal/debug"
+	"github.com/cnap-tech/cli/internal/env"
 	"golang.org/x/term"
 	"gopkg.in/yaml.v3"
 )
@@ -24,29 +27,47 @@ import (
 const (
 	repo      = "cnap-tech/cli"
 	stateFile = "state.yaml"
+
+	// ChannelPrerelease opts into prerelease notices via CNAP_UPDATE_CHANNEL.
+	ChannelPrerelease = "prerelease"
 )
 
+// githubAPIURL is the GitHub API base URL. Replaced in tests.
+var githubAPIURL = "https://api.github.com"
+
 // ReleaseInfo stores information about a GitHub release.
 type ReleaseInfo struct {
 	Version     string    `json:"tag_name"`
 	URL         string    `json:"html_url"`
 	PublishedAt time.Time `json:"published_at"`
+	Prerelease  bool      `json:"prerelease"`
+	Draft       bool      `json:"draft" yaml:"-"`
 }
 
 type stateEntry struct {
 	CheckedForUpdateAt time.Time   `yaml:"checked_for_update_at"`
+	Channel            string      `yaml:"channel,omitempty"`
 	LatestRelease      ReleaseInfo `yaml:"latest_release"`
 }
 
+// Channel returns the update channel: "prerelease" if CNAP_UPDATE_CHANNEL
+// says so, otherwise "" for stable releases only.
+func Channel() string {
+	if strings.EqualFold(os.Getenv("CNAP_UPDATE_CHANNEL"), ChannelPrerelease) {
+		return ChannelPrerelease
+	}
+	return ""
+}
+
 // ShouldCheckForUpdate returns true if the environment is suitable for update checks.
 func ShouldCheckForUpdate() bool {
-	if os.Getenv("CNAP_NO_UPDATE_NOTIFIER") != "" {
+	if os.Getenv("CNAP_NO_UPDATE_NOTIFIER") != "" || env.IsOffline() {
 		return false
 	}
 	if os.Getenv("CODESPACES") != "" {
 		return false
 	}
-	if isCI() {
+	if env.IsCI() {
 		return false
 	}
 	return term.IsTerminal(int(os.Stderr.Fd()))
@@ -61,20 +82,22 @@ func CheckForUpdate(ctx context.Context, currentVersion string) (*ReleaseInfo, e
 		return nil, err
 	}
 
-	// Return early if checked recently
+	channel := Channel()
+
+	// Return early if checked recently on the same channel
 	state, _ := getState(stateFilePath)
-	if state != nil && time.Since(state.CheckedForUpdateAt).Hours() < 24 {
+	if state != nil && state.Channel == channel && time.Since(state.CheckedForUpdateAt).Hours() < 24 {
 		return nil, nil
 	}
 
 	// Fetch latest release from GitHub
-	release, err := fetchLatestRelease(ctx)
+	release, err := fetchRelease(ctx, channel)
 	if err != nil {
 		return nil, err
 	}
 
 	// Cache the result
-	_ = setState(stateFilePath, time.Now(), *release)
+	_ = setState(stateFilePath, time.Now(), channel, *release)
 
 	if versionGreaterThan(release.Version, currentVersion) {
 		return release, nil
@@ -83,6 +106,36 @@ func CheckForUpdate(ctx context.Context, currentVersion string) (*ReleaseInfo, e
 	return nil, nil
 }
 
+// LatestRelease returns the newest release on the update channel, reusing
+// the one CheckForUpdate cached if it was checked within 24h. Unlike
+// CheckForUpdate it always returns the release, for commands that report
+// the version rather than notify about it.
+func LatestRelease(ctx context.Context) (*ReleaseInfo, error) {
+	stateFilePath, err := statePath()
+	if err != nil {
+		return nil, err
+	}
+
+	channel := Channel()
+	state, _ := getState(stateFilePath)
+	if state != nil && state.Channel == channel && time.Since(state.CheckedForUpdateAt).Hours() < 24 {
+		return &state.LatestRelease, nil
+	}
+
+	release, err := fetchRelease(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	_ = setState(stateFilePath, time.Now(), channel, *release)
+	return release, nil
+}
+
+// IsNewer reports whether version v is newer than current. Versions that
+// aren't semver, like "dev", are never newer or older.
+func IsNewer(v, current string) bool {
+	return versionGreaterThan(v, current)
+}
+
 // IsUnderHomebrew returns true if the CLI binary is managed by Homebrew.
 func IsUnderHomebrew() bool {
 	exe, err := os.Executable()
@@ -117,37 +170,50 @@ func statePath() (string, error) {
 	return filepath.Join(dir, stateFile), nil
 }
 
+// getState reads the cached check result. A missing or corrupt file is
+// treated as no state, so the next check simply runs and rewrites it.
 func getState(path string) (*stateEntry, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	var s stateEntry
 	if err := yaml.Unmarshal(data, &s); err != nil {
-		return nil, err
+		return nil, nil //nolint:nilerr // corrupt cache → check again
 	}
 	return &s, nil
 }
 
-func setState(path string, t time.Time, r ReleaseInfo) error {
-	data, err := yaml.Marshal(stateEntry{CheckedForUpdateAt: t, LatestRelease: r})
+func setState(path string, t time.Time, channel string, r ReleaseInfo) error {
+	data, err := yaml.Marshal(stateEntry{CheckedForUpdateAt: t, Channel: channel, LatestRelease: r})
 	if err != nil {
 		return err
 	}
 	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0o600)
+	return config.WriteFileAtomic(path, data, 0o600)
+}
+
+// fetchRelease fetches the newest release on channel from GitHub.
+func fetchRelease(ctx context.Context, channel string) (*ReleaseInfo, error) {
+	if channel == ChannelPrerelease {
+		return fetchNewestRelease(ctx)
+	}
+	return fetchLatestRelease(ctx)
 }
 
 func fetchLatestRelease(ctx context.Context) (*ReleaseInfo, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", repo)
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", githubAPIURL, repo)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := debug.Client().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -167,45 +233,139 @@ func fetchLatestRelease(ctx context.Context) (*ReleaseInfo, error) {
 	return &release, nil
 }
 
-// versionGreaterThan returns true if v is a newer version than w.
-// Versions are expected as semver strings with optional "v" prefix (e.g. "v0.5.1" or "0.5.1").
+// fetchNewestRelease returns the highest version among recent releases,
+// including prereleases, which /releases/latest leaves out.
+func fetchNewestRelease(ctx context.Context) (*ReleaseInfo, error) {
+	url := fmt.Sprintf("%s/repos/%s/releases?per_page=30", githubAPIURL, repo)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := debug.Client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("unexpected HTTP %d", resp.StatusCode)
+	}
+
+	var releases []ReleaseInfo
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, err
+	}
+	newest := newestRelease(releases)
+	if newest == nil {
+		return nil, fmt.Errorf("no releases found")
+	}
+	return newest, nil
+}
+
+// newestRelease picks the highest semver version, skipping drafts.
+func newestRelease(releases []ReleaseInfo) *ReleaseInfo {
+	var newest *ReleaseInfo
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft {
+			continue
+		}
+		if _, ok := parseVersion(r.Version); !ok {
+			continue
+		}
+		if newest == nil || versionGreaterThan(r.Version, newest.Version) {
+			newest = r
+		}
+	}
+	return newest
+}
+
+// versionGreaterThan reports whether v has higher semver precedence than w
+// (https://semver.org/#spec-item-11). A leading "v" is optional; build
+// metadata is ignored. Returns false if either version is invalid (e.g. "dev").
 func versionGreaterThan(v, w string) bool {
-	vParts := parseVersion(v)
-	wParts := parseVersion(w)
-	if vParts == nil || wParts == nil {
+	vv, ok1 := parseVersion(v)
+	wv, ok2 := parseVersion(w)
+	if !ok1 || !ok2 {
 		return false
 	}
-	for i := 0; i < 3; i++ {
-		if vParts[i] > wParts[i] {
-			return true
-		}
-		if vParts[i] < wParts[i] {
-			return false
+	for i := range 3 {
+		if vv.core[i] != wv.core[i] {
+			return vv.core[i] > wv.core[i]
 		}
 	}
-	return false
+	return comparePrerelease(vv.pre, wv.pre) > 0
+}
+
+type semver struct {
+	core [3]int
+	pre  []string // dot-separated pre-release identifiers; empty for a release
 }
 
-func parseVersion(s string) []int {
+func parseVersion(s string) (semver, bool) {
 	s = strings.TrimPrefix(s, "v")
-	parts := strings.SplitN(s, ".", 3)
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+
+	var v semver
+	core, pre, hasPre := strings.Cut(s, "-")
+	if hasPre {
+		v.pre = strings.Split(pre, ".")
+		for _, id := range v.pre {
+			if id == "" {
+				return semver{}, false
+			}
+		}
+	}
+
+	parts := strings.Split(core, ".")
 	if len(parts) != 3 {
-		return nil
+		return semver{}, false
 	}
-	nums := make([]int, 3)
 	for i, p := range parts {
 		n, err := strconv.Atoi(p)
-		if err != nil {
-			return nil
+		if err != nil || n < 0 {
+			return semver{}, false
 		}
-		nums[i] = n
+		v.core[i] = n
 	}
-	return nums
+	return v, true
 }
 
-// isCI returns true if running in a known CI environment.
-func isCI() bool {
-	return os.Getenv("CI") != "" ||
-		os.Getenv("BUILD_NUMBER") != "" ||
-		os.Getenv("RUN_ID") != ""
+// comparePrerelease orders pre-release identifiers per semver: a release
+// (no identifiers) ranks above any pre-release, numeric identifiers compare
+// numerically and rank below alphanumeric ones, and a longer list wins a tie.
+func comparePrerelease(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		an, aErr := strconv.Atoi(a[i])
+		bn, bErr := strconv.Atoi(b[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return cmp.Compare(an, bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return cmp.Compare(len(a), len(b))
 }
@@ -1,6 +1,14 @@
 package update
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
 
 func TestVersionGreaterThan(t *testing.T) {
 	tests := []struct {
@@ -18,6 +26,23 @@ func TestVersionGreaterThan(t *testing.T) {
 		{"dev", "v0.5.0", false},
 		{"invalid", "v0.5.0", false},
 		{"v0.5.0", "invalid", false},
+
+		// Pre-release precedence (semver.org example ordering)
+		{"1.0.0", "1.0.0-alpha", true},
+		{"1.0.0-alpha", "1.0.0", false},
+		{"1.0.0-alpha.1", "1.0.0-alpha", true},
+		{"1.0.0-alpha.beta", "1.0.0-alpha.1", true},
+		{"1.0.0-beta", "1.0.0-alpha.beta", true},
+		{"1.0.0-beta.11", "1.0.0-beta.2", true},
+		{"1.0.0-rc.1", "1.0.0-beta.11", true},
+		{"v0.6.0-rc.1", "v0.5.0", true},
+		{"v0.6.0-rc.1", "v0.6.0-rc.1", false},
+
+		// Build metadata is ignored
+		{"v1.2.3+meta", "v1.2.2", true},
+		{"v1.2.3+meta", "v1.2.3", false},
+		{"v1.2.3", "v1.2.3+meta", false},
+		{"v1.2.3-", "v1.2.2", false},
 	}
 
 	for _, tt := range tests {
@@ -29,3 +54,91 @@ func TestVersionGreaterThan(t *testing.T) {
 		})
 	}
 }
+
+func TestNewestRelease(t *testing.T) {
+	releases := []ReleaseInfo{
+		{Version: "v0.5.0"},
+		{Version: "v0.7.0-rc.1", Draft: true},
+		{Version: "v0.6.0-rc.2", Prerelease: true},
+		{Version: "v0.6.0-rc.10", Prerelease: true},
+		{Version: "nightly"},
+		{Version: "v0.5.1"},
+	}
+
+	got := newestRelease(releases)
+	if got == nil || got.Version != "v0.6.0-rc.10" {
+		t.Errorf("newestRelease = %+v, want v0.6.0-rc.10", got)
+	}
+
+	if got := newestRelease(nil); got != nil {
+		t.Errorf("newestRelease(nil) = %+v, want nil", got)
+	}
+}
+
+func TestCheckForUpdateCorruptState(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CNAP_UPDATE_CHANNEL", "")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(ReleaseInfo{Version: "v9.9.9", URL: "https://example.com/release"})
+	}))
+	defer srv.Close()
+	githubAPIURL = srv.URL
+	t.Cleanup(func() { githubAPIURL = "https://api.github.com" })
+
+	path, err := statePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	// A write interrupted mid-way leaves truncated YAML behind.
+	if err := os.WriteFile(path, []byte("checked_for_update_at: 2025-01-\n  latest_release: {tag"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rel, err := CheckForUpdate(context.Background(), "v1.0.0")
+	if err != nil {
+		t.Fatalf("CheckForUpdate: %v", err)
+	}
+	if rel == nil || rel.Version != "v9.9.9" {
+		t.Fatalf("release = %+v, want v9.9.9", rel)
+	}
+
+	state, err := getState(path)
+	if err != nil || state == nil {
+		t.Fatalf("state not rewritten: %+v, %v", state, err)
+	}
+	if state.LatestRelease.Version != "v9.9.9" {
+		t.Errorf("cached version = %q, want v9.9.9", state.LatestRelease.Version)
+	}
+}
+
+func TestLatestReleaseUsesCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CNAP_UPDATE_CHANNEL", "")
+
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		_ = json.NewEncoder(w).Encode(ReleaseInfo{Version: "v9.9.9"})
+	}))
+	defer srv.Close()
+	githubAPIURL = srv.URL
+	t.Cleanup(func() { githubAPIURL = "https://api.github.com" })
+
+	for range 2 {
+		rel, err := LatestRelease(context.Background())
+		if err != nil {
+			t.Fatalf("LatestRelease: %v", err)
+		}
+		if rel.Version != "v9.9.9" {
+			t.Errorf("version = %q, want v9.9.9", rel.Version)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("fetches = %d, want 1 (second call cached)", fetches)
+	}
+}
@@ -4,21 +4,83 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
+
+	"github.com/cnap-tech/cli/internal/env"
+	"golang.org/x/term"
 )
 
-var version = "dev"
+var (
+	version      = "dev"
+	hideHostname bool
+)
 
 // SetVersion sets the CLI version used in the User-Agent string.
 // Called from root command with build-time injected version.
 func SetVersion(v string) { version = v }
 
+// SetHideHostname replaces the hostname with "hidden" when set (the
+// hide_hostname config key). CNAP_UA_NO_HOSTNAME=1 does the same.
+func SetHideHostname(hide bool) { hideHostname = hide }
+
 // String returns a structured User-Agent string for HTTP requests.
 // Format: CNAP CLI/{version} ({os}; {arch}; {hostname})
 // Example: CNAP CLI/1.2.0 (darwin; arm64; Robins-MacBook-Pro.local)
+//
+// In CI the hostname is replaced by the CI provider and whether stdin is a
+// terminal, since CI hostnames are ephemeral and can be sensitive:
+// CNAP CLI/1.2.0 (linux; amd64; ci=github-actions; tty=false)
+//
+// CNAP_UA_EXTRA, if set, is appended as additional segments (see extra).
 func String() string {
+	segments := []string{runtime.GOOS, runtime.GOARCH}
+	if ci := env.CIName(); ci != "" {
+		segments = append(segments,
+			"ci="+ci,
+			fmt.Sprintf("tty=%t", term.IsTerminal(int(os.Stdin.Fd()))))
+	} else {
+		segments = append(segments, hostname())
+	}
+	if e := extra(os.Getenv("CNAP_UA_EXTRA")); e != "" {
+		segments = append(segments, e)
+	}
+	return fmt.Sprintf("CNAP CLI/%s (%s)", version, strings.Join(segments, "; "))
+}
+
+// maxExtraLen caps CNAP_UA_EXTRA, so a stray variable can't bloat every
+// request header.
+const maxExtraLen = 128
+
+// extra cleans CNAP_UA_EXTRA for the User-Agent comment: only HTTP token
+// characters are kept, plus "=", "/", ";" and spaces to write segments
+// like "team=platform; env=prod". Control characters (which could split
+// the header), parentheses and non-ASCII are dropped, runs of spaces are
+// collapsed, and the result is cut to maxExtraLen.
+func extra(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t':
+			return ' '
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			strings.ContainsRune("!#$%&'*+-.^_`|~=/; ", r):
+			return r
+		}
+		return -1
+	}, s)
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > maxExtraLen {
+		s = strings.TrimSpace(s[:maxExtraLen])
+	}
+	return s
+}
+
+func hostname() string {
+	if hideHostname || os.Getenv("CNAP_UA_NO_HOSTNAME") != "" {
+		return "hidden"
+	}
 	host, _ := os.Hostname()
 	if host == "" {
-		host = "unknown"
+		return "unknown"
 	}
-	return fmt.Sprintf("CNAP CLI/%s (%s; %s; %s)", version, runtime.GOOS, runtime.GOARCH, host)
+	return host
 }
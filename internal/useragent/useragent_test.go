@@ -0,0 +1,94 @@
+package useragent
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// clearCI unsets the variables env.IsCI looks at so the test sees a
+// consistent environment whether or not it runs in CI itself.
+func clearCI(t *testing.T) {
+	t.Helper()
+	for _, v := range []string{
+		"CI", "BUILD_NUMBER", "RUN_ID", "GITHUB_ACTIONS", "GITLAB_CI",
+		"CIRCLECI", "JENKINS_URL", "BUILDKITE", "TEAMCITY_VERSION", "TF_BUILD",
+	} {
+		t.Setenv(v, "")
+	}
+	t.Setenv("CNAP_UA_EXTRA", "")
+	t.Setenv("CNAP_UA_NO_HOSTNAME", "")
+}
+
+func TestStringIncludesHostname(t *testing.T) {
+	clearCI(t)
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		t.Skip("no hostname")
+	}
+
+	got := String()
+	want := "CNAP CLI/dev (" + runtime.GOOS + "; " + runtime.GOARCH + "; " + host + ")"
+	if got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestStringOmitsHostnameInCI(t *testing.T) {
+	clearCI(t)
+	t.Setenv("GITHUB_ACTIONS", "true")
+
+	got := String()
+	if host, _ := os.Hostname(); host != "" && strings.Contains(got, host) {
+		t.Errorf("String() = %q, should not contain hostname %q", got, host)
+	}
+	if !strings.Contains(got, "; ci=github-actions; tty=") {
+		t.Errorf("String() = %q, want CI context", got)
+	}
+}
+
+func TestStringExtra(t *testing.T) {
+	clearCI(t)
+	t.Setenv("CNAP_UA_EXTRA", "team=platform")
+
+	if got := String(); !strings.HasSuffix(got, "; team=platform)") {
+		t.Errorf("String() = %q, want CNAP_UA_EXTRA appended", got)
+	}
+}
+
+func TestStringExtraSanitized(t *testing.T) {
+	clearCI(t)
+	t.Setenv("CNAP_UA_EXTRA", " team=platform;\r\nX-Injected: 1 (évil)\x1b ")
+
+	if got := String(); !strings.HasSuffix(got, "; team=platform;X-Injected 1 vil)") {
+		t.Errorf("String() = %q, want CNAP_UA_EXTRA without control or non-token characters", got)
+	}
+
+	t.Setenv("CNAP_UA_EXTRA", strings.Repeat("a", 500))
+	if got := String(); !strings.HasSuffix(got, "; "+strings.Repeat("a", maxExtraLen)+")") {
+		t.Errorf("String() = %q, want CNAP_UA_EXTRA cut to %d bytes", got, maxExtraLen)
+	}
+}
+
+func TestStringHideHostname(t *testing.T) {
+	host, _ := os.Hostname()
+	want := "CNAP CLI/dev (" + runtime.GOOS + "; " + runtime.GOARCH + "; hidden)"
+
+	t.Run("env", func(t *testing.T) {
+		clearCI(t)
+		t.Setenv("CNAP_UA_NO_HOSTNAME", "1")
+		if got := String(); got != want || (host != "" && strings.Contains(got, host)) {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("config", func(t *testing.T) {
+		clearCI(t)
+		SetHideHostname(true)
+		t.Cleanup(func() { SetHideHostname(false) })
+		if got := String(); got != want || (host != "" && strings.Contains(got, host)) {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package wsterm
+
+import (
+	"sync"
+	"time"
+)
+
+// resizeInterval is the least time between resize messages. Dragging a
+// window edge fires size changes far faster than the remote shell needs.
+const resizeInterval = 100 * time.Millisecond
+
+// resizeLimiter rate-limits resize messages: the first change is sent at
+// once, and changes within interval of the last send are coalesced into one
+// send when the interval ends, which reads the size then, so the final size
+// is always sent.
+type resizeLimiter struct {
+	send     func()
+	interval time.Duration
+
+	mu      sync.Mutex
+	last    time.Time
+	timer   *time.Timer // pending trailing send, if any
+	stopped bool
+}
+
+func newResizeLimiter(send func(), interval time.Duration) *resizeLimiter {
+	return &resizeLimiter{send: send, interval: interval}
+}
+
+// changed records a size change.
+func (l *resizeLimiter) changed() {
+	l.mu.Lock()
+	if l.stopped || l.timer != nil {
+		l.mu.Unlock()
+		return
+	}
+	if wait := l.interval - time.Since(l.last); wait > 0 {
+		l.timer = time.AfterFunc(wait, l.flush)
+		l.mu.Unlock()
+		return
+	}
+	l.last = time.Now()
+	l.mu.Unlock()
+	l.send()
+}
+
+func (l *resizeLimiter) flush() {
+	l.mu.Lock()
+	l.timer = nil
+	if l.stopped {
+		l.mu.Unlock()
+		return
+	}
+	l.last = time.Now()
+	l.mu.Unlock()
+	l.send()
+}
+
+// stop drops any pending send.
+func (l *resizeLimiter) stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stopped = true
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+}
@@ -0,0 +1,66 @@
+package wsterm
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMonitorResizeStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Session{ttyFd: -1}
+
+	exited := make(chan struct{})
+	go func() {
+		s.monitorResize(ctx, make(chan struct{}))
+		close(exited)
+	}()
+
+	cancel()
+	select {
+	case <-exited:
+	case <-time.After(2 * time.Second):
+		t.Fatal("monitorResize did not exit after context cancellation")
+	}
+}
+
+func TestResizeLimiterBoundsBurst(t *testing.T) {
+	var mu sync.Mutex
+	sends := 0
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return sends
+	}
+	interval := 50 * time.Millisecond
+	l := newResizeLimiter(func() {
+		mu.Lock()
+		sends++
+		mu.Unlock()
+	}, interval)
+	defer l.stop()
+
+	l.changed()
+	if got := count(); got != 1 {
+		t.Fatalf("first resize: %d sends, want 1 sent immediately", got)
+	}
+
+	// A drag: 100 size changes over about 200ms.
+	start := time.Now()
+	for range 100 {
+		l.changed()
+		time.Sleep(2 * time.Millisecond)
+	}
+	elapsed := time.Since(start)
+
+	// The trailing send carries the final size.
+	time.Sleep(2 * interval)
+	got := count()
+	if limit := 2 + int(elapsed/interval); got > limit {
+		t.Errorf("%d sends for a %v burst, want at most %d", got, elapsed, limit)
+	}
+	if got < 2 {
+		t.Errorf("%d sends, want the final size sent after the burst", got)
+	}
+}
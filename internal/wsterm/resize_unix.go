@@ -1,28 +1,32 @@
 //go:build !windows
 
-package installs
+package wsterm
 
 import (
 	"context"
 	"os"
 	"os/signal"
 	"syscall"
-
-	"github.com/coder/websocket"
 )
 
-// monitorResize listens for SIGWINCH signals and sends resize events over the WebSocket.
-func monitorResize(ctx context.Context, conn *websocket.Conn, stop <-chan struct{}) {
+// monitorResize listens for SIGWINCH signals and sends resize events over
+// the WebSocket, at most one per resizeInterval.
+func (s *Session) monitorResize(ctx context.Context, stop <-chan struct{}) {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGWINCH)
 	defer signal.Stop(sigCh)
 
+	limiter := newResizeLimiter(func() { s.sendSize(ctx) }, resizeInterval)
+	defer limiter.stop()
+
 	for {
 		select {
 		case <-sigCh:
-			sendResize(ctx, conn)
+			limiter.changed()
 		case <-stop:
 			return
+		case <-ctx.Done():
+			return
 		}
 	}
 }
@@ -0,0 +1,41 @@
+package wsterm
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// monitorResize polls terminal size every 250ms and sends resize events when dimensions change.
+// Windows has no SIGWINCH equivalent, so polling is the standard approach (used by kubectl).
+// A failed size read is retried on the next tick rather than ending the monitor.
+// Sends go through the same resizeLimiter as on Unix.
+func (s *Session) monitorResize(ctx context.Context, stop <-chan struct{}) {
+	w, h, err := term.GetSize(s.ttyFd)
+	known := err == nil
+
+	limiter := newResizeLimiter(func() { s.sendSize(ctx) }, resizeInterval)
+	defer limiter.stop()
+
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			newW, newH, err := term.GetSize(s.ttyFd)
+			if err != nil {
+				continue
+			}
+			if !known || newW != w || newH != h {
+				w, h, known = newW, newH, true
+				limiter.changed()
+			}
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Package wsterm bridges a local terminal to a remote shell over the exec
+// WebSocket protocol: JSON text frames carrying input, output, resize, and
+// exit messages.
+package wsterm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/coder/websocket"
+	"golang.org/x/term"
+)
+
+// ErrConnLost marks a session that ended without a close frame (e.g. a
+// reset connection), as opposed to the server closing it deliberately.
+var ErrConnLost = errors.New("connection to pod lost")
+
+// Message is a single protocol frame.
+// Data is plain text unless Encoding is "base64", which is used for input
+// or output that isn't valid UTF-8 and would be mangled by JSON strings.
+type Message struct {
+	Type     string `json:"type"` // input, output, resize, error, exit, close
+	Data     string `json:"data,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Code     *int   `json:"code,omitempty"` // remote exit status, sent with "exit"
+	Cols     int    `json:"cols,omitempty"`
+	Rows     int    `json:"rows,omitempty"`
+}
+
+// Session bridges one WebSocket connection to local output streams.
+type Session struct {
+	conn   *websocket.Conn
+	stdout io.Writer
+	stderr io.Writer
+	ttyFd  int
+}
+
+// NewSession wraps conn. Remote output goes to stdout and protocol errors to
+// stderr. The size of the terminal ttyFd is forwarded on start and whenever
+// it changes; pass -1 to disable resize handling.
+func NewSession(conn *websocket.Conn, stdout, stderr io.Writer, ttyFd int) *Session {
+	return &Session{conn: conn, stdout: stdout, stderr: stderr, ttyFd: ttyFd}
+}
+
+// Run bridges input to the remote shell and remote output to stdout until
+// the remote side exits, the connection closes, or ctx is cancelled.
+// It returns the remote exit status (0 if none was sent). A clean close
+// yields a nil error; a dropped connection wraps ErrConnLost.
+func (s *Session) Run(ctx context.Context, input <-chan []byte) (int, error) {
+	// Send initial terminal size
+	s.sendSize(ctx)
+
+	// Start platform-specific resize monitoring (SIGWINCH on Unix, polling on Windows)
+	resizeStop := make(chan struct{})
+	defer close(resizeStop)
+	if s.ttyFd >= 0 {
+		go s.monitorResize(ctx, resizeStop)
+	}
+
+	done := make(chan struct{})
+	var code int
+	var runErr error
+
+	// Goroutine: read from WebSocket → write to stdout
+	go func() {
+		defer close(done)
+		for {
+			_, data, err := s.conn.Read(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					runErr = closeError(err)
+				}
+				return
+			}
+			var msg Message
+			if json.Unmarshal(data, &msg) != nil {
+				continue
+			}
+			switch msg.Type {
+			case "output":
+				data, err := msg.payload()
+				if err != nil {
+					continue
+				}
+				_, _ = s.stdout.Write(data)
+			case "error":
+				_, _ = fmt.Fprintf(s.stderr, "\r\nError: %s\r\n", msg.Message)
+			case "exit", "close":
+				if msg.Code != nil {
+					code = *msg.Code
+				}
+				return
+			}
+		}
+	}()
+
+	// Goroutine: input → send to WebSocket
+	go func() {
+		for {
+			select {
+			case data, ok := <-input:
+				if !ok {
+					return
+				}
+				if s.send(ctx, inputMessage(data)) != nil {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	<-done
+	return code, runErr
+}
+
+// inputBufferSize is how much input is read at once. Large enough that a
+// typical paste goes out as a single frame, small enough that a base64
+// frame stays under the 32 KiB default WebSocket read limit.
+const inputBufferSize = 16 * 1024
+
+// ReadInput reads r until EOF, sending chunks on out and closing it when done.
+// Chunks never end partway through a UTF-8 sequence, so multibyte text is
+// not split across frames and stays plain text on the wire.
+func ReadInput(r io.Reader, out chan<- []byte) {
+	defer close(out)
+
+	buf := make([]byte, inputBufferSize)
+	pending := 0 // bytes of an incomplete rune carried over from the last read
+	for {
+		n, err := r.Read(buf[pending:])
+		n += pending
+
+		cut := n
+		if err == nil {
+			cut -= incompleteSuffix(buf[:n])
+		}
+		if cut > 0 {
+			chunk := make([]byte, cut)
+			copy(chunk, buf[:cut])
+			out <- chunk
+		}
+		pending = copy(buf, buf[cut:n])
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// incompleteSuffix returns the length of a truncated UTF-8 sequence at the
+// end of b, or 0 if b ends on a rune boundary.
+func incompleteSuffix(b []byte) int {
+	for i := 1; i <= utf8.UTFMax-1 && i <= len(b); i++ {
+		if utf8.RuneStart(b[len(b)-i]) {
+			if utf8.FullRune(b[len(b)-i:]) {
+				return 0
+			}
+			return i
+		}
+	}
+	return 0
+}
+
+// inputMessage frames data as text when possible, falling back to base64
+// for binary input.
+func inputMessage(data []byte) Message {
+	if utf8.Valid(data) {
+		return Message{Type: "input", Data: string(data)}
+	}
+	return Message{Type: "input", Data: base64.StdEncoding.EncodeToString(data), Encoding: "base64"}
+}
+
+// payload returns the decoded Data.
+func (m Message) payload() ([]byte, error) {
+	if m.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(m.Data)
+	}
+	return []byte(m.Data), nil
+}
+
+// Close ends the session with a normal closure.
+func (s *Session) Close() error {
+	return s.conn.Close(websocket.StatusNormalClosure, "")
+}
+
+func (s *Session) send(ctx context.Context, msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.conn.Write(ctx, websocket.MessageText, data)
+}
+
+func (s *Session) sendSize(ctx context.Context) {
+	if s.ttyFd < 0 {
+		return
+	}
+	w, h, err := term.GetSize(s.ttyFd)
+	if err != nil {
+		return
+	}
+	_ = s.send(ctx, Message{Type: "resize", Cols: w, Rows: h})
+}
+
+// closeError maps a WebSocket read error to the error Run returns.
+// A normal close is a clean end of session; anything else (a reset
+// connection, a server-side failure) is reported so scripts see a failure.
+func closeError(err error) error {
+	switch websocket.CloseStatus(err) {
+	case websocket.StatusNormalClosure, websocket.StatusGoingAway:
+		return nil
+	case -1:
+		return fmt.Errorf("%w: %w", ErrConnLost, err)
+	default:
+		var ce websocket.CloseError
+		if errors.As(err, &ce) && ce.Reason != "" {
+			return fmt.Errorf("session closed by server: %s", ce.Reason)
+		}
+		return fmt.Errorf("session closed by server: %w", err)
+	}
+}
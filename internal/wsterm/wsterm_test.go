@@ -0,0 +1,203 @@
+package wsterm
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+
+	"github.com/coder/websocket"
+)
+
+// dialTestServer starts a WebSocket server running handler and returns a
+// client connection to it.
+func dialTestServer(t *testing.T, handler func(ctx context.Context, conn *websocket.Conn)) *websocket.Conn {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			t.Errorf("accept: %v", err)
+			return
+		}
+		defer func() { _ = conn.CloseNow() }()
+		handler(r.Context(), conn)
+	}))
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.Dial(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.CloseNow() })
+	return conn
+}
+
+func send(ctx context.Context, conn *websocket.Conn, msg Message) {
+	data, _ := json.Marshal(msg)
+	_ = conn.Write(ctx, websocket.MessageText, data)
+}
+
+func TestSessionRun(t *testing.T) {
+	conn := dialTestServer(t, func(ctx context.Context, conn *websocket.Conn) {
+		// Echo one input frame back as output, then exit with status 3.
+		_, data, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+		var in Message
+		_ = json.Unmarshal(data, &in)
+		send(ctx, conn, Message{Type: "output", Data: "echo: " + in.Data})
+		send(ctx, conn, Message{Type: "error", Message: "warning"})
+		code := 3
+		send(ctx, conn, Message{Type: "exit", Code: &code})
+		_, _, _ = conn.Read(ctx)
+	})
+
+	var stdout, stderr strings.Builder
+	input := make(chan []byte, 1)
+	input <- []byte("ls")
+
+	code, err := NewSession(conn, &stdout, &stderr, -1).Run(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if code != 3 {
+		t.Errorf("exit code = %d, want 3", code)
+	}
+	if got, want := stdout.String(), "echo: ls"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	if !strings.Contains(stderr.String(), "Error: warning") {
+		t.Errorf("stderr = %q, want protocol error", stderr.String())
+	}
+}
+
+func TestSessionRunServerClose(t *testing.T) {
+	conn := dialTestServer(t, func(ctx context.Context, conn *websocket.Conn) {
+		_ = conn.Close(websocket.StatusInternalError, "pod terminated")
+	})
+
+	_, err := NewSession(conn, io.Discard, io.Discard, -1).Run(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "pod terminated") {
+		t.Errorf("got %v, want server close reason", err)
+	}
+	if errors.Is(err, ErrConnLost) {
+		t.Errorf("server close should not count as a dropped connection")
+	}
+}
+
+func TestCloseError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"normal closure", websocket.CloseError{Code: websocket.StatusNormalClosure}, ""},
+		{"going away", websocket.CloseError{Code: websocket.StatusGoingAway}, ""},
+		{"server error with reason", websocket.CloseError{Code: websocket.StatusInternalError, Reason: "pod terminated"}, "session closed by server: pod terminated"},
+		{"connection reset", io.ErrUnexpectedEOF, "connection to pod lost"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := closeError(tt.err)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("got %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	if err := closeError(io.ErrUnexpectedEOF); !errors.Is(err, ErrConnLost) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("connection errors should wrap ErrConnLost and the cause, got %v", err)
+	}
+}
+
+func TestSessionInputBinaryExact(t *testing.T) {
+	blob := make([]byte, 1<<20)
+	_, _ = rand.Read(blob)
+	// Include text with multibyte runes that will straddle read boundaries.
+	blob = append(blob, bytes.Repeat([]byte("héllo wörld ✓ "), 10000)...)
+
+	received := make(chan []byte, 1)
+	conn := dialTestServer(t, func(ctx context.Context, conn *websocket.Conn) {
+		var got []byte
+		for len(got) < len(blob) {
+			_, data, err := conn.Read(ctx)
+			if err != nil {
+				break
+			}
+			var msg Message
+			_ = json.Unmarshal(data, &msg)
+			if msg.Type != "input" {
+				continue
+			}
+			if msg.Encoding == "" && !utf8.ValidString(msg.Data) {
+				t.Errorf("text frame carries invalid UTF-8")
+			}
+			p, err := msg.payload()
+			if err != nil {
+				t.Errorf("decoding input: %v", err)
+			}
+			got = append(got, p...)
+		}
+		received <- got
+		send(ctx, conn, Message{Type: "exit"})
+		_, _, _ = conn.Read(ctx)
+	})
+
+	// Start with one-byte reads, then full-buffer reads.
+	r := io.MultiReader(iotest.OneByteReader(bytes.NewReader(blob[:100])), bytes.NewReader(blob[100:]))
+	input := make(chan []byte, 64)
+	go ReadInput(r, input)
+
+	if _, err := NewSession(conn, io.Discard, io.Discard, -1).Run(context.Background(), input); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := <-received; !bytes.Equal(got, blob) {
+		t.Errorf("received %d bytes, want %d identical bytes", len(got), len(blob))
+	}
+}
+
+func TestReadInputKeepsRunesWhole(t *testing.T) {
+	text := "aé✓𝄞b"
+	out := make(chan []byte, 16)
+	go ReadInput(iotest.OneByteReader(strings.NewReader(text)), out)
+
+	var got []byte
+	for chunk := range out {
+		if !utf8.Valid(chunk) {
+			t.Errorf("chunk %q splits a rune", chunk)
+		}
+		got = append(got, chunk...)
+	}
+	if string(got) != text {
+		t.Errorf("got %q, want %q", got, text)
+	}
+}
+
+func BenchmarkReadInput(b *testing.B) {
+	data := bytes.Repeat([]byte("paste heavy input ✓\n"), 1<<14)
+	b.SetBytes(int64(len(data)))
+	for b.Loop() {
+		out := make(chan []byte, 64)
+		go ReadInput(bytes.NewReader(data), out)
+		for chunk := range out {
+			_ = inputMessage(chunk)
+		}
+	}
+}